@@ -24,6 +24,7 @@ import (
 
 type ActivitiesService interface {
 	ListActivities(ctx context.Context, request *ListActivitiesRequest) (*ListActivitiesResponse, error)
+	ListEntityActivities(ctx context.Context, request *ListEntityActivitiesRequest) (*ListEntityActivitiesResponse, error)
 	GetActivity(ctx context.Context, request *GetActivityRequest) (*GetActivityResponse, error)
 }
 
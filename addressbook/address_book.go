@@ -24,7 +24,14 @@ import (
 
 type AddressBookService interface {
 	GetAddressBook(ctx context.Context, request *GetAddressBookRequest) (*GetAddressBookResponse, error)
+	ListAllAddressBookEntries(ctx context.Context, request *ListAllAddressBookEntriesRequest) (*ListAllAddressBookEntriesResponse, error)
 	CreateAddressBookEntry(ctx context.Context, request *CreateAddressBookEntryRequest) (*CreateAddressBookEntryResponse, error)
+	DeleteAddressBookEntry(ctx context.Context, request *DeleteAddressBookEntryRequest) (*DeleteAddressBookEntryResponse, error)
+	TrackAddressBookEntry(ctx context.Context, request *TrackAddressBookEntryRequest) (*TrackAddressBookEntryResponse, error)
+	ListAddressGroups(ctx context.Context, request *ListAddressGroupsRequest) (*ListAddressGroupsResponse, error)
+	CreateAddressGroup(ctx context.Context, request *CreateAddressGroupRequest) (*CreateAddressGroupResponse, error)
+	UpdateAddressGroup(ctx context.Context, request *UpdateAddressGroupRequest) (*UpdateAddressGroupResponse, error)
+	UpdateAddressGroupMembership(ctx context.Context, request *UpdateAddressGroupMembershipRequest) (*UpdateAddressGroupMembershipResponse, error)
 }
 
 func NewAddressBookService(c client.RestClient) AddressBookService {
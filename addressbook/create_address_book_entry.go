@@ -46,6 +46,10 @@ func (s *addressBookServiceImpl) CreateAddressBookEntry(
 
 	path := fmt.Sprintf("/portfolios/%s/address_book", request.PortfolioId)
 
+	if err := client.RunRequestHooks(ctx, s.client, request); err != nil {
+		return nil, err
+	}
+
 	response := &CreateAddressBookEntryResponse{Request: request}
 
 	if err := core.HttpPost(
@@ -58,8 +62,11 @@ func (s *addressBookServiceImpl) CreateAddressBookEntry(
 		response,
 		s.client.HeadersFunc(),
 	); err != nil {
+		client.RunResponseHooks(ctx, s.client, request, err)
 		return nil, err
 	}
 
+	client.RunResponseHooks(ctx, s.client, request, nil)
+
 	return response, nil
 }
@@ -39,6 +39,10 @@ type GetAddressBookResponse struct {
 	Pagination *model.Pagination         `json:"pagination"`
 }
 
+func (r GetAddressBookResponse) HasNext() bool {
+	return r.Pagination != nil && r.Pagination.HasNext
+}
+
 func (s *addressBookServiceImpl) GetAddressBook(
 	ctx context.Context,
 	request *GetAddressBookRequest,
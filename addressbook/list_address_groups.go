@@ -0,0 +1,70 @@
+/**
+ * Copyright 2024-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package addressbook
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/coinbase-samples/core-go"
+	"github.com/coinbase-samples/prime-sdk-go/client"
+	"github.com/coinbase-samples/prime-sdk-go/model"
+)
+
+type ListAddressGroupsRequest struct {
+	PortfolioId string `json:"portfolio_id"`
+}
+
+type ListAddressGroupsResponse struct {
+	AddressGroups []*model.AddressGroup     `json:"address_groups"`
+	Request       *ListAddressGroupsRequest `json:"request"`
+}
+
+// AddressGroup returns the group with the id.
+func (r ListAddressGroupsResponse) AddressGroup(id string) (*model.AddressGroup, bool) {
+	for _, g := range r.AddressGroups {
+		if g.Id == id {
+			return g, true
+		}
+	}
+	return nil, false
+}
+
+func (s *addressBookServiceImpl) ListAddressGroups(
+	ctx context.Context,
+	request *ListAddressGroupsRequest,
+) (*ListAddressGroupsResponse, error) {
+
+	path := fmt.Sprintf("/portfolios/%s/address_groups", request.PortfolioId)
+
+	response := &ListAddressGroupsResponse{Request: request}
+
+	if err := core.HttpGet(
+		ctx,
+		s.client,
+		path,
+		core.EmptyQueryParams,
+		client.DefaultSuccessHttpStatusCodes,
+		request,
+		response,
+		s.client.HeadersFunc(),
+	); err != nil {
+		return nil, err
+	}
+
+	return response, nil
+}
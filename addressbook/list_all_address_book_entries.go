@@ -0,0 +1,76 @@
+/**
+ * Copyright 2024-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package addressbook
+
+import (
+	"context"
+	"errors"
+
+	"github.com/coinbase-samples/prime-sdk-go/model"
+)
+
+type ListAllAddressBookEntriesRequest struct {
+	PortfolioId string `json:"portfolio_id"`
+	Symbol      string `json:"currency_symbol"`
+	Search      string `json:"search"`
+}
+
+type ListAllAddressBookEntriesResponse struct {
+	Addresses []*model.AddressBookEntry         `json:"addresses"`
+	Request   *ListAllAddressBookEntriesRequest `json:"request"`
+}
+
+// ListAllAddressBookEntries follows the GetAddressBook pagination and returns
+// every matching entry. A page that reports a next page without a cursor is
+// an error rather than an endless loop.
+func (s *addressBookServiceImpl) ListAllAddressBookEntries(
+	ctx context.Context,
+	request *ListAllAddressBookEntriesRequest,
+) (*ListAllAddressBookEntriesResponse, error) {
+
+	if len(request.PortfolioId) == 0 {
+		return nil, errors.New("portfolio id not set on request")
+	}
+
+	response := &ListAllAddressBookEntriesResponse{Request: request}
+
+	pagination := &model.PaginationParams{}
+
+	for {
+		page, err := s.GetAddressBook(ctx, &GetAddressBookRequest{
+			PortfolioId: request.PortfolioId,
+			Symbol:      request.Symbol,
+			Search:      request.Search,
+			Pagination:  pagination,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		response.Addresses = append(response.Addresses, page.Addresses...)
+
+		if !page.HasNext() {
+			return response, nil
+		}
+
+		if len(page.Pagination.NextCursor) == 0 {
+			return nil, errors.New("address book has a next page without a cursor")
+		}
+
+		pagination = &model.PaginationParams{Cursor: page.Pagination.NextCursor}
+	}
+}
@@ -0,0 +1,91 @@
+/**
+ * Copyright 2024-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package addressbook
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/coinbase-samples/prime-sdk-go/client"
+	"github.com/coinbase-samples/prime-sdk-go/credentials"
+)
+
+func TestListAllAddressBookEntries(t *testing.T) {
+
+	cases := []struct {
+		description string
+		pages       map[string]string
+		entries     int
+		err         bool
+	}{
+		{
+			description: "TestListAllAddressBookEntries0",
+			pages: map[string]string{
+				"":   `{"addresses":[{"id":"e0"}],"pagination":{"next_cursor":"c1","has_next":true}}`,
+				"c1": `{"addresses":[{"id":"e1"}],"pagination":{"has_next":false}}`,
+			},
+			entries: 2,
+		},
+		{
+			description: "TestListAllAddressBookEntries1",
+			pages: map[string]string{
+				"": `{"addresses":[{"id":"e0"}],"pagination":{"next_cursor":"","has_next":true}}`,
+			},
+			err: true,
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.description, func(t *testing.T) {
+
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				page, ok := tt.pages[r.URL.Query().Get("cursor")]
+				if !ok {
+					t.Errorf("test: %s - unexpected cursor: %s", tt.description, r.URL.Query().Get("cursor"))
+					http.NotFound(w, r)
+					return
+				}
+				w.Write([]byte(page))
+			}))
+			defer server.Close()
+
+			svc := NewAddressBookService(client.NewRestClient(&credentials.Credentials{}, http.Client{}).SetBaseUrl(server.URL))
+
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+
+			response, err := svc.ListAllAddressBookEntries(ctx, &ListAllAddressBookEntriesRequest{PortfolioId: "p1"})
+			if tt.err {
+				if err == nil {
+					t.Errorf("test: %s - expected an error", tt.description)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if len(response.Addresses) != tt.entries {
+				t.Errorf("test: %s - expected: %d - received: %d", tt.description, tt.entries, len(response.Addresses))
+			}
+		})
+	}
+}
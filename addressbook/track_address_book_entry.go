@@ -0,0 +1,151 @@
+/**
+ * Copyright 2024-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package addressbook
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/coinbase-samples/prime-sdk-go/activities"
+	"github.com/coinbase-samples/prime-sdk-go/client"
+	"github.com/coinbase-samples/prime-sdk-go/model"
+)
+
+const defaultTrackPollInterval = 5 * time.Second
+
+type TrackAddressBookEntryRequest struct {
+	PortfolioId string `json:"portfolio_id"`
+
+	// The activity id returned when the entry was created or deleted
+	ActivityId string `json:"activity_id"`
+
+	// How often the activity is polled. Defaults to 5s. Use a context
+	// deadline to bound the wait.
+	PollInterval time.Duration `json:"poll_interval"`
+}
+
+type TrackAddressBookEntryResponse struct {
+	// The activity in its terminal state
+	Activity *model.Activity `json:"activity"`
+
+	// The entry the activity refers to. Nil if it is not in the address book,
+	// e.g., the addition was rejected.
+	Entry *model.AddressBookEntry `json:"entry"`
+
+	Request *TrackAddressBookEntryRequest `json:"request"`
+}
+
+// State returns the state of the entry. If the entry is not in the address
+// book, the state is derived from the activity status: rejected if the
+// activity did not complete. It is empty if the activity completed but the
+// entry is not listed, e.g., it was removed or is not visible yet, since the
+// activity alone does not tell which.
+func (r TrackAddressBookEntryResponse) State() string {
+	if r.Entry != nil {
+		return r.Entry.State
+	}
+
+	switch r.Activity.Status {
+	case model.ActivityStatusCompleted:
+		return ""
+	case model.ActivityStatusProcessing:
+		return model.AddressBookStatePendingApproval
+	}
+
+	return model.AddressBookStateRejected
+}
+
+// TrackAddressBookEntry polls the activity of an address book change until it
+// completes or is rejected, cancelled or expires, and then looks up the entry
+// it refers to.
+func (s *addressBookServiceImpl) TrackAddressBookEntry(
+	ctx context.Context,
+	request *TrackAddressBookEntryRequest,
+) (*TrackAddressBookEntryResponse, error) {
+
+	if len(request.ActivityId) == 0 {
+		return nil, errors.New("activity id not set on request")
+	}
+
+	interval := request.PollInterval
+	if interval <= 0 {
+		interval = defaultTrackPollInterval
+	}
+
+	activity, err := s.waitForTerminalActivity(ctx, request.PortfolioId, request.ActivityId, interval)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &TrackAddressBookEntryResponse{Activity: activity, Request: request}
+
+	if len(activity.ReferenceId) == 0 {
+		return response, nil
+	}
+
+	entries, err := s.ListAllAddressBookEntries(ctx, &ListAllAddressBookEntriesRequest{PortfolioId: request.PortfolioId})
+	if err != nil {
+		return response, fmt.Errorf("unable to look up address book entry: %s - err: %w", activity.ReferenceId, err)
+	}
+
+	for _, e := range entries.Addresses {
+		if e.Id == activity.ReferenceId {
+			response.Entry = e
+			break
+		}
+	}
+
+	return response, nil
+}
+
+func (s *addressBookServiceImpl) waitForTerminalActivity(
+	ctx context.Context,
+	portfolioId,
+	activityId string,
+	interval time.Duration,
+) (*model.Activity, error) {
+
+	svc := activities.NewActivitiesService(s.client)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	var lastErr error
+
+	for {
+		response, err := svc.GetActivity(ctx, &activities.GetActivityRequest{PortfolioId: portfolioId, Id: activityId})
+		switch {
+		case err == nil && response.Activity != nil && response.Activity.IsTerminal():
+			return response.Activity, nil
+		case err != nil && ctx.Err() == nil && !client.IsRetryable(err):
+			return nil, fmt.Errorf("unable to get activity: %s - err: %w", activityId, err)
+		case err != nil:
+			lastErr = err
+		}
+
+		select {
+		case <-ctx.Done():
+			if lastErr != nil {
+				return nil, fmt.Errorf("activity not completed: %s - last err: %v - err: %w", activityId, lastErr, ctx.Err())
+			}
+			return nil, fmt.Errorf("activity not completed: %s - err: %w", activityId, ctx.Err())
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,139 @@
+/**
+ * Copyright 2024-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package addressbook
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/coinbase-samples/prime-sdk-go/client"
+	"github.com/coinbase-samples/prime-sdk-go/credentials"
+	"github.com/coinbase-samples/prime-sdk-go/model"
+)
+
+func TestTrackAddressBookEntry(t *testing.T) {
+
+	cases := []struct {
+		description string
+		activities  []string
+		codes       []int
+		state       string
+		err         bool
+	}{
+		{
+			description: "TestTrackAddressBookEntry0",
+			activities: []string{
+				`{"activity":{"id":"a1","reference_id":"e1","status":"ACTIVITY_STATUS_PROCESSING"}}`,
+				`{"activity":{"id":"a1","reference_id":"e1","status":"ACTIVITY_STATUS_COMPLETED"}}`,
+			},
+			state: model.AddressBookStateActive,
+		},
+		{
+			description: "TestTrackAddressBookEntry1",
+			activities: []string{
+				`{"activity":{"id":"a1","reference_id":"e9","status":"ACTIVITY_STATUS_CANCELLED"}}`,
+			},
+			state: model.AddressBookStateRejected,
+		},
+		{
+			description: "TestTrackAddressBookEntry2",
+			activities: []string{
+				`{"activity":{"id":"a1","reference_id":"e9","status":"ACTIVITY_STATUS_COMPLETED"}}`,
+			},
+		},
+		{
+			description: "TestTrackAddressBookEntry3",
+			activities: []string{
+				`{"message":"unavailable"}`,
+				`{"activity":{"id":"a1","reference_id":"e1","status":"ACTIVITY_STATUS_COMPLETED"}}`,
+			},
+			codes: []int{http.StatusServiceUnavailable, http.StatusOK},
+			state: model.AddressBookStateActive,
+		},
+		{
+			description: "TestTrackAddressBookEntry4",
+			activities: []string{
+				`{"message":"not found"}`,
+			},
+			codes: []int{http.StatusNotFound},
+			err:   true,
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.description, func(t *testing.T) {
+
+			var polls int
+
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				switch r.URL.Path {
+				case "/portfolios/p1/activities/a1":
+					i := polls
+					if i >= len(tt.activities) {
+						i = len(tt.activities) - 1
+					}
+					polls++
+					if i < len(tt.codes) {
+						w.WriteHeader(tt.codes[i])
+					}
+					w.Write([]byte(tt.activities[i]))
+				case "/portfolios/p1/address_book":
+					if r.URL.Query().Get("cursor") == "" {
+						w.Write([]byte(`{"addresses":[{"id":"e0","state":"ACTIVE"}],"pagination":{"next_cursor":"c1","has_next":true}}`))
+						return
+					}
+					w.Write([]byte(`{"addresses":[{"id":"e1","state":"ACTIVE"}],"pagination":{"has_next":false}}`))
+				default:
+					t.Errorf("test: %s - unexpected path: %s", tt.description, r.URL.Path)
+				}
+			}))
+			defer server.Close()
+
+			svc := NewAddressBookService(client.NewRestClient(&credentials.Credentials{}, http.Client{}).SetBaseUrl(server.URL))
+
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+
+			response, err := svc.TrackAddressBookEntry(ctx, &TrackAddressBookEntryRequest{
+				PortfolioId:  "p1",
+				ActivityId:   "a1",
+				PollInterval: time.Millisecond,
+			})
+			if tt.err {
+				if err == nil || polls != 1 {
+					t.Errorf("test: %s - expected an error after one poll - received: %d polls - err: %v", tt.description, polls, err)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if polls != len(tt.activities) {
+				t.Errorf("test: %s - expected: %d polls - received: %d", tt.description, len(tt.activities), polls)
+			}
+
+			if response.State() != tt.state {
+				t.Errorf("test: %s - expected: %s - received: %s", tt.description, tt.state, response.State())
+			}
+		})
+	}
+}
@@ -0,0 +1,87 @@
+/**
+ * Copyright 2024-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package addressbook
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/coinbase-samples/prime-sdk-go/model"
+)
+
+type UpdateAddressGroupMembershipRequest struct {
+	PortfolioId    string                     `json:"portfolio_id"`
+	AddressGroupId string                     `json:"address_group_id"`
+	Add            []*model.AddressGroupEntry `json:"add"`
+
+	// The addresses to remove
+	Remove []string `json:"remove"`
+}
+
+type UpdateAddressGroupMembershipResponse struct {
+	// The group as submitted for approval
+	AddressGroup *model.AddressGroup `json:"address_group"`
+	Added        int                 `json:"added"`
+	Removed      int                 `json:"removed"`
+
+	// Empty if the membership did not change and no update was requested
+	ActivityId string `json:"activity_id"`
+
+	Request *UpdateAddressGroupMembershipRequest `json:"request"`
+}
+
+// UpdateAddressGroupMembership adds and removes addresses from the current
+// membership of a group and requests the update. Addresses that are already
+// members are not added twice. If nothing changes, no update is requested.
+func (s *addressBookServiceImpl) UpdateAddressGroupMembership(
+	ctx context.Context,
+	request *UpdateAddressGroupMembershipRequest,
+) (*UpdateAddressGroupMembershipResponse, error) {
+
+	if len(request.AddressGroupId) == 0 {
+		return nil, errors.New("address group id not set on request")
+	}
+
+	groups, err := s.ListAddressGroups(ctx, &ListAddressGroupsRequest{PortfolioId: request.PortfolioId})
+	if err != nil {
+		return nil, fmt.Errorf("unable to list address groups: %w", err)
+	}
+
+	group, ok := groups.AddressGroup(request.AddressGroupId)
+	if !ok {
+		return nil, fmt.Errorf("address group not found: %s", request.AddressGroupId)
+	}
+
+	response := &UpdateAddressGroupMembershipResponse{AddressGroup: group, Request: request}
+
+	response.Removed = group.Remove(request.Remove...)
+	response.Added = group.Add(request.Add...)
+
+	if response.Added == 0 && response.Removed == 0 {
+		return response, nil
+	}
+
+	updated, err := s.UpdateAddressGroup(ctx, &UpdateAddressGroupRequest{PortfolioId: request.PortfolioId, AddressGroup: group})
+	if err != nil {
+		return nil, fmt.Errorf("unable to update address group: %s - err: %w", request.AddressGroupId, err)
+	}
+
+	response.ActivityId = updated.ActivityId
+
+	return response, nil
+}
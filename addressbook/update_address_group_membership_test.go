@@ -0,0 +1,130 @@
+/**
+ * Copyright 2024-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package addressbook
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/coinbase-samples/prime-sdk-go/client"
+	"github.com/coinbase-samples/prime-sdk-go/credentials"
+	"github.com/coinbase-samples/prime-sdk-go/model"
+)
+
+const (
+	testEvmAddress      = "0x5aaeb6053f3e94c9b9a09f33669435e7ef1beaed"
+	testChecksumAddress = "0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed"
+)
+
+func TestUpdateAddressGroupMembership(t *testing.T) {
+
+	cases := []struct {
+		description string
+		add         []*model.AddressGroupEntry
+		remove      []string
+		expected    []string
+		updated     bool
+	}{
+		{
+			description: "TestUpdateAddressGroupMembership0",
+			add:         []*model.AddressGroupEntry{{Name: "c", Address: "0xc"}},
+			remove:      []string{"0xa"},
+			expected:    []string{"0xb", testEvmAddress, "0xc"},
+			updated:     true,
+		},
+		{
+			description: "TestUpdateAddressGroupMembership1",
+			add:         []*model.AddressGroupEntry{{Name: "b", Address: "0xb"}},
+			remove:      []string{"0xz"},
+			expected:    []string{"0xa", "0xb", testEvmAddress},
+		},
+		{
+			description: "TestUpdateAddressGroupMembership2",
+			add:         []*model.AddressGroupEntry{{Name: "d", Address: testChecksumAddress}},
+			expected:    []string{"0xa", "0xb", testEvmAddress},
+		},
+		{
+			description: "TestUpdateAddressGroupMembership3",
+			remove:      []string{testChecksumAddress},
+			expected:    []string{"0xa", "0xb"},
+			updated:     true,
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.description, func(t *testing.T) {
+
+			var submitted *UpdateAddressGroupRequest
+
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				switch r.Method {
+				case http.MethodGet:
+					w.Write([]byte(`{"address_groups":[{"uuid":"g1","name":"evm","network_type":"NETWORK_TYPE_EVM","addresses":[{"name":"a","address":"0xa"},{"name":"b","address":"0xb"},{"name":"d","address":"` + testEvmAddress + `"}]}]}`))
+				case http.MethodPut:
+					submitted = &UpdateAddressGroupRequest{}
+					if err := json.NewDecoder(r.Body).Decode(submitted); err != nil {
+						t.Error(err)
+					}
+					w.Write([]byte(`{"activity_id":"a1"}`))
+				}
+			}))
+			defer server.Close()
+
+			svc := NewAddressBookService(client.NewRestClient(&credentials.Credentials{}, http.Client{}).SetBaseUrl(server.URL))
+
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+
+			response, err := svc.UpdateAddressGroupMembership(ctx, &UpdateAddressGroupMembershipRequest{
+				PortfolioId:    "p1",
+				AddressGroupId: "g1",
+				Add:            tt.add,
+				Remove:         tt.remove,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if (submitted != nil) != tt.updated || (len(response.ActivityId) > 0) != tt.updated {
+				t.Fatalf("test: %s - expected update: %v", tt.description, tt.updated)
+			}
+
+			var addresses []string
+			for _, a := range response.AddressGroup.Addresses {
+				addresses = append(addresses, a.Address)
+			}
+
+			if len(addresses) != len(tt.expected) {
+				t.Fatalf("test: %s - expected: %v - received: %v", tt.description, tt.expected, addresses)
+			}
+
+			for i := range addresses {
+				if addresses[i] != tt.expected[i] {
+					t.Errorf("test: %s - expected: %v - received: %v", tt.description, tt.expected, addresses)
+				}
+			}
+
+			if submitted != nil && len(submitted.AddressGroup.Addresses) != len(tt.expected) {
+				t.Errorf("test: %s - expected: %d submitted addresses - received: %d", tt.description, len(tt.expected), len(submitted.AddressGroup.Addresses))
+			}
+		})
+	}
+}
@@ -0,0 +1,136 @@
+/**
+ * Copyright 2024-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package addressbooksync
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/coinbase-samples/prime-sdk-go/addressvalidation"
+	"gopkg.in/yaml.v3"
+)
+
+// Config is the desired address book of a portfolio, e.g.:
+//
+//	portfolio_id: 4f9a0e7c-...
+//	addresses:
+//	  - name: Treasury cold storage
+//	    symbol: ETH
+//	    address: "0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed"
+//	  - name: Exchange deposit
+//	    symbol: XRP
+//	    address: rHb9CJAWyB4rj91VRWn96DkukG4bwdtyTh
+//	    account_identifier: "12345"
+type Config struct {
+	PortfolioId string          `yaml:"portfolio_id"`
+	Addresses   []*DesiredEntry `yaml:"addresses"`
+}
+
+type DesiredEntry struct {
+	Name              string `yaml:"name"`
+	Symbol            string `yaml:"symbol"`
+	Address           string `yaml:"address"`
+	AccountIdentifier string `yaml:"account_identifier"`
+}
+
+func (e DesiredEntry) String() string {
+	if len(e.AccountIdentifier) > 0 {
+		return fmt.Sprintf("%s %s (%s) - %s", e.Symbol, e.Address, e.AccountIdentifier, e.Name)
+	}
+	return fmt.Sprintf("%s %s - %s", e.Symbol, e.Address, e.Name)
+}
+
+// LoadConfig reads a YAML config. Unknown fields, missing values and
+// duplicate addresses are errors.
+func LoadConfig(r io.Reader) (*Config, error) {
+
+	decoder := yaml.NewDecoder(r)
+	decoder.KnownFields(true)
+
+	config := &Config{}
+	if err := decoder.Decode(config); err != nil {
+		return nil, fmt.Errorf("unable to deserialize address book config: %w", err)
+	}
+
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+
+	return config, nil
+}
+
+// LoadConfigFile reads a YAML config from a file.
+func LoadConfigFile(path string) (*Config, error) {
+
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("unable to open address book config: %w", err)
+	}
+	defer f.Close()
+
+	return LoadConfig(f)
+}
+
+// Validate checks that the portfolio and every entry are set and that no
+// address is listed twice.
+func (c Config) Validate() error {
+
+	if len(c.PortfolioId) == 0 {
+		return errors.New("portfolio_id not set")
+	}
+
+	seen := make(map[entryKey]int)
+
+	for i, e := range c.Addresses {
+
+		if e == nil || len(e.Name) == 0 || len(e.Symbol) == 0 || len(e.Address) == 0 {
+			return fmt.Errorf("address %d: name, symbol and address are required", i)
+		}
+
+		k := e.key()
+		if j, ok := seen[k]; ok {
+			return fmt.Errorf("address %d: duplicate of address %d: %s", i, j, e)
+		}
+		seen[k] = i
+	}
+
+	return nil
+}
+
+// entryKey identifies an address book entry. Names are labels and are not
+// part of the identity. Addresses are compared in their canonical case, so
+// an EVM address with and without its checksum casing is the same entry.
+type entryKey struct {
+	symbol            string
+	address           string
+	accountIdentifier string
+}
+
+func (e DesiredEntry) key() entryKey {
+	return newEntryKey(e.Symbol, e.Address, e.AccountIdentifier)
+}
+
+func newEntryKey(symbol, address, accountIdentifier string) entryKey {
+	return entryKey{
+		symbol:            strings.ToUpper(strings.TrimSpace(symbol)),
+		address:           addressvalidation.CanonicalAddress(address),
+		accountIdentifier: strings.TrimSpace(accountIdentifier),
+	}
+}
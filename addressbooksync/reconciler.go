@@ -0,0 +1,220 @@
+/**
+ * Copyright 2024-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package addressbooksync
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/coinbase-samples/prime-sdk-go/addressbook"
+	"github.com/coinbase-samples/prime-sdk-go/model"
+)
+
+type Mode int
+
+const (
+	// DryRun plans the changes without sending any request
+	DryRun Mode = iota
+
+	// Apply creates the missing entries
+	Apply
+)
+
+// Plan is the difference between the desired and the remote address book.
+type Plan struct {
+	PortfolioId string
+
+	// Desired entries that are not in the remote address book, or were
+	// rejected or archived. Apply creates them.
+	Create []*DesiredEntry
+
+	// Desired entries that are in the remote address book but not active
+	// yet, because the addition is awaiting approval. They are not created
+	// again.
+	PendingApproval []*PlannedEntry
+
+	// Remote entries that are not desired. They are reported only; removals
+	// are left to a reviewed, manual change.
+	UnexpectedRemote []*model.AddressBookEntry
+
+	// Desired entries that are active in the remote address book
+	InSync []*PlannedEntry
+}
+
+// PlannedEntry is a desired entry and its remote counterpart.
+type PlannedEntry struct {
+	Desired *DesiredEntry
+	Remote  *model.AddressBookEntry
+}
+
+// Empty returns true if there is nothing to create and nothing unexpected.
+func (p Plan) Empty() bool {
+	return len(p.Create) == 0 && len(p.UnexpectedRemote) == 0
+}
+
+type Report struct {
+	Plan    *Plan
+	Mode    Mode
+	Results []*CreateResult
+}
+
+// Failed returns the results of entries that could not be created.
+func (r Report) Failed() []*CreateResult {
+	var failed []*CreateResult
+	for _, res := range r.Results {
+		if res.Err != nil {
+			failed = append(failed, res)
+		}
+	}
+	return failed
+}
+
+type CreateResult struct {
+	Entry      *DesiredEntry
+	ActivityId string
+	Err        error
+}
+
+// Reconciler keeps the address book of a portfolio in sync with a desired
+// set of entries.
+type Reconciler struct {
+	service addressbook.AddressBookService
+}
+
+func NewReconciler(s addressbook.AddressBookService) *Reconciler {
+	return &Reconciler{service: s}
+}
+
+// Plan lists the complete remote address book and diffs it against the
+// config.
+func (r *Reconciler) Plan(ctx context.Context, config *Config) (*Plan, error) {
+
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+
+	remote, err := r.service.ListAllAddressBookEntries(ctx, &addressbook.ListAllAddressBookEntriesRequest{
+		PortfolioId: config.PortfolioId,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("unable to list address book: %w", err)
+	}
+
+	return diff(config, remote.Addresses), nil
+}
+
+// Apply creates the entries in the plan, unless the mode is DryRun. A failure
+// to create one entry does not stop the others. Apply is idempotent across
+// runs: once created, an entry is pending approval and the next plan lists it
+// as pending approval rather than as one to create.
+func (r *Reconciler) Apply(ctx context.Context, plan *Plan, mode Mode) *Report {
+
+	report := &Report{Plan: plan, Mode: mode}
+
+	if mode == DryRun {
+		return report
+	}
+
+	for _, e := range plan.Create {
+
+		result := &CreateResult{Entry: e}
+
+		response, err := r.service.CreateAddressBookEntry(ctx, &addressbook.CreateAddressBookEntryRequest{
+			PortfolioId:       plan.PortfolioId,
+			Name:              e.Name,
+			Symbol:            e.Symbol,
+			Address:           e.Address,
+			AccountIdentifier: e.AccountIdentifier,
+		})
+		if err != nil {
+			result.Err = err
+		} else {
+			result.ActivityId = response.ActivityId
+		}
+
+		report.Results = append(report.Results, result)
+	}
+
+	return report
+}
+
+// Sync plans and applies the config.
+func (r *Reconciler) Sync(ctx context.Context, config *Config, mode Mode) (*Report, error) {
+
+	plan, err := r.Plan(ctx, config)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.Apply(ctx, plan, mode), nil
+}
+
+func diff(config *Config, remote []*model.AddressBookEntry) *Plan {
+
+	plan := &Plan{PortfolioId: config.PortfolioId}
+
+	// More than one remote entry can share a key, e.g., a rejected entry
+	// that was added again. The live one is preferred.
+	byKey := make(map[entryKey]*model.AddressBookEntry)
+	for _, e := range remote {
+		k := newEntryKey(e.Symbol, e.Address, e.AccountIdentifier)
+		if current, ok := byKey[k]; !ok || !isLive(current) {
+			byKey[k] = e
+		}
+	}
+
+	desired := make(map[entryKey]bool)
+
+	for _, e := range config.Addresses {
+
+		k := e.key()
+		desired[k] = true
+
+		match, ok := byKey[k]
+
+		switch {
+		case !ok || !isLive(match):
+			plan.Create = append(plan.Create, e)
+		case match.State == model.AddressBookStateActive:
+			plan.InSync = append(plan.InSync, &PlannedEntry{Desired: e, Remote: match})
+		default:
+			plan.PendingApproval = append(plan.PendingApproval, &PlannedEntry{Desired: e, Remote: match})
+		}
+	}
+
+	for k, e := range byKey {
+		if !desired[k] && isLive(e) {
+			plan.UnexpectedRemote = append(plan.UnexpectedRemote, e)
+		}
+	}
+
+	sort.Slice(plan.UnexpectedRemote, func(i, j int) bool {
+		a, b := plan.UnexpectedRemote[i], plan.UnexpectedRemote[j]
+		if a.Symbol != b.Symbol {
+			return a.Symbol < b.Symbol
+		}
+		return a.Address < b.Address
+	})
+
+	return plan
+}
+
+// isLive returns true if the entry is active or awaiting approval.
+func isLive(e *model.AddressBookEntry) bool {
+	return e.State != model.AddressBookStateRejected && e.State != model.AddressBookStateArchived
+}
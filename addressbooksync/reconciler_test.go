@@ -0,0 +1,176 @@
+/**
+ * Copyright 2024-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package addressbooksync
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/coinbase-samples/prime-sdk-go/addressbook"
+	"github.com/coinbase-samples/prime-sdk-go/client"
+	"github.com/coinbase-samples/prime-sdk-go/credentials"
+)
+
+const testConfig = `
+portfolio_id: p1
+addresses:
+  - name: Treasury
+    symbol: ETH
+    address: "0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed"
+  - name: Cold storage
+    symbol: BTC
+    address: bc1qw508d6qejxtdg4y5r3zarvary0c5xw7kv8f3t4
+  - name: Exchange
+    symbol: XRP
+    address: rHb9CJAWyB4rj91VRWn96DkukG4bwdtyTh
+    account_identifier: "12345"
+  - name: Market maker
+    symbol: SOL
+    address: TokenkegQfeZyiNwAJbNbGKPFXCWuBvf9Ss623VQ5DA
+`
+
+func TestSync(t *testing.T) {
+
+	config, err := LoadConfig(strings.NewReader(testConfig))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		description      string
+		mode             Mode
+		create           int
+		pendingApproval  int
+		unexpectedRemote int
+		inSync           int
+		sent             int
+	}{
+		{
+			description:      "TestSync0",
+			mode:             DryRun,
+			create:           2,
+			pendingApproval:  1,
+			unexpectedRemote: 1,
+			inSync:           1,
+		},
+		{
+			description:      "TestSync1",
+			mode:             Apply,
+			create:           2,
+			pendingApproval:  1,
+			unexpectedRemote: 1,
+			inSync:           1,
+			sent:             2,
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.description, func(t *testing.T) {
+
+			var created []*addressbook.CreateAddressBookEntryRequest
+
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				switch {
+				case r.Method == http.MethodPost:
+					request := &addressbook.CreateAddressBookEntryRequest{}
+					if err := json.NewDecoder(r.Body).Decode(request); err != nil {
+						t.Error(err)
+					}
+					created = append(created, request)
+					w.Write([]byte(`{"activity_id":"a1"}`))
+				case r.URL.Query().Get("cursor") == "":
+					w.Write([]byte(`{"addresses":[
+						{"id":"e1","currency_symbol":"ETH","address":"0x5aaeb6053f3e94c9b9a09f33669435e7ef1beaed","state":"ACTIVE"},
+						{"id":"e2","currency_symbol":"XRP","address":"rHb9CJAWyB4rj91VRWn96DkukG4bwdtyTh","account_identifier":"12345","state":"PENDING_APPROVAL"}
+					],"pagination":{"next_cursor":"c1","has_next":true}}`))
+				default:
+					w.Write([]byte(`{"addresses":[
+						{"id":"e3","currency_symbol":"SOL","address":"TokenkegQfeZyiNwAJbNbGKPFXCWuBvf9Ss623VQ5DA","state":"REJECTED"},
+						{"id":"e4","currency_symbol":"USDC","address":"0xfB6916095ca1df60bB79Ce92cE3Ea74c37c5d359","state":"ACTIVE"}
+					],"pagination":{"has_next":false}}`))
+				}
+			}))
+			defer server.Close()
+
+			c := client.NewRestClient(&credentials.Credentials{}, http.Client{}).SetBaseUrl(server.URL)
+
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+
+			report, err := NewReconciler(addressbook.NewAddressBookService(c)).Sync(ctx, config, tt.mode)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			plan := report.Plan
+
+			if len(plan.Create) != tt.create ||
+				len(plan.PendingApproval) != tt.pendingApproval ||
+				len(plan.UnexpectedRemote) != tt.unexpectedRemote ||
+				len(plan.InSync) != tt.inSync {
+				t.Errorf("test: %s - unexpected plan - create: %d - pending approval: %d - unexpected remote: %d - in sync: %d",
+					tt.description, len(plan.Create), len(plan.PendingApproval), len(plan.UnexpectedRemote), len(plan.InSync))
+			}
+
+			if len(created) != tt.sent || len(report.Results) != tt.sent || len(report.Failed()) != 0 {
+				t.Errorf("test: %s - expected: %d created - received: %d", tt.description, tt.sent, len(created))
+			}
+
+			if tt.sent > 0 && (created[0].Symbol != "BTC" || created[1].Symbol != "SOL") {
+				t.Errorf("test: %s - unexpected creations: %s, %s", tt.description, created[0].Symbol, created[1].Symbol)
+			}
+		})
+	}
+}
+
+func TestLoadConfig(t *testing.T) {
+
+	cases := []struct {
+		description string
+		config      string
+	}{
+		{
+			description: "TestLoadConfig0",
+			config:      "addresses:\n  - {name: a, symbol: ETH, address: '0x1'}\n",
+		},
+		{
+			description: "TestLoadConfig1",
+			config:      "portfolio_id: p1\naddresses:\n  - {name: a, symbol: ETH}\n",
+		},
+		{
+			description: "TestLoadConfig2",
+			config:      "portfolio_id: p1\naddresses:\n  - {name: a, symbol: ETH, address: '0x1'}\n  - {name: b, symbol: eth, address: '0x1'}\n",
+		},
+		{
+			description: "TestLoadConfig3",
+			config:      "portfolio_id: p1\naddresses:\n  - {name: a, symbol: ETH, address: '0x1', memo: x}\n",
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.description, func(t *testing.T) {
+			if _, err := LoadConfig(strings.NewReader(tt.config)); err == nil {
+				t.Errorf("test: %s - expected an error", tt.description)
+			}
+		})
+	}
+}
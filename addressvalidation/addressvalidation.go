@@ -0,0 +1,262 @@
+/**
+ * Copyright 2024-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package addressvalidation checks blockchain addresses and account
+// identifiers (memos and destination tags) offline, so a typo is caught
+// before an address book entry or withdrawal is submitted for approval.
+// Formats are looked up by the network of the address, if set, and
+// otherwise by the asset symbol. Assets issued on several chains, e.g., USDC,
+// are only checked when the network is set.
+package addressvalidation
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/coinbase-samples/prime-sdk-go/model"
+)
+
+var (
+	ErrUnsupported                = errors.New("no address format registered")
+	ErrInvalidAddress             = errors.New("invalid address")
+	ErrInvalidAccountIdentifier   = errors.New("invalid account identifier")
+	ErrAccountIdentifierRequired  = errors.New("account identifier required")
+	ErrAccountIdentifierForbidden = errors.New("account identifier not supported")
+)
+
+// Format is the address format of a blockchain.
+type Format struct {
+	Name string
+
+	// Address returns an error if the address is malformed.
+	Address func(address string) error
+
+	// The name of the account identifier on this chain, e.g., memo. Empty
+	// if the chain does not use one.
+	AccountIdentifierName string
+
+	// AccountIdentifier returns an error if the identifier is malformed. Nil
+	// if the chain does not use one.
+	AccountIdentifier func(id string) error
+}
+
+// ValidationError describes why an address or account identifier was
+// rejected. It unwraps to one of the Err values.
+type ValidationError struct {
+	Symbol  string
+	Network string
+	Format  string
+	Value   string
+	Reason  string
+	Err     error
+}
+
+func (e *ValidationError) Error() string {
+	msg := fmt.Sprintf("%v: %s - symbol: %s - format: %s", e.Err, e.Value, e.Symbol, e.Format)
+	if len(e.Network) > 0 {
+		msg += fmt.Sprintf(" - network: %s", e.Network)
+	}
+	if len(e.Reason) > 0 {
+		msg += fmt.Sprintf(" - reason: %s", e.Reason)
+	}
+	return msg
+}
+
+func (e *ValidationError) Unwrap() error {
+	return e.Err
+}
+
+var (
+	registryMu sync.RWMutex
+
+	// Assets issued on several chains, e.g., USDC on Ethereum and Solana,
+	// are not listed, since their format is only known from the network.
+	symbolFormats = map[string]*Format{
+		"BTC":  Bitcoin,
+		"LTC":  Litecoin,
+		"DOGE": Dogecoin,
+		"SOL":  Solana,
+		"XRP":  Xrp,
+		"XLM":  Stellar,
+		"ATOM": Cosmos,
+
+		"ETH":   Evm,
+		"DAI":   Evm,
+		"WBTC":  Evm,
+		"LINK":  Evm,
+		"UNI":   Evm,
+		"AAVE":  Evm,
+		"MKR":   Evm,
+		"COMP":  Evm,
+		"CRV":   Evm,
+		"LDO":   Evm,
+		"SHIB":  Evm,
+		"APE":   Evm,
+		"POL":   Evm,
+		"MATIC": Evm,
+		"ARB":   Evm,
+		"OP":    Evm,
+	}
+
+	// Keyed by the chain part of the network id, e.g., "base" for
+	// "base-mainnet".
+	networkFormats = map[string]*Format{
+		"bitcoin":  Bitcoin,
+		"litecoin": Litecoin,
+		"dogecoin": Dogecoin,
+		"ethereum": Evm,
+		"base":     Evm,
+		"polygon":  Evm,
+		"arbitrum": Evm,
+		"optimism": Evm,
+		"solana":   Solana,
+		"ripple":   Xrp,
+		"xrp":      Xrp,
+		"stellar":  Stellar,
+		"cosmos":   Cosmos,
+	}
+)
+
+// RegisterSymbol sets the format of the addresses of an asset, keyed by
+// model.Asset.Symbol.
+func RegisterSymbol(symbol string, f *Format) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	symbolFormats[strings.ToUpper(symbol)] = f
+}
+
+// RegisterNetwork sets the format of the addresses of a network. The chain
+// is the part of the network id before the first dash, e.g., "base".
+func RegisterNetwork(chain string, f *Format) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	networkFormats[strings.ToLower(chain)] = f
+}
+
+// LookupFormat returns the format of the network, if set and registered, or
+// else of the symbol.
+func LookupFormat(symbol, networkId string) (*Format, bool) {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	if len(networkId) > 0 {
+		chain := strings.ToLower(strings.SplitN(networkId, "-", 2)[0])
+		if f, ok := networkFormats[chain]; ok {
+			return f, true
+		}
+	}
+
+	f, ok := symbolFormats[strings.ToUpper(symbol)]
+	return f, ok
+}
+
+// CanonicalAddress returns the address in the case used to compare it with
+// another. Hex EVM addresses, whose checksum casing is optional, and bech32
+// addresses, which are valid in all lower or all upper case, are lowercased.
+// Other addresses, e.g., base58, are case-sensitive and only trimmed.
+func CanonicalAddress(address string) string {
+
+	address = strings.TrimSpace(address)
+
+	if len(address) == 42 && strings.HasPrefix(strings.ToLower(address), "0x") {
+		if _, err := hex.DecodeString(address[2:]); err == nil {
+			return strings.ToLower(address)
+		}
+	}
+
+	if _, _, _, err := bech32Decode(address); err == nil {
+		return strings.ToLower(address)
+	}
+
+	return address
+}
+
+// Validator checks addresses against the registered formats. The zero value
+// accepts symbols without a registered format and optional account
+// identifiers.
+type Validator struct {
+	// Reject symbols and networks without a registered format
+	RejectUnsupported bool
+
+	// Reject addresses without an account identifier on chains that use
+	// one, e.g., XRP without a destination tag. Set when the destinations
+	// are exchanges or other omnibus wallets.
+	RequireAccountIdentifier bool
+}
+
+// Validate checks the address with the default Validator.
+func Validate(symbol string, address *model.BlockchainAddress) error {
+	return Validator{}.Validate(symbol, address)
+}
+
+// Validate checks the address and account identifier of a blockchain address
+// of an asset. It returns a *ValidationError if either is malformed.
+func (v Validator) Validate(symbol string, address *model.BlockchainAddress) error {
+
+	if address == nil {
+		return &ValidationError{Symbol: symbol, Err: ErrInvalidAddress, Reason: "address not set"}
+	}
+
+	var networkId string
+	if address.Network != nil {
+		networkId = address.Network.Id
+	}
+
+	f, ok := LookupFormat(symbol, networkId)
+	if !ok {
+		if v.RejectUnsupported {
+			return &ValidationError{Symbol: symbol, Network: networkId, Value: address.Address, Err: ErrUnsupported}
+		}
+		return nil
+	}
+
+	newErr := func(value string, err error, reason string) error {
+		return &ValidationError{Symbol: symbol, Network: networkId, Format: f.Name, Value: value, Reason: reason, Err: err}
+	}
+
+	if address.Address != strings.TrimSpace(address.Address) {
+		return newErr(address.Address, ErrInvalidAddress, "leading or trailing whitespace")
+	}
+
+	if err := f.Address(address.Address); err != nil {
+		return newErr(address.Address, ErrInvalidAddress, err.Error())
+	}
+
+	id := address.AccountIdentifier
+
+	if f.AccountIdentifier == nil {
+		if len(id) > 0 {
+			return newErr(id, ErrAccountIdentifierForbidden, "")
+		}
+		return nil
+	}
+
+	if len(id) == 0 {
+		if v.RequireAccountIdentifier {
+			return newErr(id, ErrAccountIdentifierRequired, f.AccountIdentifierName)
+		}
+		return nil
+	}
+
+	if err := f.AccountIdentifier(id); err != nil {
+		return newErr(id, ErrInvalidAccountIdentifier, fmt.Sprintf("%s %v", f.AccountIdentifierName, err))
+	}
+
+	return nil
+}
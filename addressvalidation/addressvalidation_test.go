@@ -0,0 +1,147 @@
+/**
+ * Copyright 2024-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package addressvalidation
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/coinbase-samples/prime-sdk-go/model"
+)
+
+func TestValidate(t *testing.T) {
+
+	cases := []struct {
+		description string
+		symbol      string
+		network     string
+		address     string
+		identifier  string
+		expected    error
+	}{
+		{description: "TestValidate0", symbol: "BTC", address: "1A1zP1eP5QGefi2DMPTfTL5SLmv7DivfNa"},
+		{description: "TestValidate1", symbol: "BTC", address: "3J98t1WpEZ73CNmQviecrnyiWrnqRhWNLy"},
+		{description: "TestValidate2", symbol: "BTC", address: "bc1qw508d6qejxtdg4y5r3zarvary0c5xw7kv8f3t4"},
+		{description: "TestValidate3", symbol: "BTC", address: "bc1p5d7rjq7g6rdk2yhzks9smlaqtedr4dekq08ge8ztwac72sfr9rusxg3297"},
+		{description: "TestValidate4", symbol: "BTC", address: "1A1zP1eP5QGefi2DMPTfTL5SLmv7DivfNb", expected: ErrInvalidAddress},
+		{description: "TestValidate5", symbol: "BTC", address: "bc1qw508d6qejxtdg4y5r3zarvary0c5xw7kv8f3t5", expected: ErrInvalidAddress},
+		{description: "TestValidate6", symbol: "BTC", address: "0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed", expected: ErrInvalidAddress},
+		{description: "TestValidate7", symbol: "ETH", address: "0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed"},
+		{description: "TestValidate8", symbol: "ETH", address: "0xfB6916095ca1df60bB79Ce92cE3Ea74c37c5d359"},
+		{description: "TestValidate9", symbol: "ETH", address: "0xdbF03B407c01E7cD3CBea99509d93f8DDDC8C6FB"},
+		{description: "TestValidate10", symbol: "ETH", address: "0x5aaeb6053f3e94c9b9a09f33669435e7ef1beaed"},
+		{description: "TestValidate11", symbol: "ETH", address: "0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAeD", expected: ErrInvalidAddress},
+		{description: "TestValidate12", symbol: "ETH", address: "0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeA", expected: ErrInvalidAddress},
+		{description: "TestValidate13", symbol: "ETH", address: "0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed", identifier: "1", expected: ErrAccountIdentifierForbidden},
+		{description: "TestValidate14", symbol: "SOL", address: "TokenkegQfeZyiNwAJbNbGKPFXCWuBvf9Ss623VQ5DA"},
+		{description: "TestValidate15", symbol: "SOL", address: "11111111111111111111111111111111"},
+		{description: "TestValidate16", symbol: "SOL", address: "TokenkegQfeZyiNwAJbNbGKPFXCWuBvf9Ss623VQ5D", expected: ErrInvalidAddress},
+		{description: "TestValidate17", symbol: "USDC", network: "solana-mainnet", address: "TokenkegQfeZyiNwAJbNbGKPFXCWuBvf9Ss623VQ5DA"},
+		{description: "TestValidate18", symbol: "USDC", network: "solana-mainnet", address: "0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed", expected: ErrInvalidAddress},
+		{description: "TestValidate19", symbol: "USDC", network: "base-mainnet", address: "0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed"},
+		{description: "TestValidate20", symbol: "XRP", address: "rHb9CJAWyB4rj91VRWn96DkukG4bwdtyTh", identifier: "12345"},
+		{description: "TestValidate21", symbol: "XRP", address: "rHb9CJAWyB4rj91VRWn96DkukG4bwdtyTh", identifier: "memo", expected: ErrInvalidAccountIdentifier},
+		{description: "TestValidate22", symbol: "XRP", address: "rHb9CJAWyB4rj91VRWn96DkukG4bwdtyTh", identifier: "4294967296", expected: ErrInvalidAccountIdentifier},
+		{description: "TestValidate23", symbol: "XRP", address: "rHb9CJAWyB4rj91VRWn96DkukG4bwdtyTi", expected: ErrInvalidAddress},
+		{description: "TestValidate24", symbol: "XLM", address: "GAAZI4TCR3TY5OJHCTJC2A4QSY6CJWJH5IAJTGKIN2ER7LBNVKOCCWN7", identifier: "invoice 42"},
+		{description: "TestValidate25", symbol: "XLM", address: "GAAZI4TCR3TY5OJHCTJC2A4QSY6CJWJH5IAJTGKIN2ER7LBNVKOCCWN6", expected: ErrInvalidAddress},
+		{description: "TestValidate26", symbol: "XLM", address: "GAAZI4TCR3TY5OJHCTJC2A4QSY6CJWJH5IAJTGKIN2ER7LBNVKOCCWN7", identifier: "a memo that is longer than twenty eight bytes", expected: ErrInvalidAccountIdentifier},
+		{description: "TestValidate27", symbol: "ATOM", address: "cosmos1qypqxpq9qcrsszg2pvxq6rs0zqg3yyc5lzv7xu"},
+		{description: "TestValidate28", symbol: "ATOM", address: "cosmos1qypqxpq9qcrsszg2pvxq6rs0zqg3yyc5lzv7xv", expected: ErrInvalidAddress},
+		{description: "TestValidate29", symbol: "ATOM", address: "bc1qw508d6qejxtdg4y5r3zarvary0c5xw7kv8f3t4", expected: ErrInvalidAddress},
+		{description: "TestValidate30", symbol: "DOGE", address: "DH5yaieqoZN36fDVciNyRueRGvGLR3mr7L"},
+		{description: "TestValidate31", symbol: "LTC", address: "1A1zP1eP5QGefi2DMPTfTL5SLmv7DivfNa", expected: ErrInvalidAddress},
+		{description: "TestValidate32", symbol: "ETH", address: " 0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed", expected: ErrInvalidAddress},
+		{description: "TestValidate33", symbol: "UNKNOWN", address: "anything"},
+		{description: "TestValidate34", symbol: "USDC", address: "TokenkegQfeZyiNwAJbNbGKPFXCWuBvf9Ss623VQ5DA"},
+		{description: "TestValidate35", symbol: "USDC", address: "0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed"},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.description, func(t *testing.T) {
+
+			address := &model.BlockchainAddress{Address: tt.address, AccountIdentifier: tt.identifier}
+			if len(tt.network) > 0 {
+				address.Network = &model.Network{Id: tt.network}
+			}
+
+			err := Validate(tt.symbol, address)
+			if !errors.Is(err, tt.expected) || (err == nil) != (tt.expected == nil) {
+				t.Errorf("test: %s - expected: %v - received: %v", tt.description, tt.expected, err)
+			}
+		})
+	}
+}
+
+func TestValidatorOptions(t *testing.T) {
+
+	v := Validator{RejectUnsupported: true, RequireAccountIdentifier: true}
+
+	if err := v.Validate("UNKNOWN", &model.BlockchainAddress{Address: "anything"}); !errors.Is(err, ErrUnsupported) {
+		t.Errorf("expected: %v - received: %v", ErrUnsupported, err)
+	}
+
+	if err := v.Validate("USDC", &model.BlockchainAddress{Address: "TokenkegQfeZyiNwAJbNbGKPFXCWuBvf9Ss623VQ5DA"}); !errors.Is(err, ErrUnsupported) {
+		t.Errorf("expected: %v - received: %v", ErrUnsupported, err)
+	}
+
+	if err := v.Validate("XRP", &model.BlockchainAddress{Address: "rHb9CJAWyB4rj91VRWn96DkukG4bwdtyTh"}); !errors.Is(err, ErrAccountIdentifierRequired) {
+		t.Errorf("expected: %v - received: %v", ErrAccountIdentifierRequired, err)
+	}
+
+	if err := v.Validate("BTC", &model.BlockchainAddress{Address: "1A1zP1eP5QGefi2DMPTfTL5SLmv7DivfNa"}); err != nil {
+		t.Errorf("expected no error - received: %v", err)
+	}
+}
+
+func TestCanonicalAddress(t *testing.T) {
+
+	cases := []struct {
+		description string
+		address     string
+		expected    string
+	}{
+		{
+			description: "TestCanonicalAddress0",
+			address:     "0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed",
+			expected:    "0x5aaeb6053f3e94c9b9a09f33669435e7ef1beaed",
+		},
+		{
+			description: "TestCanonicalAddress1",
+			address:     " BC1QW508D6QEJXTDG4Y5R3ZARVARY0C5XW7KV8F3T4",
+			expected:    "bc1qw508d6qejxtdg4y5r3zarvary0c5xw7kv8f3t4",
+		},
+		{
+			description: "TestCanonicalAddress2",
+			address:     "rHb9CJAWyB4rj91VRWn96DkukG4bwdtyTh",
+			expected:    "rHb9CJAWyB4rj91VRWn96DkukG4bwdtyTh",
+		},
+		{
+			description: "TestCanonicalAddress3",
+			address:     "TokenkegQfeZyiNwAJbNbGKPFXCWuBvf9Ss623VQ5DA",
+			expected:    "TokenkegQfeZyiNwAJbNbGKPFXCWuBvf9Ss623VQ5DA",
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.description, func(t *testing.T) {
+			if received := CanonicalAddress(tt.address); received != tt.expected {
+				t.Errorf("test: %s - expected: %s - received: %s", tt.description, tt.expected, received)
+			}
+		})
+	}
+}
@@ -0,0 +1,93 @@
+/**
+ * Copyright 2024-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package addressvalidation
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"math/big"
+)
+
+const (
+	bitcoinAlphabet = "123456789ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnopqrstuvwxyz"
+	rippleAlphabet  = "rpshnaf39wBUDNEGHJKLM4PQRST7VWXYZ2bcdeCg65jkm8oFqi1tuvAxyz"
+)
+
+var bigRadix = big.NewInt(58)
+
+// base58Decode decodes s with the alphabet. Leading zero characters are
+// decoded as leading zero bytes.
+func base58Decode(s, alphabet string) ([]byte, error) {
+
+	if len(s) == 0 {
+		return nil, errors.New("empty")
+	}
+
+	n := new(big.Int)
+	for _, r := range s {
+		i := indexOf(alphabet, r)
+		if i < 0 {
+			return nil, fmt.Errorf("invalid base58 character: %q", r)
+		}
+		n.Mul(n, bigRadix)
+		n.Add(n, big.NewInt(int64(i)))
+	}
+
+	var zeros int
+	for zeros < len(s) && s[zeros] == alphabet[0] {
+		zeros++
+	}
+
+	return append(make([]byte, zeros), n.Bytes()...), nil
+}
+
+// base58CheckDecode decodes s and verifies the trailing 4 byte double
+// SHA-256 checksum. It returns the version byte and the payload.
+func base58CheckDecode(s, alphabet string) (version byte, payload []byte, err error) {
+
+	decoded, err := base58Decode(s, alphabet)
+	if err != nil {
+		return
+	}
+
+	if len(decoded) < 5 {
+		err = errors.New("too short")
+		return
+	}
+
+	body, checksum := decoded[:len(decoded)-4], decoded[len(decoded)-4:]
+
+	first := sha256.Sum256(body)
+	second := sha256.Sum256(first[:])
+	if !bytes.Equal(second[:4], checksum) {
+		err = errors.New("invalid checksum")
+		return
+	}
+
+	return body[0], body[1:], nil
+}
+
+func indexOf(alphabet string, r rune) int {
+	for i, c := range alphabet {
+		if c == r {
+			return i
+		}
+	}
+	return -1
+}
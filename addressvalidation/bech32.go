@@ -0,0 +1,171 @@
+/**
+ * Copyright 2024-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package addressvalidation
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+const bech32Charset = "qpzry9x8gf2tvdw0s3jn54khce6mua7l"
+
+type bech32Encoding int
+
+const (
+	bech32  bech32Encoding = 1
+	bech32m bech32Encoding = 0x2bc830a3
+)
+
+// bech32Decode decodes a bech32 or bech32m string (BIP-173, BIP-350) and
+// returns the human readable part, the 5 bit data without the checksum and
+// the encoding the checksum matched.
+func bech32Decode(s string) (hrp string, data []byte, encoding bech32Encoding, err error) {
+
+	if len(s) > 90 {
+		err = errors.New("too long")
+		return
+	}
+
+	if strings.ToLower(s) != s && strings.ToUpper(s) != s {
+		err = errors.New("mixed case")
+		return
+	}
+	s = strings.ToLower(s)
+
+	sep := strings.LastIndexByte(s, '1')
+	if sep < 1 || sep+7 > len(s) {
+		err = errors.New("invalid separator position")
+		return
+	}
+
+	hrp = s[:sep]
+	for _, c := range hrp {
+		if c < 33 || c > 126 {
+			err = fmt.Errorf("invalid human readable part character: %q", c)
+			return
+		}
+	}
+
+	for _, c := range s[sep+1:] {
+		i := strings.IndexRune(bech32Charset, c)
+		if i < 0 {
+			err = fmt.Errorf("invalid bech32 character: %q", c)
+			return
+		}
+		data = append(data, byte(i))
+	}
+
+	switch bech32Encoding(bech32Polymod(append(bech32HrpExpand(hrp), data...))) {
+	case bech32:
+		encoding = bech32
+	case bech32m:
+		encoding = bech32m
+	default:
+		err = errors.New("invalid checksum")
+		return
+	}
+
+	data = data[:len(data)-6]
+	return
+}
+
+func bech32Polymod(values []byte) uint32 {
+	gen := [5]uint32{0x3b6a57b2, 0x26508e6d, 0x1ea119fa, 0x3d4233dd, 0x2a1462b3}
+	chk := uint32(1)
+	for _, v := range values {
+		top := chk >> 25
+		chk = (chk&0x1ffffff)<<5 ^ uint32(v)
+		for i := 0; i < 5; i++ {
+			if (top>>i)&1 == 1 {
+				chk ^= gen[i]
+			}
+		}
+	}
+	return chk
+}
+
+func bech32HrpExpand(hrp string) []byte {
+	expanded := make([]byte, 0, len(hrp)*2+1)
+	for _, c := range hrp {
+		expanded = append(expanded, byte(c>>5))
+	}
+	expanded = append(expanded, 0)
+	for _, c := range hrp {
+		expanded = append(expanded, byte(c&31))
+	}
+	return expanded
+}
+
+// convertBits regroups 5 bit data into bytes. Padding must be zero and
+// shorter than a group.
+func convertBits(data []byte, from, to uint) ([]byte, error) {
+	var acc, n uint
+	var out []byte
+	for _, v := range data {
+		acc = acc<<from | uint(v)
+		n += from
+		for n >= to {
+			n -= to
+			out = append(out, byte(acc>>n&(1<<to-1)))
+		}
+	}
+	if n >= from || acc&(1<<n-1) != 0 {
+		return nil, errors.New("invalid padding")
+	}
+	return out, nil
+}
+
+// segwitDecode validates a segregated witness address with the human
+// readable part: version 0 programs are 20 or 32 bytes encoded with bech32,
+// later versions are encoded with bech32m.
+func segwitDecode(s, expectedHrp string) error {
+
+	hrp, data, encoding, err := bech32Decode(s)
+	if err != nil {
+		return err
+	}
+
+	if hrp != expectedHrp {
+		return fmt.Errorf("unexpected prefix: %s", hrp)
+	}
+
+	if len(data) < 1 || data[0] > 16 {
+		return errors.New("invalid witness version")
+	}
+
+	version := data[0]
+
+	program, err := convertBits(data[1:], 5, 8)
+	if err != nil {
+		return err
+	}
+
+	if len(program) < 2 || len(program) > 40 {
+		return fmt.Errorf("invalid witness program length: %d", len(program))
+	}
+
+	if version == 0 && len(program) != 20 && len(program) != 32 {
+		return fmt.Errorf("invalid version 0 witness program length: %d", len(program))
+	}
+
+	if version == 0 && encoding != bech32 || version > 0 && encoding != bech32m {
+		return errors.New("checksum encoding does not match witness version")
+	}
+
+	return nil
+}
@@ -0,0 +1,295 @@
+/**
+ * Copyright 2024-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package addressvalidation
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+var (
+	Bitcoin = &Format{
+		Name:    "bitcoin",
+		Address: utxoAddress("bc", 0x00, 0x05),
+	}
+
+	Litecoin = &Format{
+		Name:    "litecoin",
+		Address: utxoAddress("ltc", 0x30, 0x32, 0x05),
+	}
+
+	Dogecoin = &Format{
+		Name:    "dogecoin",
+		Address: utxoAddress("", 0x1e, 0x16),
+	}
+
+	Evm = &Format{
+		Name:    "evm",
+		Address: evmAddress,
+	}
+
+	Solana = &Format{
+		Name:    "solana",
+		Address: solanaAddress,
+	}
+
+	Xrp = &Format{
+		Name:                  "xrp",
+		Address:               xrpAddress,
+		AccountIdentifierName: "destination tag",
+		AccountIdentifier:     xrpDestinationTag,
+	}
+
+	Stellar = &Format{
+		Name:                  "stellar",
+		Address:               stellarAddress,
+		AccountIdentifierName: "memo",
+		AccountIdentifier:     maxBytesMemo(28),
+	}
+
+	Cosmos = &Format{
+		Name:                  "cosmos",
+		Address:               cosmosAddress("cosmos"),
+		AccountIdentifierName: "memo",
+		AccountIdentifier:     maxBytesMemo(256),
+	}
+)
+
+// utxoAddress accepts base58check addresses with one of the version bytes
+// and, if hrp is set, segwit addresses with the human readable part.
+func utxoAddress(hrp string, versions ...byte) func(string) error {
+	return func(address string) error {
+
+		if len(hrp) > 0 && strings.HasPrefix(strings.ToLower(address), hrp+"1") {
+			return segwitDecode(address, hrp)
+		}
+
+		version, payload, err := base58CheckDecode(address, bitcoinAlphabet)
+		if err != nil {
+			return err
+		}
+
+		if len(payload) != 20 {
+			return fmt.Errorf("invalid payload length: %d", len(payload))
+		}
+
+		for _, v := range versions {
+			if version == v {
+				return nil
+			}
+		}
+
+		return fmt.Errorf("unexpected version: %d", version)
+	}
+}
+
+// evmAddress accepts 0x prefixed 20 byte hex addresses. Mixed case addresses
+// must have a valid EIP-55 checksum; all lower or all upper case addresses
+// carry no checksum.
+func evmAddress(address string) error {
+
+	if !strings.HasPrefix(address, "0x") {
+		return errors.New("missing 0x prefix")
+	}
+
+	digits := address[2:]
+	if len(digits) != 40 {
+		return fmt.Errorf("invalid length: %d", len(digits))
+	}
+
+	if _, err := hex.DecodeString(digits); err != nil {
+		return errors.New("invalid hex")
+	}
+
+	if digits == strings.ToLower(digits) || digits == strings.ToUpper(digits) {
+		return nil
+	}
+
+	if address != eip55Checksum(digits) {
+		return errors.New("invalid EIP-55 checksum")
+	}
+
+	return nil
+}
+
+// eip55Checksum returns the checksummed form of the 40 hex digits.
+func eip55Checksum(digits string) string {
+
+	lower := strings.ToLower(digits)
+	hash := keccak256([]byte(lower))
+
+	checksummed := []byte(lower)
+	for i, c := range checksummed {
+		nibble := hash[i/2] >> 4
+		if i%2 == 1 {
+			nibble = hash[i/2] & 0x0f
+		}
+		if c >= 'a' && nibble >= 8 {
+			checksummed[i] = c - 32
+		}
+	}
+
+	return "0x" + string(checksummed)
+}
+
+// solanaAddress accepts base58 encoded 32 byte public keys.
+func solanaAddress(address string) error {
+
+	decoded, err := base58Decode(address, bitcoinAlphabet)
+	if err != nil {
+		return err
+	}
+
+	if len(decoded) != 32 {
+		return fmt.Errorf("invalid public key length: %d", len(decoded))
+	}
+
+	return nil
+}
+
+// xrpAddress accepts classic r-addresses.
+func xrpAddress(address string) error {
+
+	version, payload, err := base58CheckDecode(address, rippleAlphabet)
+	if err != nil {
+		return err
+	}
+
+	if version != 0 || len(payload) != 20 {
+		return errors.New("not a classic address")
+	}
+
+	return nil
+}
+
+func xrpDestinationTag(tag string) error {
+	if _, err := strconv.ParseUint(tag, 10, 32); err != nil {
+		return errors.New("must be an integer between 0 and 4294967295")
+	}
+	return nil
+}
+
+// stellarAddress accepts G-addresses: the base32 StrKey of an ed25519 public
+// key with a CRC16-XModem checksum.
+func stellarAddress(address string) error {
+
+	if len(address) != 56 || address[0] != 'G' {
+		return errors.New("not an account address")
+	}
+
+	decoded, err := stellarBase32Decode(address)
+	if err != nil {
+		return err
+	}
+
+	if len(decoded) != 35 || decoded[0] != 6<<3 {
+		return errors.New("invalid version byte")
+	}
+
+	body := decoded[:33]
+	checksum := uint16(decoded[33]) | uint16(decoded[34])<<8
+
+	if crc16XModem(body) != checksum {
+		return errors.New("invalid checksum")
+	}
+
+	return nil
+}
+
+func stellarBase32Decode(s string) ([]byte, error) {
+
+	const alphabet = "ABCDEFGHIJKLMNOPQRSTUVWXYZ234567"
+
+	var out []byte
+	var acc, n uint
+
+	for _, c := range s {
+		i := strings.IndexRune(alphabet, c)
+		if i < 0 {
+			return nil, fmt.Errorf("invalid base32 character: %q", c)
+		}
+		acc = acc<<5 | uint(i)
+		n += 5
+		if n >= 8 {
+			n -= 8
+			out = append(out, byte(acc>>n))
+		}
+	}
+
+	return out, nil
+}
+
+func crc16XModem(data []byte) uint16 {
+	var crc uint16
+	for _, b := range data {
+		crc ^= uint16(b) << 8
+		for i := 0; i < 8; i++ {
+			if crc&0x8000 != 0 {
+				crc = crc<<1 ^ 0x1021
+			} else {
+				crc <<= 1
+			}
+		}
+	}
+	return crc
+}
+
+// cosmosAddress accepts bech32 account addresses with the prefix.
+func cosmosAddress(prefix string) func(string) error {
+	return func(address string) error {
+
+		hrp, data, encoding, err := bech32Decode(address)
+		if err != nil {
+			return err
+		}
+
+		if hrp != prefix {
+			return fmt.Errorf("unexpected prefix: %s", hrp)
+		}
+
+		if encoding != bech32 {
+			return errors.New("unexpected bech32m checksum")
+		}
+
+		decoded, err := convertBits(data, 5, 8)
+		if err != nil {
+			return err
+		}
+
+		if len(decoded) != 20 && len(decoded) != 32 {
+			return fmt.Errorf("invalid account length: %d", len(decoded))
+		}
+
+		return nil
+	}
+}
+
+func maxBytesMemo(max int) func(string) error {
+	return func(memo string) error {
+		if !utf8.ValidString(memo) {
+			return errors.New("must be valid UTF-8")
+		}
+		if len(memo) > max {
+			return fmt.Errorf("must be at most %d bytes", max)
+		}
+		return nil
+	}
+}
@@ -0,0 +1,48 @@
+/**
+ * Copyright 2024-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package addressvalidation
+
+import (
+	"context"
+
+	"github.com/coinbase-samples/prime-sdk-go/addressbook"
+	"github.com/coinbase-samples/prime-sdk-go/client"
+	"github.com/coinbase-samples/prime-sdk-go/model"
+	"github.com/coinbase-samples/prime-sdk-go/transactions"
+)
+
+// RequestHook returns a client hook that validates the address of address
+// book entries and blockchain withdrawals before they are sent:
+//
+//	client.AddRequestHook(c, addressvalidation.Validator{}.RequestHook())
+func (v Validator) RequestHook() client.RequestHook {
+	return func(ctx context.Context, request interface{}) error {
+		switch r := request.(type) {
+		case *addressbook.CreateAddressBookEntryRequest:
+			return v.Validate(r.Symbol, &model.BlockchainAddress{
+				Address:           r.Address,
+				AccountIdentifier: r.AccountIdentifier,
+			})
+		case *transactions.CreateWalletWithdrawalRequest:
+			if r.BlockchainAddress == nil {
+				return nil
+			}
+			return v.Validate(r.Symbol, r.BlockchainAddress)
+		}
+		return nil
+	}
+}
@@ -0,0 +1,85 @@
+/**
+ * Copyright 2024-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package addressvalidation
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/coinbase-samples/prime-sdk-go/addressbook"
+	"github.com/coinbase-samples/prime-sdk-go/client"
+	"github.com/coinbase-samples/prime-sdk-go/credentials"
+)
+
+func TestRequestHook(t *testing.T) {
+
+	cases := []struct {
+		description string
+		address     string
+		sent        bool
+	}{
+		{
+			description: "TestRequestHook0",
+			address:     "0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed",
+			sent:        true,
+		},
+		{
+			description: "TestRequestHook1",
+			address:     "0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAeD",
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.description, func(t *testing.T) {
+
+			var sent bool
+
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				sent = true
+				w.Write([]byte(`{"activity_id":"a1"}`))
+			}))
+			defer server.Close()
+
+			c := client.NewRestClient(&credentials.Credentials{}, http.Client{}).SetBaseUrl(server.URL)
+			if err := client.AddRequestHook(c, Validator{}.RequestHook()); err != nil {
+				t.Fatal(err)
+			}
+
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+
+			_, err := addressbook.NewAddressBookService(c).CreateAddressBookEntry(ctx, &addressbook.CreateAddressBookEntryRequest{
+				PortfolioId: "p1",
+				Symbol:      "ETH",
+				Name:        "treasury",
+				Address:     tt.address,
+			})
+
+			if sent != tt.sent {
+				t.Errorf("test: %s - expected sent: %v - received: %v", tt.description, tt.sent, sent)
+			}
+
+			if !tt.sent && !errors.Is(err, ErrInvalidAddress) {
+				t.Errorf("test: %s - expected: %v - received: %v", tt.description, ErrInvalidAddress, err)
+			}
+		})
+	}
+}
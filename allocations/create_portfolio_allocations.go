@@ -48,6 +48,10 @@ func (s *allocationsServiceImpl) CreatePortfolioAllocations(
 
 	path := "/allocations"
 
+	if err := client.RunRequestHooks(ctx, s.client, request); err != nil {
+		return nil, err
+	}
+
 	response := &CreatePortfolioAllocationsResponse{Request: request}
 
 	if err := core.HttpPost(
@@ -60,8 +64,11 @@ func (s *allocationsServiceImpl) CreatePortfolioAllocations(
 		response,
 		s.client.HeadersFunc(),
 	); err != nil {
+		client.RunResponseHooks(ctx, s.client, request, err)
 		return nil, err
 	}
 
+	client.RunResponseHooks(ctx, s.client, request, nil)
+
 	return response, nil
 }
@@ -50,6 +50,10 @@ func (s *allocationsServiceImpl) CreatePortfolioNetAllocations(
 
 	path := "/allocations/net"
 
+	if err := client.RunRequestHooks(ctx, s.client, request); err != nil {
+		return nil, err
+	}
+
 	response := &CreatePortfolioNetAllocationsResponse{Request: request}
 
 	if err := core.HttpPost(
@@ -62,8 +66,11 @@ func (s *allocationsServiceImpl) CreatePortfolioNetAllocations(
 		response,
 		s.client.HeadersFunc(),
 	); err != nil {
+		client.RunResponseHooks(ctx, s.client, request, err)
 		return nil, err
 	}
 
+	client.RunResponseHooks(ctx, s.client, request, nil)
+
 	return response, nil
 }
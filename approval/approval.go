@@ -0,0 +1,133 @@
+/**
+ * Copyright 2024-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package approval
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+const (
+	OperationCreateOrder                = "CreateOrder"
+	OperationEditOrder                  = "EditOrder"
+	OperationAcceptQuote                = "AcceptQuote"
+	OperationCreateWalletTransfer       = "CreateWalletTransfer"
+	OperationCreateWalletWithdrawal     = "CreateWalletWithdrawal"
+	OperationCreateOnchainTransaction   = "CreateOnchainTransaction"
+	OperationCreatePortfolioAllocations = "CreatePortfolioAllocations"
+
+	// Held until an approver confirms or rejects it
+	StatusPending = "PENDING"
+
+	// Confirmed by an approver and ready to be submitted
+	StatusApproved = "APPROVED"
+
+	// The request is being sent. A request left in this state by a crash has
+	// an unknown outcome and is never sent again; check Prime before acting.
+	StatusSubmitting = "SUBMITTING"
+
+	StatusSubmitted = "SUBMITTED"
+	StatusFailed    = "FAILED"
+	StatusRejected  = "REJECTED"
+)
+
+var (
+	ErrHeld          = errors.New("request held for approval")
+	ErrNotFound      = errors.New("held request not found")
+	ErrSelfApproval  = errors.New("requester cannot approve or reject own request")
+	ErrHashMismatch  = errors.New("hash does not match held request")
+	ErrTampered      = errors.New("held request payload does not match its hash")
+	ErrChanged       = errors.New("held request changed since it was approved")
+	ErrNotPending    = errors.New("held request is not pending")
+	ErrNotApproved   = errors.New("held request is not approved")
+	ErrApproverUnset = errors.New("approver not set")
+)
+
+// HeldRequest is a serialized mutating request waiting for, or past, a
+// second person's approval.
+type HeldRequest struct {
+	Id        string          `json:"id"`
+	Operation string          `json:"operation"`
+	Payload   json.RawMessage `json:"payload"`
+
+	// The hex encoded SHA-256 of the operation and payload. Approvers confirm
+	// the hash they reviewed, so a request that changed since cannot be
+	// approved.
+	Hash string `json:"hash"`
+
+	Status      string    `json:"status"`
+	RequestedBy string    `json:"requested_by"`
+	Requested   time.Time `json:"requested_at"`
+	DecidedBy   string    `json:"decided_by"`
+	Decided     time.Time `json:"decided_at"`
+	Reason      string    `json:"reason"`
+	Submitted   time.Time `json:"submitted_at"`
+
+	// The hash the approver confirmed. Submit only sends a payload that
+	// still matches it, so a payload and hash edited after the approval are
+	// detected.
+	ApprovedHash string `json:"approved_hash"`
+
+	// The Prime response or error of the submitted request
+	Response json.RawMessage `json:"response"`
+	Error    string          `json:"error"`
+}
+
+// Verify checks that the payload still matches the hash.
+func (r HeldRequest) Verify() error {
+	if Hash(r.Operation, r.Payload) != r.Hash {
+		return fmt.Errorf("%w - id: %s", ErrTampered, r.Id)
+	}
+	return nil
+}
+
+// Hash returns the hex encoded SHA-256 of the operation and the compact
+// JSON payload, so the hash does not depend on how the store formats it.
+func Hash(operation string, payload []byte) string {
+
+	var compact bytes.Buffer
+	if err := json.Compact(&compact, payload); err == nil {
+		payload = compact.Bytes()
+	}
+
+	h := sha256.New()
+	h.Write([]byte(operation))
+	h.Write([]byte{'\n'})
+	h.Write(payload)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// HeldError is returned by a service call that was held for approval. It
+// matches ErrHeld with errors.Is.
+type HeldError struct {
+	Id        string
+	Operation string
+	Hash      string
+}
+
+func (e *HeldError) Error() string {
+	return fmt.Sprintf("%s - operation: %s - id: %s - hash: %s", ErrHeld, e.Operation, e.Id, e.Hash)
+}
+
+func (e *HeldError) Unwrap() error {
+	return ErrHeld
+}
@@ -0,0 +1,337 @@
+/**
+ * Copyright 2024-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package approval
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/coinbase-samples/prime-sdk-go/allocations"
+	"github.com/coinbase-samples/prime-sdk-go/client"
+	"github.com/coinbase-samples/prime-sdk-go/idempotency"
+	"github.com/coinbase-samples/prime-sdk-go/orders"
+	"github.com/coinbase-samples/prime-sdk-go/quotes"
+	"github.com/coinbase-samples/prime-sdk-go/transactions"
+)
+
+type approvedContextKey struct{}
+
+// Queue holds mutating requests until a second person approves them. Add
+// its hook to the client after other hooks, e.g., the policy engine, so
+// requests that would be blocked are not queued:
+//
+//	queue := approval.NewQueue(c, store, "alice")
+//	client.AddRequestHook(c, queue.RequestHook())
+//
+// Held calls return a *HeldError with the id and hash to pass to the
+// approver. Once approved, Submit sends the request through the client,
+// which runs the other hooks again. Add the policy engine's ResponseHook,
+// so the daily withdrawal amount reserved for a held call is released and
+// only counted once, on submit.
+//
+// The requester and approver are identities passed by the caller and the
+// held requests are files, so four-eyes approval relies on each person
+// running with their own identity and on access to the store being
+// restricted.
+type Queue struct {
+	client client.RestClient
+	store  Store
+
+	// The identity recorded as the requester of held requests. An approver
+	// must be someone else.
+	RequestedBy string
+
+	// Selects the requests to hold, e.g., orders above a notional. Defaults
+	// to all requests of the covered operations.
+	Hold func(operation string, request interface{}) bool
+
+	now func() time.Time
+}
+
+// NewQueue returns a queue that persists held requests in the store. The
+// client is only needed to submit requests, so approvers can pass nil.
+func NewQueue(c client.RestClient, store Store, requestedBy string) *Queue {
+	return &Queue{
+		client:      c,
+		store:       store,
+		RequestedBy: requestedBy,
+		now:         time.Now,
+	}
+}
+
+// RequestHook returns a client hook that serializes and persists
+// CreateOrder, EditOrder, AcceptQuote, CreateWalletTransfer,
+// CreateWalletWithdrawal, CreateOnchainTransaction and
+// CreatePortfolioAllocations requests and blocks the call with a
+// *HeldError. Quotes usually expire before they are approved, so a held
+// accept is rejected by Prime on submit; exclude them with Hold if RFQs do
+// not need a second person. Transfer and withdrawal idempotency keys are resolved before
+// hooks run, so they are part of the held request.
+func (q *Queue) RequestHook() client.RequestHook {
+	return func(ctx context.Context, request interface{}) error {
+
+		operation := operationOf(request)
+		if len(operation) == 0 {
+			return nil
+		}
+
+		payload, err := json.Marshal(request)
+		if err != nil {
+			return fmt.Errorf("unable to serialize request - operation: %s - err: %w", operation, err)
+		}
+
+		hash := Hash(operation, payload)
+
+		// Let the request submitted by Submit through
+		if approved, ok := ctx.Value(approvedContextKey{}).(string); ok && approved == hash {
+			return nil
+		}
+
+		if q.Hold != nil && !q.Hold(operation, request) {
+			return nil
+		}
+
+		id, err := idempotency.NewKey()
+		if err != nil {
+			return fmt.Errorf("unable to generate held request id: %w", err)
+		}
+
+		held := &HeldRequest{
+			Id:          id,
+			Operation:   operation,
+			Payload:     payload,
+			Hash:        hash,
+			Status:      StatusPending,
+			RequestedBy: q.RequestedBy,
+			Requested:   q.now().UTC(),
+		}
+
+		if err := q.store.Create(ctx, held); err != nil {
+			return fmt.Errorf("unable to hold request - operation: %s - err: %w", operation, err)
+		}
+
+		return &HeldError{Id: id, Operation: operation, Hash: hash}
+	}
+}
+
+// Pending returns the requests waiting for approval.
+func (q *Queue) Pending(ctx context.Context) ([]*HeldRequest, error) {
+
+	requests, err := q.store.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var pending []*HeldRequest
+	for _, r := range requests {
+		if r.Status == StatusPending {
+			pending = append(pending, r)
+		}
+	}
+
+	return pending, nil
+}
+
+// Get returns a held request.
+func (q *Queue) Get(ctx context.Context, id string) (*HeldRequest, error) {
+	return q.store.Get(ctx, id)
+}
+
+// Approve confirms a pending request. The approver must not be the
+// requester and must pass the hash of the request they reviewed.
+func (q *Queue) Approve(ctx context.Context, id, approver, hash string) (*HeldRequest, error) {
+	return q.decide(ctx, id, approver, func(r *HeldRequest) error {
+		if r.Hash != hash {
+			return fmt.Errorf("%w - id: %s", ErrHashMismatch, id)
+		}
+		r.Status = StatusApproved
+		r.ApprovedHash = hash
+		return nil
+	})
+}
+
+// Reject declines a pending request, so it can never be submitted.
+func (q *Queue) Reject(ctx context.Context, id, approver, reason string) (*HeldRequest, error) {
+	return q.decide(ctx, id, approver, func(r *HeldRequest) error {
+		r.Status = StatusRejected
+		r.Reason = reason
+		return nil
+	})
+}
+
+func (q *Queue) decide(ctx context.Context, id, approver string, fn func(r *HeldRequest) error) (*HeldRequest, error) {
+
+	if len(approver) == 0 {
+		return nil, ErrApproverUnset
+	}
+
+	return q.store.Update(ctx, id, func(r *HeldRequest) error {
+
+		if r.Status != StatusPending {
+			return fmt.Errorf("%w - id: %s - status: %s", ErrNotPending, id, r.Status)
+		}
+
+		if approver == r.RequestedBy {
+			return fmt.Errorf("%w - id: %s - approver: %s", ErrSelfApproval, id, approver)
+		}
+
+		if err := r.Verify(); err != nil {
+			return err
+		}
+
+		if err := fn(r); err != nil {
+			return err
+		}
+
+		r.DecidedBy = approver
+		r.Decided = q.now().UTC()
+
+		return nil
+	})
+}
+
+// Submit sends an approved request. The request moves to SUBMITTING before
+// it is sent, so it is submitted exactly once: later calls, from this or
+// another process sharing the store, return ErrNotApproved. The held
+// request is returned with the error of the call, if any.
+func (q *Queue) Submit(ctx context.Context, id string) (*HeldRequest, error) {
+
+	if q.client == nil {
+		return nil, errors.New("client not set on queue")
+	}
+
+	held, err := q.store.Update(ctx, id, func(r *HeldRequest) error {
+
+		if r.Status != StatusApproved {
+			return fmt.Errorf("%w - id: %s - status: %s", ErrNotApproved, id, r.Status)
+		}
+
+		if err := r.Verify(); err != nil {
+			return err
+		}
+
+		if r.ApprovedHash != r.Hash {
+			return fmt.Errorf("%w - id: %s", ErrChanged, id)
+		}
+
+		r.Status = StatusSubmitting
+		r.Submitted = q.now().UTC()
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	response, callErr := q.send(context.WithValue(ctx, approvedContextKey{}, held.ApprovedHash), held)
+
+	updated, err := q.store.Update(context.Background(), id, func(r *HeldRequest) error {
+		if callErr != nil {
+			r.Status = StatusFailed
+			r.Error = callErr.Error()
+			return nil
+		}
+
+		b, err := json.Marshal(response)
+		if err != nil {
+			return fmt.Errorf("unable to serialize response - id: %s - err: %w", id, err)
+		}
+
+		r.Status = StatusSubmitted
+		r.Response = b
+
+		return nil
+	})
+	if err != nil {
+		return held, fmt.Errorf("unable to record submission - id: %s - err: %w", id, err)
+	}
+
+	return updated, callErr
+}
+
+func (q *Queue) send(ctx context.Context, held *HeldRequest) (interface{}, error) {
+
+	switch held.Operation {
+	case OperationCreateOrder:
+		request := &orders.CreateOrderRequest{}
+		if err := json.Unmarshal(held.Payload, request); err != nil {
+			return nil, fmt.Errorf("unable to deserialize held request - id: %s - err: %w", held.Id, err)
+		}
+		return orders.NewOrdersService(q.client).CreateOrder(ctx, request)
+	case OperationEditOrder:
+		request := &orders.EditOrderRequest{}
+		if err := json.Unmarshal(held.Payload, request); err != nil {
+			return nil, fmt.Errorf("unable to deserialize held request - id: %s - err: %w", held.Id, err)
+		}
+		return orders.NewOrdersService(q.client).EditOrder(ctx, request)
+	case OperationAcceptQuote:
+		request := &quotes.AcceptQuoteRequest{}
+		if err := json.Unmarshal(held.Payload, request); err != nil {
+			return nil, fmt.Errorf("unable to deserialize held request - id: %s - err: %w", held.Id, err)
+		}
+		return quotes.NewQuotesService(q.client).AcceptQuote(ctx, request)
+	case OperationCreateWalletTransfer:
+		request := &transactions.CreateWalletTransferRequest{}
+		if err := json.Unmarshal(held.Payload, request); err != nil {
+			return nil, fmt.Errorf("unable to deserialize held request - id: %s - err: %w", held.Id, err)
+		}
+		return transactions.NewTransactionsService(q.client).CreateWalletTransfer(ctx, request)
+	case OperationCreateWalletWithdrawal:
+		request := &transactions.CreateWalletWithdrawalRequest{}
+		if err := json.Unmarshal(held.Payload, request); err != nil {
+			return nil, fmt.Errorf("unable to deserialize held request - id: %s - err: %w", held.Id, err)
+		}
+		return transactions.NewTransactionsService(q.client).CreateWalletWithdrawal(ctx, request)
+	case OperationCreateOnchainTransaction:
+		request := &transactions.CreateOnchainTransactionRequest{}
+		if err := json.Unmarshal(held.Payload, request); err != nil {
+			return nil, fmt.Errorf("unable to deserialize held request - id: %s - err: %w", held.Id, err)
+		}
+		return transactions.NewTransactionsService(q.client).CreateOnchainTransaction(ctx, request)
+	case OperationCreatePortfolioAllocations:
+		request := &allocations.CreatePortfolioAllocationsRequest{}
+		if err := json.Unmarshal(held.Payload, request); err != nil {
+			return nil, fmt.Errorf("unable to deserialize held request - id: %s - err: %w", held.Id, err)
+		}
+		return allocations.NewAllocationsService(q.client).CreatePortfolioAllocations(ctx, request)
+	}
+
+	return nil, fmt.Errorf("unsupported operation: %s - id: %s", held.Operation, held.Id)
+}
+
+func operationOf(request interface{}) string {
+	switch request.(type) {
+	case *orders.CreateOrderRequest:
+		return OperationCreateOrder
+	case *orders.EditOrderRequest:
+		return OperationEditOrder
+	case *quotes.AcceptQuoteRequest:
+		return OperationAcceptQuote
+	case *transactions.CreateWalletTransferRequest:
+		return OperationCreateWalletTransfer
+	case *transactions.CreateWalletWithdrawalRequest:
+		return OperationCreateWalletWithdrawal
+	case *transactions.CreateOnchainTransactionRequest:
+		return OperationCreateOnchainTransaction
+	case *allocations.CreatePortfolioAllocationsRequest:
+		return OperationCreatePortfolioAllocations
+	}
+	return ""
+}
@@ -0,0 +1,462 @@
+/**
+ * Copyright 2024-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package approval
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/coinbase-samples/prime-sdk-go/client"
+	"github.com/coinbase-samples/prime-sdk-go/credentials"
+	"github.com/coinbase-samples/prime-sdk-go/model"
+	"github.com/coinbase-samples/prime-sdk-go/orders"
+	"github.com/coinbase-samples/prime-sdk-go/policy"
+	"github.com/coinbase-samples/prime-sdk-go/quotes"
+	"github.com/coinbase-samples/prime-sdk-go/transactions"
+	"github.com/shopspring/decimal"
+)
+
+func TestQueue(t *testing.T) {
+
+	var sent []map[string]interface{}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body := make(map[string]interface{})
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Error(err)
+		}
+		sent = append(sent, body)
+		w.Write([]byte(`{"activity_id":"a1","transaction_id":"t1","order_id":"o1"}`))
+	}))
+	defer server.Close()
+
+	dir := t.TempDir()
+
+	store, err := NewFileStore(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	c := client.NewRestClient(&credentials.Credentials{}, http.Client{}).SetBaseUrl(server.URL)
+
+	queue := NewQueue(c, store, "alice")
+	queue.Hold = func(operation string, request interface{}) bool {
+		r, ok := request.(*orders.CreateOrderRequest)
+		return !ok || r.Order.BaseQuantity != "0.01"
+	}
+
+	if err := client.AddRequestHook(c, queue.RequestHook()); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	// Small orders are not held
+	if _, err := orders.NewOrdersService(c).CreateOrder(ctx, &orders.CreateOrderRequest{Order: &model.Order{
+		PortfolioId:  "p1",
+		ProductId:    "BTC-USD",
+		Side:         model.OrderSideBuy,
+		Type:         model.OrderTypeMarket,
+		BaseQuantity: "0.01",
+	}}); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = transactions.NewTransactionsService(c).CreateWalletTransfer(ctx, &transactions.CreateWalletTransferRequest{
+		PortfolioId:         "p1",
+		SourceWalletId:      "w1",
+		DestinationWalletId: "w2",
+		Symbol:              "ETH",
+		Amount:              "500",
+		Reference:           "rebalance-1",
+	})
+
+	var held *HeldError
+	if !errors.As(err, &held) || !errors.Is(err, ErrHeld) {
+		t.Fatalf("test: TestQueue - expected: %v - received: %v", ErrHeld, err)
+	}
+
+	if len(sent) != 1 {
+		t.Fatalf("test: TestQueue - expected: 1 request sent - received: %d", len(sent))
+	}
+
+	pending, err := queue.Pending(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(pending) != 1 || pending[0].Id != held.Id || pending[0].Hash != held.Hash {
+		t.Fatalf("test: TestQueue - expected the held transfer to be pending - received: %v", pending)
+	}
+
+	// A second process, e.g., the approval CLI, shares the store
+	approver := NewQueue(nil, store, "")
+
+	cases := []struct {
+		description string
+		approver    string
+		hash        string
+		expected    error
+	}{
+		{
+			description: "TestQueue0",
+			approver:    "alice",
+			hash:        held.Hash,
+			expected:    ErrSelfApproval,
+		},
+		{
+			description: "TestQueue1",
+			approver:    "bob",
+			hash:        Hash(OperationCreateWalletTransfer, []byte("{}")),
+			expected:    ErrHashMismatch,
+		},
+		{
+			description: "TestQueue2",
+			hash:        held.Hash,
+			expected:    ErrApproverUnset,
+		},
+		{
+			description: "TestQueue3",
+			approver:    "bob",
+			hash:        held.Hash,
+		},
+		{
+			description: "TestQueue4",
+			approver:    "carol",
+			hash:        held.Hash,
+			expected:    ErrNotPending,
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.description, func(t *testing.T) {
+			_, err := approver.Approve(ctx, held.Id, tt.approver, tt.hash)
+			if !errors.Is(err, tt.expected) {
+				t.Errorf("test: %s - expected: %v - received: %v", tt.description, tt.expected, err)
+			}
+		})
+	}
+
+	if _, err := approver.Submit(ctx, held.Id); err == nil {
+		t.Errorf("test: TestQueue - expected an error submitting without a client")
+	}
+
+	submitted, err := queue.Submit(ctx, held.Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if submitted.Status != StatusSubmitted || submitted.DecidedBy != "bob" || len(submitted.Response) == 0 {
+		t.Errorf("test: TestQueue - expected: %s - received: %+v", StatusSubmitted, submitted)
+	}
+
+	if _, err := queue.Submit(ctx, held.Id); !errors.Is(err, ErrNotApproved) {
+		t.Errorf("test: TestQueue - expected: %v - received: %v", ErrNotApproved, err)
+	}
+
+	if len(sent) != 2 {
+		t.Fatalf("test: TestQueue - expected: 2 requests sent - received: %d", len(sent))
+	}
+
+	if sent[1]["amount"] != "500" || sent[1]["idempotency_key"] == "" {
+		t.Errorf("test: TestQueue - unexpected transfer sent: %v", sent[1])
+	}
+
+	if matches, _ := filepath.Glob(filepath.Join(dir, "*.lock")); len(matches) != 0 {
+		t.Errorf("test: TestQueue - expected no lock files - received: %v", matches)
+	}
+}
+
+func TestTamperedRequest(t *testing.T) {
+
+	dir := t.TempDir()
+
+	store, err := NewFileStore(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	queue := NewQueue(nil, store, "alice")
+
+	ctx := context.Background()
+
+	err = queue.RequestHook()(ctx, &transactions.CreateWalletWithdrawalRequest{
+		PortfolioId:    "p1",
+		SourceWalletId: "w1",
+		Symbol:         "USDC",
+		Amount:         "1000",
+		IdempotencyKey: "k1",
+	})
+
+	var held *HeldError
+	if !errors.As(err, &held) {
+		t.Fatalf("test: TestTamperedRequest - expected: %v - received: %v", ErrHeld, err)
+	}
+
+	path := filepath.Join(dir, held.Id+".json")
+
+	r, err := store.Get(ctx, held.Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	r.Payload = json.RawMessage(`{"portfolio_id":"p1","wallet_id":"w1","currency_symbol":"USDC","amount":"1000000","idempotency_key":"k1"}`)
+
+	b, err := json.Marshal(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := os.WriteFile(path, b, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := queue.Approve(ctx, held.Id, "bob", held.Hash); !errors.Is(err, ErrTampered) {
+		t.Errorf("test: TestTamperedRequest - expected: %v - received: %v", ErrTampered, err)
+	}
+
+	rejected, err := queue.Reject(ctx, held.Id, "bob", "amount changed")
+	if !errors.Is(err, ErrTampered) || rejected != nil {
+		t.Errorf("test: TestTamperedRequest - expected: %v - received: %v", ErrTampered, err)
+	}
+}
+
+func TestQueueWithPolicy(t *testing.T) {
+
+	var sent int
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		sent++
+		w.Write([]byte(`{"activity_id":"a1","approval_url":"u1","symbol":"USDC","amount":"6"}`))
+	}))
+	defer server.Close()
+
+	store, err := NewFileStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	c := client.NewRestClient(&credentials.Credentials{}, http.Client{}).SetBaseUrl(server.URL)
+
+	engine, err := policy.NewEngine(&policy.Rules{MaxDailyWithdrawal: map[string]decimal.Decimal{"USDC": decimal.NewFromInt(10)}}, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	engine.OnDecision = func(d *policy.Decision) {}
+
+	queue := NewQueue(c, store, "alice")
+
+	if err := client.AddRequestHook(c, engine.RequestHook()); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := client.AddRequestHook(c, queue.RequestHook()); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := client.AddResponseHook(c, engine.ResponseHook()); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	_, err = transactions.NewTransactionsService(c).CreateWalletWithdrawal(ctx, &transactions.CreateWalletWithdrawalRequest{
+		PortfolioId:     "p1",
+		SourceWalletId:  "w1",
+		Symbol:          "USDC",
+		Amount:          "6",
+		DestinationType: "DESTINATION_BLOCKCHAIN",
+	})
+
+	var held *HeldError
+	if !errors.As(err, &held) {
+		t.Fatalf("test: TestQueueWithPolicy - expected: %v - received: %v", ErrHeld, err)
+	}
+
+	if _, err := queue.Approve(ctx, held.Id, "bob", held.Hash); err != nil {
+		t.Fatal(err)
+	}
+
+	submitted, err := queue.Submit(ctx, held.Id)
+	if err != nil {
+		t.Fatalf("test: TestQueueWithPolicy - expected: nil - received: %v", err)
+	}
+
+	if submitted.Status != StatusSubmitted || sent != 1 {
+		t.Errorf("test: TestQueueWithPolicy - expected: %s - received: %s - sent: %d", StatusSubmitted, submitted.Status, sent)
+	}
+}
+
+func TestChangedAfterApproval(t *testing.T) {
+
+	dir := t.TempDir()
+
+	store, err := NewFileStore(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("unexpected request: %s %s", r.Method, r.URL)
+	}))
+	defer server.Close()
+
+	c := client.NewRestClient(&credentials.Credentials{}, http.Client{}).SetBaseUrl(server.URL)
+
+	queue := NewQueue(c, store, "alice")
+
+	ctx := context.Background()
+
+	err = queue.RequestHook()(ctx, &transactions.CreateWalletWithdrawalRequest{
+		PortfolioId:    "p1",
+		SourceWalletId: "w1",
+		Symbol:         "USDC",
+		Amount:         "1000",
+		IdempotencyKey: "k1",
+	})
+
+	var held *HeldError
+	if !errors.As(err, &held) {
+		t.Fatalf("test: TestChangedAfterApproval - expected: %v - received: %v", ErrHeld, err)
+	}
+
+	approved, err := queue.Approve(ctx, held.Id, "bob", held.Hash)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if approved.ApprovedHash != held.Hash {
+		t.Errorf("test: TestChangedAfterApproval - expected: %s - received: %s", held.Hash, approved.ApprovedHash)
+	}
+
+	// Change the payload and its hash, but not the approved hash
+	approved.Payload = json.RawMessage(`{"portfolio_id":"p1","wallet_id":"w1","currency_symbol":"USDC","amount":"1000000","idempotency_key":"k1"}`)
+	approved.Hash = Hash(approved.Operation, approved.Payload)
+
+	b, err := json.Marshal(approved)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, held.Id+".json"), b, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := queue.Submit(ctx, held.Id); !errors.Is(err, ErrChanged) {
+		t.Errorf("test: TestChangedAfterApproval - expected: %v - received: %v", ErrChanged, err)
+	}
+}
+
+func TestQueueOperations(t *testing.T) {
+
+	var sent []string
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		sent = append(sent, r.URL.Path)
+		w.Write([]byte(`{"order_id":"o1","transaction_id":"t1"}`))
+	}))
+	defer server.Close()
+
+	store, err := NewFileStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	c := client.NewRestClient(&credentials.Credentials{}, http.Client{}).SetBaseUrl(server.URL)
+
+	queue := NewQueue(c, store, "alice")
+
+	if err := client.AddRequestHook(c, queue.RequestHook()); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	cases := []struct {
+		description string
+		operation   string
+		path        string
+		call        func() error
+	}{
+		{
+			description: "TestQueueOperations0",
+			operation:   OperationEditOrder,
+			path:        "/portfolios/p1/orders/o1/edit",
+			call: func() error {
+				_, err := orders.NewOrdersService(c).EditOrder(ctx, &orders.EditOrderRequest{PortfolioId: "p1", OrderId: "o1", BaseQuantity: "2"})
+				return err
+			},
+		},
+		{
+			description: "TestQueueOperations1",
+			operation:   OperationAcceptQuote,
+			path:        "/portfolios/p1/accept_quote",
+			call: func() error {
+				_, err := quotes.NewQuotesService(c).AcceptQuote(ctx, &quotes.AcceptQuoteRequest{PortfolioId: "p1", ProductId: "BTC-USD", QuoteId: "q1"})
+				return err
+			},
+		},
+		{
+			description: "TestQueueOperations2",
+			operation:   OperationCreateOnchainTransaction,
+			path:        "/portfolios/p1/wallets/w1/onchain_transaction",
+			call: func() error {
+				_, err := transactions.NewTransactionsService(c).CreateOnchainTransaction(ctx, &transactions.CreateOnchainTransactionRequest{PortfolioId: "p1", WalletId: "w1", RawUnsignedTransaction: "02f8"})
+				return err
+			},
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.description, func(t *testing.T) {
+
+			sent = nil
+
+			var held *HeldError
+			if err := tt.call(); !errors.As(err, &held) || held.Operation != tt.operation {
+				t.Fatalf("test: %s - expected: %s held - received: %v", tt.description, tt.operation, err)
+			}
+
+			if len(sent) != 0 {
+				t.Fatalf("test: %s - expected the request to be held - sent: %v", tt.description, sent)
+			}
+
+			if _, err := queue.Approve(ctx, held.Id, "bob", held.Hash); err != nil {
+				t.Fatal(err)
+			}
+
+			submitted, err := queue.Submit(ctx, held.Id)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if submitted.Status != StatusSubmitted || len(sent) != 1 || sent[0] != tt.path {
+				t.Errorf("test: %s - expected: %s sent to %s - received: %s - sent: %v", tt.description, StatusSubmitted, tt.path, submitted.Status, sent)
+			}
+		})
+	}
+}
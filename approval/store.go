@@ -0,0 +1,279 @@
+/**
+ * Copyright 2024-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package approval
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Store persists held requests. Update must apply the function and persist
+// the result atomically with respect to other updates of the same request,
+// since status transitions rely on it to submit a request exactly once.
+type Store interface {
+	Create(ctx context.Context, r *HeldRequest) error
+	Get(ctx context.Context, id string) (*HeldRequest, error)
+	List(ctx context.Context) ([]*HeldRequest, error)
+
+	// Update calls fn with the current request and persists it unless fn
+	// returns an error.
+	Update(ctx context.Context, id string, fn func(r *HeldRequest) error) (*HeldRequest, error)
+}
+
+// NewMemoryStore returns a store that keeps held requests in memory. It is
+// only useful when requests are approved in the same process.
+func NewMemoryStore() Store {
+	return &memoryStore{requests: make(map[string]*HeldRequest)}
+}
+
+type memoryStore struct {
+	requests map[string]*HeldRequest
+	mu       sync.Mutex
+}
+
+func (s *memoryStore) Create(ctx context.Context, r *HeldRequest) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.requests[r.Id]; ok {
+		return fmt.Errorf("held request exists: %s", r.Id)
+	}
+
+	c := *r
+	s.requests[r.Id] = &c
+	return nil
+}
+
+func (s *memoryStore) Get(ctx context.Context, id string) (*HeldRequest, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	r, ok := s.requests[id]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrNotFound, id)
+	}
+
+	c := *r
+	return &c, nil
+}
+
+func (s *memoryStore) List(ctx context.Context) ([]*HeldRequest, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	requests := make([]*HeldRequest, 0, len(s.requests))
+	for _, r := range s.requests {
+		c := *r
+		requests = append(requests, &c)
+	}
+
+	sortRequests(requests)
+
+	return requests, nil
+}
+
+func (s *memoryStore) Update(ctx context.Context, id string, fn func(r *HeldRequest) error) (*HeldRequest, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	r, ok := s.requests[id]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrNotFound, id)
+	}
+
+	c := *r
+	if err := fn(&c); err != nil {
+		return nil, err
+	}
+
+	s.requests[id] = &c
+
+	u := c
+	return &u, nil
+}
+
+// NewFileStore returns a store that keeps each held request as a JSON file
+// in dir, so requests can be approved from another process, e.g., the
+// approval CLI. Updates take a lock file next to the request, so concurrent
+// processes cannot both submit it. A lock file left behind by a crash must
+// be removed by hand after checking the request.
+func NewFileStore(dir string) (Store, error) {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, fmt.Errorf("unable to create held request dir: %s - err: %w", dir, err)
+	}
+	return &fileStore{dir: dir}, nil
+}
+
+type fileStore struct {
+	dir string
+	mu  sync.Mutex
+}
+
+func (s *fileStore) Create(ctx context.Context, r *HeldRequest) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, err := os.Stat(s.path(r.Id)); err == nil {
+		return fmt.Errorf("held request exists: %s", r.Id)
+	}
+
+	return s.save(r)
+}
+
+func (s *fileStore) Get(ctx context.Context, id string) (*HeldRequest, error) {
+	if err := validateId(id); err != nil {
+		return nil, err
+	}
+	return s.load(id)
+}
+
+func (s *fileStore) List(ctx context.Context) ([]*HeldRequest, error) {
+
+	matches, err := filepath.Glob(filepath.Join(s.dir, "*.json"))
+	if err != nil {
+		return nil, fmt.Errorf("unable to list held requests: %w", err)
+	}
+
+	requests := make([]*HeldRequest, 0, len(matches))
+
+	for _, m := range matches {
+		r, err := s.load(strings.TrimSuffix(filepath.Base(m), ".json"))
+		if err != nil {
+			return nil, err
+		}
+		requests = append(requests, r)
+	}
+
+	sortRequests(requests)
+
+	return requests, nil
+}
+
+func (s *fileStore) Update(ctx context.Context, id string, fn func(r *HeldRequest) error) (*HeldRequest, error) {
+
+	if err := validateId(id); err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	lock, err := os.OpenFile(s.path(id)+".lock", os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("unable to lock held request: %s - err: %w", id, err)
+	}
+	lock.Close()
+	defer os.Remove(lock.Name())
+
+	r, err := s.load(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := fn(r); err != nil {
+		return nil, err
+	}
+
+	if err := s.save(r); err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+func (s *fileStore) path(id string) string {
+	return filepath.Join(s.dir, id+".json")
+}
+
+func (s *fileStore) load(id string) (*HeldRequest, error) {
+
+	b, err := os.ReadFile(s.path(id))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("%w: %s", ErrNotFound, id)
+	} else if err != nil {
+		return nil, fmt.Errorf("unable to read held request: %s - err: %w", id, err)
+	}
+
+	r := &HeldRequest{}
+	if err := json.Unmarshal(b, r); err != nil {
+		return nil, fmt.Errorf("unable to deserialize held request: %s - err: %w", id, err)
+	}
+
+	return r, nil
+}
+
+// save writes to a temporary file and renames it, so a crash never leaves
+// a partially written request behind.
+func (s *fileStore) save(r *HeldRequest) error {
+
+	b, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return fmt.Errorf("unable to serialize held request: %s - err: %w", r.Id, err)
+	}
+
+	tmp, err := os.CreateTemp(s.dir, r.Id+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("unable to create held request file: %w", err)
+	}
+
+	if _, err := tmp.Write(b); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return fmt.Errorf("unable to write held request: %s - err: %w", r.Id, err)
+	}
+
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return fmt.Errorf("unable to sync held request: %s - err: %w", r.Id, err)
+	}
+
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return fmt.Errorf("unable to close held request: %s - err: %w", r.Id, err)
+	}
+
+	if err := os.Rename(tmp.Name(), s.path(r.Id)); err != nil {
+		os.Remove(tmp.Name())
+		return fmt.Errorf("unable to save held request: %s - err: %w", r.Id, err)
+	}
+
+	return nil
+}
+
+func validateId(id string) error {
+	if len(id) == 0 || strings.ContainsAny(id, `/\.`) {
+		return fmt.Errorf("invalid held request id: %q", id)
+	}
+	return nil
+}
+
+func sortRequests(requests []*HeldRequest) {
+	sort.Slice(requests, func(i, j int) bool {
+		if requests[i].Requested.Equal(requests[j].Requested) {
+			return requests[i].Id < requests[j].Id
+		}
+		return requests[i].Requested.Before(requests[j].Requested)
+	})
+}
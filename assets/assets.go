@@ -24,6 +24,7 @@ import (
 
 type AssetsService interface {
 	ListAssets(ctx context.Context, request *ListAssetsRequest) (*ListAssetsResponse, error)
+	ListAssetNetworks(ctx context.Context, request *ListAssetNetworksRequest) (*ListAssetNetworksResponse, error)
 }
 
 func NewAssetsService(c client.RestClient) AssetsService {
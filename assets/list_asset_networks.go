@@ -0,0 +1,65 @@
+/**
+ * Copyright 2024-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package assets
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/coinbase-samples/prime-sdk-go/model"
+)
+
+type ListAssetNetworksRequest struct {
+	EntityId string `json:"entity_id"`
+	Symbol   string `json:"symbol"`
+}
+
+type ListAssetNetworksResponse struct {
+	Asset    *model.Asset              `json:"asset"`
+	Networks []*model.AssetNetwork     `json:"networks"`
+	Request  *ListAssetNetworksRequest `json:"request"`
+}
+
+// ListAssetNetworks returns the networks an asset is supported on. Use
+// Asset.CheapestNetwork to pick the rail for a withdrawal.
+func (s *assetsServiceImpl) ListAssetNetworks(
+	ctx context.Context,
+	request *ListAssetNetworksRequest,
+) (*ListAssetNetworksResponse, error) {
+
+	assets, err := s.ListAssets(ctx, &ListAssetsRequest{EntityId: request.EntityId})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, a := range assets.Assets {
+		if a.Symbol == request.Symbol {
+			return &ListAssetNetworksResponse{Asset: a, Networks: a.Networks, Request: request}, nil
+		}
+	}
+
+	return nil, fmt.Errorf("asset not found: %s", request.Symbol)
+}
+
+// NetworksBySymbol indexes the networks of assets by symbol.
+func NetworksBySymbol(assets []*model.Asset) map[string][]*model.AssetNetwork {
+	networks := make(map[string][]*model.AssetNetwork, len(assets))
+	for _, a := range assets {
+		networks[a.Symbol] = a.Networks
+	}
+	return networks
+}
@@ -0,0 +1,132 @@
+/**
+ * Copyright 2024-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package assets
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/coinbase-samples/prime-sdk-go/client"
+	"github.com/coinbase-samples/prime-sdk-go/credentials"
+	"github.com/coinbase-samples/prime-sdk-go/model"
+	"github.com/shopspring/decimal"
+)
+
+func TestCheapestNetwork(t *testing.T) {
+
+	usdc := &model.Asset{
+		Symbol: "USDC",
+		Networks: []*model.AssetNetwork{
+			{
+				Network:             &model.Network{Id: "ethereum-mainnet"},
+				Default:             true,
+				MinWithdrawalAmount: "1",
+				WithdrawalFee:       "5",
+			},
+			{
+				Network:             &model.Network{Id: "base-mainnet"},
+				MinWithdrawalAmount: "10",
+				MaxWithdrawalAmount: "1000",
+				WithdrawalFee:       "0.01",
+			},
+			{
+				Network:             &model.Network{Id: "solana-mainnet"},
+				MinWithdrawalAmount: "5",
+				WithdrawalFee:       "0.5",
+			},
+		},
+	}
+
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("/entities/e1/assets", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]interface{}{"assets": []*model.Asset{{Symbol: "BTC"}, usdc}})
+	})
+
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	svc := NewAssetsService(client.NewRestClient(&credentials.Credentials{}, http.Client{}).SetBaseUrl(server.URL))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	response, err := svc.ListAssetNetworks(ctx, &ListAssetNetworksRequest{EntityId: "e1", Symbol: "USDC"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(response.Networks) != 3 {
+		t.Fatalf("expected: 3 networks - received: %d", len(response.Networks))
+	}
+
+	cases := []struct {
+		description string
+		amount      string
+		expected    string
+	}{
+		{
+			description: "TestCheapestNetwork0",
+			amount:      "100",
+			expected:    "base-mainnet",
+		},
+		{
+			description: "TestCheapestNetwork1",
+			amount:      "6",
+			expected:    "solana-mainnet",
+		},
+		{
+			description: "TestCheapestNetwork2",
+			amount:      "2",
+			expected:    "ethereum-mainnet",
+		},
+		{
+			description: "TestCheapestNetwork3",
+			amount:      "5000",
+			expected:    "solana-mainnet",
+		},
+		{
+			description: "TestCheapestNetwork4",
+			amount:      "0.5",
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.description, func(t *testing.T) {
+			n, err := response.Asset.CheapestNetwork(decimal.RequireFromString(tt.amount))
+
+			if len(tt.expected) == 0 {
+				if err == nil {
+					t.Errorf("test: %s - expected an error - received: %s", tt.description, n.Network.Id)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("test: %s - unexpected err: %v", tt.description, err)
+			}
+
+			if n.Network.Id != tt.expected {
+				t.Errorf("test: %s - expected: %s - received: %s", tt.description, tt.expected, n.Network.Id)
+			}
+		})
+	}
+}
@@ -24,6 +24,7 @@ import (
 
 type BalancesService interface {
 	ListPortfolioBalances(ctx context.Context, request *ListPortfolioBalancesRequest) (*ListPortfolioBalancesResponse, error)
+	ListEntityBalances(ctx context.Context, request *ListEntityBalancesRequest) (*ListEntityBalancesResponse, error)
 	GetWalletBalance(ctx context.Context, request *GetWalletBalanceRequest) (*GetWalletBalanceResponse, error)
 	ListOnchainWalletBalances(ctx context.Context, request *ListOnchainWalletBalancesRequest) (*ListOnchainWalletBalancesResponse, error)
 }
@@ -0,0 +1,48 @@
+/**
+ * Copyright 2024-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package client
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/coinbase-samples/core-go"
+)
+
+// IsRetryable returns true if err is likely to be transient: a transport
+// error, a 429 or a 5xx response. Other responses, e.g., 401 or 404, and
+// errors that are not from the API, e.g., a body that does not decode, are
+// not worth retrying. Check the context before retrying, since a cancelled
+// call is reported as a transport error.
+func IsRetryable(err error) bool {
+
+	var apiErr *core.ApiError
+	if !errors.As(err, &apiErr) {
+		return false
+	}
+
+	switch {
+	case apiErr.CodeReceived == 0:
+		return true
+	case apiErr.CodeReceived == http.StatusTooManyRequests:
+		return true
+	case apiErr.CodeReceived >= http.StatusInternalServerError:
+		return true
+	}
+
+	return false
+}
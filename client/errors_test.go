@@ -0,0 +1,70 @@
+/**
+ * Copyright 2024-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package client
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/coinbase-samples/core-go"
+)
+
+func TestIsRetryable(t *testing.T) {
+
+	cases := []struct {
+		description string
+		err         error
+		expected    bool
+	}{
+		{
+			description: "TestIsRetryable0",
+			err:         &core.ApiError{CodeReceived: 0},
+			expected:    true,
+		},
+		{
+			description: "TestIsRetryable1",
+			err:         fmt.Errorf("wrapped: %w", &core.ApiError{CodeReceived: 503}),
+			expected:    true,
+		},
+		{
+			description: "TestIsRetryable2",
+			err:         &core.ApiError{CodeReceived: 429},
+			expected:    true,
+		},
+		{
+			description: "TestIsRetryable3",
+			err:         &core.ApiError{CodeReceived: 401},
+		},
+		{
+			description: "TestIsRetryable4",
+			err:         &core.ApiError{CodeReceived: 404},
+		},
+		{
+			description: "TestIsRetryable5",
+			err:         errors.New("unexpected end of JSON input"),
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.description, func(t *testing.T) {
+			if received := IsRetryable(tt.err); received != tt.expected {
+				t.Errorf("test: %s - expected: %v - received: %v", tt.description, tt.expected, received)
+			}
+		})
+	}
+}
@@ -0,0 +1,128 @@
+/**
+ * Copyright 2024-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package client
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// RateLimiter blocks until a request is allowed to be sent or the context
+// is done.
+type RateLimiter interface {
+	Wait(ctx context.Context) error
+}
+
+// RateLimitedClient is implemented by clients that can limit the rate of all
+// requests they send, including the client returned by NewRestClient. It is
+// separate from RestClient, so other implementations, e.g., mocks, do not
+// need to support it.
+type RateLimitedClient interface {
+	SetRateLimiter(l RateLimiter) RestClient
+	RateLimiter() RateLimiter
+}
+
+// SetRateLimiter sets the rate limiter of a client that implements
+// RateLimitedClient. Pass nil to remove a previously set limiter.
+func SetRateLimiter(c RestClient, l RateLimiter) error {
+	rc, ok := c.(RateLimitedClient)
+	if !ok {
+		return fmt.Errorf("client does not support rate limiting: %T", c)
+	}
+	rc.SetRateLimiter(l)
+	return nil
+}
+
+// NewRateLimiter returns a token bucket rate limiter that allows
+// requestsPerSecond on average and bursts of up to burst requests. It panics
+// if requestsPerSecond is not positive, like time.NewTicker does for its
+// duration.
+func NewRateLimiter(requestsPerSecond float64, burst int) RateLimiter {
+	if !(requestsPerSecond > 0) || math.IsInf(requestsPerSecond, 1) {
+		panic(fmt.Sprintf("non-positive or infinite rate for NewRateLimiter: %v", requestsPerSecond))
+	}
+
+	if burst < 1 {
+		burst = 1
+	}
+
+	return &tokenBucketLimiter{
+		rate:   requestsPerSecond,
+		burst:  float64(burst),
+		tokens: float64(burst),
+		last:   time.Now(),
+	}
+}
+
+type tokenBucketLimiter struct {
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+	mu     sync.Mutex
+}
+
+func (l *tokenBucketLimiter) Wait(ctx context.Context) error {
+	for {
+		l.mu.Lock()
+
+		now := time.Now()
+		l.tokens = math.Min(l.burst, l.tokens+now.Sub(l.last).Seconds()*l.rate)
+		l.last = now
+
+		if l.tokens >= 1 {
+			l.tokens--
+			l.mu.Unlock()
+			return nil
+		}
+
+		wait := time.Duration((1 - l.tokens) / l.rate * float64(time.Second))
+		l.mu.Unlock()
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// rateLimitedTransport waits on the limiter before each round trip, so every
+// service call made through the client shares the same limit.
+type rateLimitedTransport struct {
+	next    http.RoundTripper
+	limiter RateLimiter
+}
+
+func (t *rateLimitedTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if err := t.limiter.Wait(req.Context()); err != nil {
+		return nil, err
+	}
+
+	next := t.next
+	if next == nil {
+		next = http.DefaultTransport
+	}
+
+	return next.RoundTrip(req)
+}
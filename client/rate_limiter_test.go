@@ -0,0 +1,124 @@
+/**
+ * Copyright 2024-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package client
+
+import (
+	"context"
+	"math"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/coinbase-samples/prime-sdk-go/credentials"
+)
+
+func TestRateLimiter(t *testing.T) {
+
+	limiter := NewRateLimiter(50, 2)
+
+	start := time.Now()
+	for idx := 0; idx < 4; idx++ {
+		if err := limiter.Wait(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Two requests are served from the burst and two wait ~20ms each
+	if elapsed := time.Since(start); elapsed < 30*time.Millisecond {
+		t.Errorf("expected the limiter to delay requests - elapsed: %v", elapsed)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if err := NewRateLimiter(0.001, 1).Wait(ctx); err != nil {
+		t.Fatalf("expected the burst to allow the first request: %v", err)
+	}
+
+	slow := NewRateLimiter(0.001, 1)
+	slow.Wait(context.Background())
+
+	if err := slow.Wait(ctx); err == nil {
+		t.Error("expected an error when the context is done")
+	}
+}
+
+func TestNewRateLimiterInvalidRate(t *testing.T) {
+
+	cases := []struct {
+		description string
+		rate        float64
+	}{
+		{
+			description: "TestNewRateLimiterInvalidRate0",
+			rate:        0,
+		},
+		{
+			description: "TestNewRateLimiterInvalidRate1",
+			rate:        -1,
+		},
+		{
+			description: "TestNewRateLimiterInvalidRate2",
+			rate:        math.NaN(),
+		},
+		{
+			description: "TestNewRateLimiterInvalidRate3",
+			rate:        math.Inf(1),
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.description, func(t *testing.T) {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("test: %s - expected a panic for rate: %v", tt.description, tt.rate)
+				}
+			}()
+			NewRateLimiter(tt.rate, 1)
+		})
+	}
+}
+
+func TestSetRateLimiter(t *testing.T) {
+
+	c := NewRestClient(&credentials.Credentials{}, http.Client{})
+
+	if err := SetRateLimiter(c, NewRateLimiter(10, 1)); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := SetRateLimiter(c, NewRateLimiter(20, 1)); err != nil {
+		t.Fatal(err)
+	}
+
+	transport, ok := c.HttpClient().Transport.(*rateLimitedTransport)
+	if !ok {
+		t.Fatal("expected a rate limited transport")
+	}
+
+	if transport.next != nil {
+		t.Error("expected the previous limiter to be replaced, not wrapped")
+	}
+
+	if err := SetRateLimiter(c, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	if c.HttpClient().Transport != nil || c.(RateLimitedClient).RateLimiter() != nil {
+		t.Error("expected the limiter to be removed")
+	}
+}
@@ -0,0 +1,99 @@
+/**
+ * Copyright 2024-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package client
+
+import (
+	"context"
+	"fmt"
+)
+
+// RequestHook is called with the request of a mutating service call, e.g.,
+// *orders.CreateOrderRequest or *transactions.CreateWalletWithdrawalRequest,
+// before it is sent. Hooks switch on the request types they check and
+// ignore the rest. A non-nil error blocks the call and is returned to the
+// caller.
+type RequestHook func(ctx context.Context, request interface{}) error
+
+// ResponseHook is called with the request of a mutating service call and
+// its error once the call is done, including when a request hook blocked
+// it. Hooks use it to undo what their request hook did for a call that
+// failed, e.g., release a reservation.
+type ResponseHook func(ctx context.Context, request interface{}, err error)
+
+// HookedClient is implemented by clients that run request hooks, including
+// the client returned by NewRestClient. It is separate from RestClient, so
+// other implementations, e.g., mocks, do not need to support it.
+type HookedClient interface {
+	AddRequestHook(h RequestHook) RestClient
+	RequestHooks() []RequestHook
+
+	AddResponseHook(h ResponseHook) RestClient
+	ResponseHooks() []ResponseHook
+}
+
+// AddRequestHook adds a hook to a client that implements HookedClient.
+func AddRequestHook(c RestClient, h RequestHook) error {
+	hc, ok := c.(HookedClient)
+	if !ok {
+		return fmt.Errorf("client does not support request hooks: %T", c)
+	}
+	hc.AddRequestHook(h)
+	return nil
+}
+
+// AddResponseHook adds a hook to a client that implements HookedClient.
+func AddResponseHook(c RestClient, h ResponseHook) error {
+	hc, ok := c.(HookedClient)
+	if !ok {
+		return fmt.Errorf("client does not support response hooks: %T", c)
+	}
+	hc.AddResponseHook(h)
+	return nil
+}
+
+// RunRequestHooks calls the hooks of the client in the order they were added
+// and returns the first error. If a hook blocks the call, the response hooks
+// are run with its error. A client that does not implement HookedClient has
+// no hooks.
+func RunRequestHooks(ctx context.Context, c RestClient, request interface{}) error {
+	hc, ok := c.(HookedClient)
+	if !ok {
+		return nil
+	}
+
+	for _, h := range hc.RequestHooks() {
+		if err := h(ctx, request); err != nil {
+			RunResponseHooks(ctx, c, request, err)
+			return err
+		}
+	}
+	return nil
+}
+
+// RunResponseHooks calls the response hooks of the client in the order they
+// were added. Services call it after sending a request that passed the
+// request hooks.
+func RunResponseHooks(ctx context.Context, c RestClient, request interface{}, err error) {
+	hc, ok := c.(HookedClient)
+	if !ok {
+		return
+	}
+
+	for _, h := range hc.ResponseHooks() {
+		h(ctx, request, err)
+	}
+}
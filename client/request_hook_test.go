@@ -0,0 +1,112 @@
+/**
+ * Copyright 2024-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package client
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"testing"
+
+	"github.com/coinbase-samples/prime-sdk-go/credentials"
+)
+
+func TestRequestHooks(t *testing.T) {
+
+	c := NewRestClient(&credentials.Credentials{}, http.Client{})
+
+	var calls int
+	var mu sync.Mutex
+
+	hook := func(ctx context.Context, request interface{}) error {
+		mu.Lock()
+		defer mu.Unlock()
+		calls++
+		return nil
+	}
+
+	var wg sync.WaitGroup
+	for idx := 0; idx < 10; idx++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			if err := AddRequestHook(c, hook); err != nil {
+				t.Error(err)
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			if err := RunRequestHooks(context.Background(), c, struct{}{}); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if hooks := c.(HookedClient).RequestHooks(); len(hooks) != 10 {
+		t.Errorf("expected: 10 hooks - received: %d", len(hooks))
+	}
+
+	if err := AddRequestHook(nil, hook); err == nil {
+		t.Error("expected an error for a client without hooks")
+	}
+}
+
+func TestResponseHooks(t *testing.T) {
+
+	c := NewRestClient(&credentials.Credentials{}, http.Client{})
+
+	blocked := errors.New("blocked")
+
+	var received []error
+
+	if err := AddRequestHook(c, func(ctx context.Context, request interface{}) error {
+		if request == "block" {
+			return blocked
+		}
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := AddResponseHook(c, func(ctx context.Context, request interface{}, err error) {
+		received = append(received, err)
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := RunRequestHooks(context.Background(), c, "send"); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(received) != 0 {
+		t.Errorf("expected: 0 response hook calls - received: %d", len(received))
+	}
+
+	if err := RunRequestHooks(context.Background(), c, "block"); err != blocked {
+		t.Errorf("expected: %v - received: %v", blocked, err)
+	}
+
+	if len(received) != 1 || received[0] != blocked {
+		t.Errorf("expected: [%v] - received: %v", blocked, received)
+	}
+
+	if err := AddResponseHook(nil, nil); err == nil {
+		t.Error("expected an error for a client without hooks")
+	}
+}
@@ -23,6 +23,7 @@ import (
 	"fmt"
 	"net/http"
 	"strconv"
+	"sync"
 	"time"
 
 	"github.com/coinbase-samples/core-go"
@@ -52,6 +53,11 @@ type restClientImpl struct {
 
 	headersFunc core.HttpHeaderFunc
 	credentials *credentials.Credentials
+	rateLimiter RateLimiter
+
+	requestHooks  []RequestHook
+	responseHooks []ResponseHook
+	hooksMu       sync.RWMutex
 }
 
 func (c *restClientImpl) HttpBaseUrl() string {
@@ -80,6 +86,58 @@ func (c *restClientImpl) HeadersFunc() core.HttpHeaderFunc {
 	return c.headersFunc
 }
 
+// SetRateLimiter limits the rate of all requests sent through the client.
+// Pass nil to remove a previously set limiter.
+func (c *restClientImpl) SetRateLimiter(l RateLimiter) RestClient {
+	if t, ok := c.httpClient.Transport.(*rateLimitedTransport); ok {
+		c.httpClient.Transport = t.next
+	}
+
+	c.rateLimiter = l
+
+	if l != nil {
+		c.httpClient.Transport = &rateLimitedTransport{next: c.httpClient.Transport, limiter: l}
+	}
+
+	return c
+}
+
+func (c *restClientImpl) RateLimiter() RateLimiter {
+	return c.rateLimiter
+}
+
+// AddRequestHook adds a hook that is run before each mutating service call
+// made through the client.
+func (c *restClientImpl) AddRequestHook(h RequestHook) RestClient {
+	c.hooksMu.Lock()
+	defer c.hooksMu.Unlock()
+	c.requestHooks = append(c.requestHooks, h)
+	return c
+}
+
+// RequestHooks returns a copy of the hooks, so a hook added while a call is
+// running does not race with it.
+func (c *restClientImpl) RequestHooks() []RequestHook {
+	c.hooksMu.RLock()
+	defer c.hooksMu.RUnlock()
+	return append([]RequestHook(nil), c.requestHooks...)
+}
+
+// AddResponseHook adds a hook that is run after each mutating service call
+// made through the client.
+func (c *restClientImpl) AddResponseHook(h ResponseHook) RestClient {
+	c.hooksMu.Lock()
+	defer c.hooksMu.Unlock()
+	c.responseHooks = append(c.responseHooks, h)
+	return c
+}
+
+func (c *restClientImpl) ResponseHooks() []ResponseHook {
+	c.hooksMu.RLock()
+	defer c.hooksMu.RUnlock()
+	return append([]ResponseHook(nil), c.responseHooks...)
+}
+
 func NewRestClient(credentials *credentials.Credentials, httpClient http.Client) RestClient {
 	return &restClientImpl{
 		baseUrl:     defaultV1ApiBaseUrl,
@@ -102,7 +160,13 @@ func AddPrimeHeaders(req *http.Request, path string, body []byte, cl core.RestCl
 }
 
 func sign(method, path, timestamp, signingKey, body string) string {
+	return SignMessage(signingKey, fmt.Sprintf("%s%s%s%s", timestamp, method, path, body))
+}
+
+// SignMessage returns the base64 encoded HMAC-SHA256 signature of message.
+// This is the scheme Prime uses for REST requests and WebSocket subscriptions.
+func SignMessage(signingKey, message string) string {
 	h := hmac.New(sha256.New, []byte(signingKey))
-	h.Write([]byte(fmt.Sprintf("%s%s%s%s", timestamp, method, path, body)))
+	h.Write([]byte(message))
 	return base64.StdEncoding.EncodeToString(h.Sum(nil))
 }
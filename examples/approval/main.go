@@ -0,0 +1,185 @@
+/**
+ * Copyright 2024-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Command approval lists and decides requests held by an approval queue
+// that uses a file store:
+//
+//	approval -dir held list
+//	approval -dir held show <id>
+//	approval -dir held -user bob approve <id> <hash>
+//	approval -dir held -user bob reject <id> <reason>
+//	approval -dir held -policy policy.yaml -withdrawals withdrawals.json -validate submit <id>
+//
+// Submitting reads the credentials from PRIME_CREDENTIALS and runs the same
+// checks as the requesting process: the policy rules, with the daily
+// withdrawal totals in the withdrawals file, and address validation. The
+// policy engine has no price source here, so orders that need a reference
+// price are blocked.
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/coinbase-samples/core-go"
+	"github.com/coinbase-samples/prime-sdk-go/addressbook"
+	"github.com/coinbase-samples/prime-sdk-go/addressvalidation"
+	"github.com/coinbase-samples/prime-sdk-go/approval"
+	"github.com/coinbase-samples/prime-sdk-go/client"
+	"github.com/coinbase-samples/prime-sdk-go/credentials"
+	"github.com/coinbase-samples/prime-sdk-go/orders"
+	"github.com/coinbase-samples/prime-sdk-go/policy"
+)
+
+func main() {
+
+	dir := flag.String("dir", "held_requests", "the directory of the held request file store")
+	user := flag.String("user", "", "the approver identity, required to approve or reject")
+	policyFile := flag.String("policy", "", "the policy rules file to check submitted requests against")
+	withdrawalsFile := flag.String("withdrawals", "", "the file that keeps the daily withdrawal totals of the policy")
+	validate := flag.Bool("validate", false, "validate the destination addresses of submitted requests")
+	flag.Parse()
+
+	args := flag.Args()
+	if len(args) == 0 {
+		log.Fatalf("usage: approval [-dir dir] [-user user] [-policy file] [-withdrawals file] [-validate] list | show <id> | approve <id> <hash> | reject <id> <reason> | submit <id>")
+	}
+
+	store, err := approval.NewFileStore(*dir)
+	if err != nil {
+		log.Fatalf("unable to open store: %v", err)
+	}
+
+	ctx := context.Background()
+
+	queue := approval.NewQueue(nil, store, "")
+
+	switch args[0] {
+	case "list":
+		pending, err := queue.Pending(ctx)
+		if err != nil {
+			log.Fatalf("unable to list held requests: %v", err)
+		}
+		for _, r := range pending {
+			fmt.Printf("%s\t%s\t%s\t%s\t%s\n", r.Id, r.Operation, r.RequestedBy, r.Requested.Format(time.RFC3339), r.Hash)
+		}
+	case "show":
+		requireArgs(args, 2)
+		r, err := queue.Get(ctx, args[1])
+		if err != nil {
+			log.Fatalf("unable to get held request: %v", err)
+		}
+		printRequest(r)
+	case "approve":
+		requireArgs(args, 3)
+		r, err := queue.Approve(ctx, args[1], *user, args[2])
+		if err != nil {
+			log.Fatalf("unable to approve held request: %v", err)
+		}
+		printRequest(r)
+	case "reject":
+		requireArgs(args, 3)
+		r, err := queue.Reject(ctx, args[1], *user, strings.Join(args[2:], " "))
+		if err != nil {
+			log.Fatalf("unable to reject held request: %v", err)
+		}
+		printRequest(r)
+	case "submit":
+		requireArgs(args, 2)
+
+		credentials, err := credentials.ReadEnvCredentials("PRIME_CREDENTIALS")
+		if err != nil {
+			log.Fatalf("unable to read prime credentials: %v", err)
+		}
+
+		httpClient, err := core.DefaultHttpClient()
+		if err != nil {
+			log.Fatalf("unable to load default http client: %v", err)
+		}
+
+		restClient := client.NewRestClient(credentials, httpClient)
+
+		if *validate {
+			if err := client.AddRequestHook(restClient, addressvalidation.Validator{}.RequestHook()); err != nil {
+				log.Fatalf("unable to add request hook: %v", err)
+			}
+		}
+
+		if len(*policyFile) > 0 {
+			addPolicy(restClient, *policyFile, *withdrawalsFile)
+		}
+
+		queue = approval.NewQueue(restClient, store, "")
+
+		r, err := queue.Submit(ctx, args[1])
+		if r != nil {
+			printRequest(r)
+		}
+		if err != nil {
+			log.Fatalf("unable to submit held request: %v", err)
+		}
+	default:
+		log.Fatalf("unknown command: %s", args[0])
+	}
+}
+
+func addPolicy(restClient client.RestClient, policyFile, withdrawalsFile string) {
+
+	rules, err := policy.LoadRulesFile(policyFile)
+	if err != nil {
+		log.Fatalf("unable to load policy rules: %v", err)
+	}
+
+	engine, err := policy.NewEngine(rules, addressbook.NewAddressBookService(restClient), nil)
+	if err != nil {
+		log.Fatalf("unable to create policy engine: %v", err)
+	}
+
+	engine.Orders = orders.NewOrdersService(restClient)
+
+	if len(withdrawalsFile) > 0 {
+		if engine.Withdrawals, err = policy.NewFileWithdrawalCounter(withdrawalsFile); err != nil {
+			log.Fatalf("unable to open withdrawal counter: %v", err)
+		}
+	}
+
+	if err := client.AddRequestHook(restClient, engine.RequestHook()); err != nil {
+		log.Fatalf("unable to add request hook: %v", err)
+	}
+
+	if err := client.AddResponseHook(restClient, engine.ResponseHook()); err != nil {
+		log.Fatalf("unable to add response hook: %v", err)
+	}
+}
+
+func requireArgs(args []string, n int) {
+	if len(args) < n {
+		log.Fatalf("missing arguments for command: %s", args[0])
+	}
+}
+
+func printRequest(r *approval.HeldRequest) {
+	b, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		log.Fatalf("unable to serialize held request: %v", err)
+	}
+	fmt.Println(string(b))
+}
@@ -0,0 +1,66 @@
+/**
+ * Copyright 2024-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package financing
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/coinbase-samples/core-go"
+	"github.com/coinbase-samples/prime-sdk-go/client"
+)
+
+type CreateLocateRequest struct {
+	PortfolioId string `json:"portfolio_id"`
+	Symbol      string `json:"symbol"`
+	Amount      string `json:"amount"`
+
+	// The date the borrow is needed, in YYYY-MM-DD format. Defaults to today.
+	LocateDate string `json:"locate_date,omitempty"`
+}
+
+type CreateLocateResponse struct {
+	LocateId string               `json:"locate_id"`
+	Request  *CreateLocateRequest `json:"request"`
+}
+
+// CreateLocate requests to borrow an asset to sell short. Track the approval
+// with ListLocates.
+func (s *financingServiceImpl) CreateLocate(
+	ctx context.Context,
+	request *CreateLocateRequest,
+) (*CreateLocateResponse, error) {
+
+	path := fmt.Sprintf("/portfolios/%s/locates", request.PortfolioId)
+
+	response := &CreateLocateResponse{Request: request}
+
+	if err := core.HttpPost(
+		ctx,
+		s.client,
+		path,
+		core.EmptyQueryParams,
+		client.DefaultSuccessHttpStatusCodes,
+		request,
+		response,
+		s.client.HeadersFunc(),
+	); err != nil {
+		return nil, err
+	}
+
+	return response, nil
+}
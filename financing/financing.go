@@ -0,0 +1,42 @@
+/**
+ * Copyright 2024-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package financing
+
+import (
+	"context"
+
+	"github.com/coinbase-samples/prime-sdk-go/client"
+)
+
+type FinancingService interface {
+	GetMarginSummary(ctx context.Context, request *GetMarginSummaryRequest) (*GetMarginSummaryResponse, error)
+	ListMarginCallHistory(ctx context.Context, request *ListMarginCallHistoryRequest) (*ListMarginCallHistoryResponse, error)
+	GetBuyingPower(ctx context.Context, request *GetBuyingPowerRequest) (*GetBuyingPowerResponse, error)
+	GetWithdrawalPower(ctx context.Context, request *GetWithdrawalPowerRequest) (*GetWithdrawalPowerResponse, error)
+	CreateLocate(ctx context.Context, request *CreateLocateRequest) (*CreateLocateResponse, error)
+	ListLocates(ctx context.Context, request *ListLocatesRequest) (*ListLocatesResponse, error)
+	ListTradeFinanceTiers(ctx context.Context, request *ListTradeFinanceTiersRequest) (*ListTradeFinanceTiersResponse, error)
+	ListInterestAccruals(ctx context.Context, request *ListInterestAccrualsRequest) (*ListInterestAccrualsResponse, error)
+}
+
+func NewFinancingService(c client.RestClient) FinancingService {
+	return &financingServiceImpl{client: c}
+}
+
+type financingServiceImpl struct {
+	client client.RestClient
+}
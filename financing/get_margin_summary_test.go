@@ -0,0 +1,107 @@
+/**
+ * Copyright 2024-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package financing
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/coinbase-samples/prime-sdk-go/client"
+	"github.com/coinbase-samples/prime-sdk-go/credentials"
+)
+
+func TestGetMarginSummary(t *testing.T) {
+
+	cases := []struct {
+		description   string
+		body          string
+		grossLeverage string
+		deficit       bool
+	}{
+		{
+			description:   "TestGetMarginSummary0",
+			body:          `{"margin_summary":{"entity_id":"e1","margin_equity":"1000000","margin_requirement":"250000","excess_deficit":"750000","gross_market_value":"2500000","net_market_value":"500000","margin_calls":[]}}`,
+			grossLeverage: "2.5",
+		},
+		{
+			description:   "TestGetMarginSummary1",
+			body:          `{"margin_summary":{"entity_id":"e1","margin_equity":"100000","margin_requirement":"150000","excess_deficit":"-50000","gross_market_value":"1000000","net_market_value":"-1000000","margin_calls":[{"margin_call_id":"m1","currency":"USD","initial_amount":"50000","remaining_amount":"50000","status":"OPEN"}]}}`,
+			grossLeverage: "10",
+			deficit:       true,
+		},
+		{
+			description:   "TestGetMarginSummary2",
+			body:          `{"margin_summary":{"entity_id":"e1","margin_equity":"0","margin_requirement":"0","excess_deficit":"0","gross_market_value":"0","net_market_value":"0"}}`,
+			grossLeverage: "0",
+		},
+		{
+			description:   "TestGetMarginSummary3",
+			body:          `{"margin_summary":{"entity_id":"e1","margin_equity":"1000","margin_requirement":"","excess_deficit":"1000","long_market_value":"","short_market_value":"","gross_market_value":"2000","net_market_value":"","total_loan_value":""}}`,
+			grossLeverage: "2",
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.description, func(t *testing.T) {
+
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != "/entities/e1/margin" {
+					t.Errorf("test: %s - unexpected path: %s", tt.description, r.URL.Path)
+				}
+				w.Write([]byte(tt.body))
+			}))
+			defer server.Close()
+
+			svc := NewFinancingService(client.NewRestClient(&credentials.Credentials{}, http.Client{}).SetBaseUrl(server.URL))
+
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+
+			response, err := svc.GetMarginSummary(ctx, &GetMarginSummaryRequest{EntityId: "e1"})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			l, err := response.MarginSummary.GrossLeverage()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if l.String() != tt.grossLeverage {
+				t.Errorf("test: %s - expected: %s - received: %s", tt.description, tt.grossLeverage, l)
+			}
+
+			deficit, err := response.MarginSummary.InDeficit()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if deficit != tt.deficit {
+				t.Errorf("test: %s - expected deficit: %v", tt.description, tt.deficit)
+			}
+
+			if len(response.MarginSummary.MarginCalls) > 0 {
+				if _, err := response.MarginSummary.MarginCalls[0].RemainingAmountNum(); err != nil {
+					t.Errorf("test: %s - unexpected err: %v", tt.description, err)
+				}
+			}
+		})
+	}
+}
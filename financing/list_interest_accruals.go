@@ -0,0 +1,80 @@
+/**
+ * Copyright 2024-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package financing
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/coinbase-samples/core-go"
+	"github.com/coinbase-samples/prime-sdk-go/client"
+	"github.com/coinbase-samples/prime-sdk-go/model"
+	"github.com/coinbase-samples/prime-sdk-go/utils"
+)
+
+type ListInterestAccrualsRequest struct {
+	EntityId string `json:"entity_id"`
+
+	// Optionally limits the accruals to a portfolio of the entity
+	PortfolioId string    `json:"portfolio_id"`
+	Start       time.Time `json:"start_date"`
+	End         time.Time `json:"end_date"`
+}
+
+type ListInterestAccrualsResponse struct {
+	Accruals []*model.InterestAccrual     `json:"accruals"`
+	Request  *ListInterestAccrualsRequest `json:"request"`
+}
+
+func (s *financingServiceImpl) ListInterestAccruals(
+	ctx context.Context,
+	request *ListInterestAccrualsRequest,
+) (*ListInterestAccrualsResponse, error) {
+
+	path := fmt.Sprintf("/entities/%s/accruals", request.EntityId)
+	if len(request.PortfolioId) > 0 {
+		path = fmt.Sprintf("/entities/%s/portfolios/%s/accruals", request.EntityId, request.PortfolioId)
+	}
+
+	var queryParams string
+
+	if !request.Start.IsZero() {
+		queryParams = core.AppendHttpQueryParam(queryParams, "start_date", utils.TimeToStr(request.Start))
+	}
+
+	if !request.End.IsZero() {
+		queryParams = core.AppendHttpQueryParam(queryParams, "end_date", utils.TimeToStr(request.End))
+	}
+
+	response := &ListInterestAccrualsResponse{Request: request}
+
+	if err := core.HttpGet(
+		ctx,
+		s.client,
+		path,
+		queryParams,
+		client.DefaultSuccessHttpStatusCodes,
+		request,
+		response,
+		s.client.HeadersFunc(),
+	); err != nil {
+		return nil, err
+	}
+
+	return response, nil
+}
@@ -0,0 +1,74 @@
+/**
+ * Copyright 2024-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package financing
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/coinbase-samples/core-go"
+	"github.com/coinbase-samples/prime-sdk-go/client"
+	"github.com/coinbase-samples/prime-sdk-go/model"
+)
+
+type ListLocatesRequest struct {
+	PortfolioId string   `json:"portfolio_id"`
+	LocateIds   []string `json:"locate_ids"`
+
+	// Filters by locate date, in YYYY-MM-DD format
+	LocateDate string `json:"locate_date"`
+}
+
+type ListLocatesResponse struct {
+	Locates []*model.Locate     `json:"locates"`
+	Request *ListLocatesRequest `json:"request"`
+}
+
+func (s *financingServiceImpl) ListLocates(
+	ctx context.Context,
+	request *ListLocatesRequest,
+) (*ListLocatesResponse, error) {
+
+	path := fmt.Sprintf("/portfolios/%s/locates", request.PortfolioId)
+
+	var queryParams string
+
+	for _, id := range request.LocateIds {
+		queryParams = core.AppendHttpQueryParam(queryParams, "locate_ids", id)
+	}
+
+	if len(request.LocateDate) > 0 {
+		queryParams = core.AppendHttpQueryParam(queryParams, "locate_date", request.LocateDate)
+	}
+
+	response := &ListLocatesResponse{Request: request}
+
+	if err := core.HttpGet(
+		ctx,
+		s.client,
+		path,
+		queryParams,
+		client.DefaultSuccessHttpStatusCodes,
+		request,
+		response,
+		s.client.HeadersFunc(),
+	); err != nil {
+		return nil, err
+	}
+
+	return response, nil
+}
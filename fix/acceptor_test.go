@@ -0,0 +1,131 @@
+/**
+ * Copyright 2024-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package fix
+
+import (
+	"bufio"
+	"context"
+	"net"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+)
+
+// testAcceptor is a FIX acceptor stub. Each connection runs the script; the
+// sender sequence of the acceptor continues across connections, like a
+// gateway does within a trading day.
+type testAcceptor struct {
+	listener net.Listener
+	seq      int64
+	mu       sync.Mutex
+}
+
+func newTestAcceptor(t *testing.T, script func(c *testFixConn)) *testAcceptor {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	a := &testAcceptor{listener: listener}
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				script(&testFixConn{t: t, acceptor: a, conn: conn, r: bufio.NewReader(conn)})
+			}()
+		}
+	}()
+
+	t.Cleanup(func() { listener.Close() })
+
+	return a
+}
+
+func (a *testAcceptor) dial(ctx context.Context, address string) (net.Conn, error) {
+	d := &net.Dialer{}
+	return d.DialContext(ctx, "tcp", a.listener.Addr().String())
+}
+
+func (a *testAcceptor) nextSeq() int64 {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.seq++
+	return a.seq
+}
+
+// writeSeq messages bypass the acceptor sequence; setSeq realigns it.
+func (a *testAcceptor) setSeq(seq int64) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.seq = seq
+}
+
+type testFixConn struct {
+	t        *testing.T
+	acceptor *testAcceptor
+	conn     net.Conn
+	r        *bufio.Reader
+}
+
+func (c *testFixConn) read() *Message {
+	c.conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+
+	b, err := ReadMessage(c.r)
+	if err != nil {
+		return nil
+	}
+
+	msg, err := ParseMessage(b)
+	if err != nil {
+		c.t.Errorf("unable to parse message: %v", err)
+		return nil
+	}
+
+	return msg
+}
+
+// readType reads until a message of the type, skipping heartbeats and other
+// messages.
+func (c *testFixConn) readType(msgType string) *Message {
+	for {
+		msg := c.read()
+		if msg == nil || msg.MsgType() == msgType {
+			return msg
+		}
+	}
+}
+
+func (c *testFixConn) write(msg *Message) {
+	c.writeSeq(msg, c.acceptor.nextSeq())
+}
+
+func (c *testFixConn) writeSeq(msg *Message, seq int64) {
+	msg.Set(TagSenderCompId, DefaultTargetCompId).
+		Set(TagTargetCompId, "svc").
+		Set(TagMsgSeqNum, strconv.FormatInt(seq, 10)).
+		Set(TagSendingTime, sendingTime(time.Now()))
+
+	c.conn.Write(msg.Bytes())
+}
@@ -0,0 +1,376 @@
+/**
+ * Copyright 2024-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package fix
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/coinbase-samples/core-go"
+	"github.com/coinbase-samples/prime-sdk-go/orderbook"
+)
+
+// MarketDataFeed maintains an L2 order book per product from FIX market
+// data. It implements orderbook.Feed, like the WebSocket order book feed.
+type MarketDataFeed interface {
+	orderbook.Feed
+
+	// Replay applies the market data received in a message log, e.g., to
+	// rebuild the books a session saw while debugging. Books are created for
+	// every product in the log.
+	Replay(r io.Reader) error
+}
+
+// NewMarketDataFeed registers the market data handlers on the session. When
+// the session detects a sequence gap, or a product's RptSeq skips, the books
+// are invalidated and fresh snapshots are requested.
+func NewMarketDataFeed(s Session) MarketDataFeed {
+	f := &marketDataFeedImpl{
+		session: s,
+		books:   make(map[string]*orderbook.Book),
+		reqIds:  make(map[string]string),
+		rptSeqs: make(map[string]int64),
+	}
+
+	s.Handle(MsgTypeMarketDataSnapshot, f.handleSnapshot)
+	s.Handle(MsgTypeMarketDataRefresh, f.handleRefresh)
+	s.Handle(MsgTypeMarketDataReject, f.handleReject)
+	s.OnSequenceGap(f.onSequenceGap)
+
+	return f
+}
+
+type marketDataFeedImpl struct {
+	session Session
+	books   map[string]*orderbook.Book
+
+	// The MDReqID of the active request and the last RptSeq, per product
+	reqIds  map[string]string
+	rptSeqs map[string]int64
+
+	replaying bool
+	err       error
+	mu        sync.Mutex
+}
+
+func (f *marketDataFeedImpl) Subscribe(ctx context.Context, productIds ...string) error {
+
+	for _, id := range productIds {
+
+		f.mu.Lock()
+		if _, ok := f.books[id]; !ok {
+			f.books[id] = orderbook.NewBook(id)
+		}
+		f.mu.Unlock()
+
+		if err := f.request(id); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (f *marketDataFeedImpl) Book(productId string) (*orderbook.Book, bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	b, ok := f.books[productId]
+	return b, ok
+}
+
+func (f *marketDataFeedImpl) Err() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.err
+}
+
+func (f *marketDataFeedImpl) setErr(err error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.err = err
+}
+
+func (f *marketDataFeedImpl) Replay(r io.Reader) error {
+
+	f.mu.Lock()
+	f.replaying = true
+	f.mu.Unlock()
+
+	defer func() {
+		f.mu.Lock()
+		f.replaying = false
+		f.mu.Unlock()
+	}()
+
+	return ReplayMessageLog(r, func(entry *LogEntry) error {
+		if entry.Direction != DirectionIn {
+			return nil
+		}
+
+		switch entry.Message.MsgType() {
+		case MsgTypeMarketDataSnapshot:
+			f.handleSnapshot(entry.Message)
+		case MsgTypeMarketDataRefresh:
+			f.handleRefresh(entry.Message)
+		}
+
+		return nil
+	})
+}
+
+// request sends a MarketDataRequest for the full depth of a product with
+// incremental updates. An active request for the product is cancelled first.
+func (f *marketDataFeedImpl) request(productId string) error {
+
+	reqId := fmt.Sprintf("md-%s-%s", productId, strconv.FormatInt(time.Now().UnixNano(), 36))
+
+	f.mu.Lock()
+	prevReqId := f.reqIds[productId]
+	f.reqIds[productId] = reqId
+	delete(f.rptSeqs, productId)
+	f.mu.Unlock()
+
+	if len(prevReqId) > 0 {
+		if err := f.session.Send(marketDataRequest(prevReqId, productId, SubscriptionRequestUnsubscribe)); err != nil {
+			return fmt.Errorf("unable to cancel market data request: %s - err: %w", prevReqId, err)
+		}
+	}
+
+	if err := f.session.Send(marketDataRequest(reqId, productId, SubscriptionRequestSnapshotAndUpdates)); err != nil {
+		return fmt.Errorf("unable to send market data request: %s - err: %w", productId, err)
+	}
+
+	return nil
+}
+
+func marketDataRequest(reqId, productId, subscriptionRequest string) *Message {
+	return NewMessage(MsgTypeMarketDataRequest).
+		Set(TagMDReqId, reqId).
+		Set(TagSubscriptionReq, subscriptionRequest).
+		Set(TagMarketDepth, "0").
+		Set(TagMDUpdateType, MDUpdateTypeIncremental).
+		Set(TagNoMDEntryTypes, "2").
+		Add(TagMDEntryType, MDEntryTypeBid).
+		Add(TagMDEntryType, MDEntryTypeOffer).
+		Set(TagNoRelatedSym, "1").
+		Add(TagSymbol, productId)
+}
+
+// book returns the book of a product, creating it while replaying a log.
+func (f *marketDataFeedImpl) book(productId string) (*orderbook.Book, bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	b, ok := f.books[productId]
+	if !ok && f.replaying {
+		b = orderbook.NewBook(productId)
+		f.books[productId] = b
+		ok = true
+	}
+	return b, ok
+}
+
+func (f *marketDataFeedImpl) handleSnapshot(msg *Message) {
+
+	productId := msg.GetString(TagSymbol)
+
+	book, ok := f.book(productId)
+	if !ok {
+		return
+	}
+
+	entries, err := msg.Group(TagNoMDEntries, TagMDEntryType)
+	if err != nil {
+		f.setErr(fmt.Errorf("invalid market data snapshot - product: %s - err: %w", productId, err))
+		book.Invalidate()
+		return
+	}
+
+	updates := make([]orderbook.Update, 0, len(entries))
+	for _, e := range entries {
+		u, err := convertMDEntry(e)
+		if err != nil {
+			f.setErr(fmt.Errorf("invalid market data snapshot - product: %s - err: %w", productId, err))
+			book.Invalidate()
+			return
+		}
+		updates = append(updates, u)
+	}
+
+	if rptSeq, err := msg.GetInt(TagRptSeq); err == nil {
+		f.mu.Lock()
+		f.rptSeqs[productId] = rptSeq
+		f.mu.Unlock()
+	}
+
+	book.ApplySnapshot(updates)
+}
+
+// handleRefresh applies an incremental refresh. Entries may span products;
+// the updates of each product are applied together, in order.
+func (f *marketDataFeedImpl) handleRefresh(msg *Message) {
+
+	entries, err := msg.Group(TagNoMDEntries, TagMDUpdateAction)
+	if err != nil {
+		f.setErr(fmt.Errorf("invalid market data refresh - err: %w", err))
+		return
+	}
+
+	var productIds []string
+	updates := make(map[string][]orderbook.Update)
+
+	for _, e := range entries {
+
+		productId := e.GetString(TagSymbol)
+		if len(productId) == 0 {
+			productId = msg.GetString(TagSymbol)
+		}
+
+		book, ok := f.book(productId)
+		if !ok {
+			continue
+		}
+
+		if !f.checkRptSeq(productId, e) {
+			f.resync(book, fmt.Sprintf("rpt seq gap - product: %s", productId))
+			continue
+		}
+
+		u, err := convertMDEntry(e)
+		if err != nil {
+			f.setErr(fmt.Errorf("invalid market data refresh - product: %s - err: %w", productId, err))
+			book.Invalidate()
+			continue
+		}
+
+		if _, ok := updates[productId]; !ok {
+			productIds = append(productIds, productId)
+		}
+		updates[productId] = append(updates[productId], u)
+	}
+
+	for _, id := range productIds {
+		if book, ok := f.book(id); ok {
+			book.Apply(updates[id])
+		}
+	}
+}
+
+// checkRptSeq returns false if the entry's RptSeq is not the next one for
+// the product. Entries without RptSeq are not checked.
+func (f *marketDataFeedImpl) checkRptSeq(productId string, e *Message) bool {
+
+	rptSeq, err := e.GetInt(TagRptSeq)
+	if err != nil {
+		return true
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	last, ok := f.rptSeqs[productId]
+	if ok && rptSeq != last+1 {
+		return false
+	}
+
+	f.rptSeqs[productId] = rptSeq
+	return true
+}
+
+func (f *marketDataFeedImpl) handleReject(msg *Message) {
+
+	reqId := msg.GetString(TagMDReqId)
+
+	f.mu.Lock()
+	for productId, id := range f.reqIds {
+		if id == reqId {
+			delete(f.reqIds, productId)
+		}
+	}
+	f.mu.Unlock()
+
+	f.setErr(fmt.Errorf("market data request rejected: %s - reason: %s - text: %s", reqId, msg.GetString(TagMDReqRejReason), msg.GetString(TagText)))
+}
+
+func (f *marketDataFeedImpl) onSequenceGap(expected, received int64) {
+
+	f.mu.Lock()
+	books := make([]*orderbook.Book, 0, len(f.books))
+	for _, b := range f.books {
+		books = append(books, b)
+	}
+	f.mu.Unlock()
+
+	for _, b := range books {
+		f.resync(b, fmt.Sprintf("sequence gap - expected: %d - received: %d", expected, received))
+	}
+}
+
+// resync invalidates a book and requests a fresh snapshot. While replaying,
+// the book is only invalidated.
+func (f *marketDataFeedImpl) resync(book *orderbook.Book, reason string) {
+
+	book.Invalidate()
+
+	f.mu.Lock()
+	replaying := f.replaying
+	f.mu.Unlock()
+
+	if replaying {
+		return
+	}
+
+	if err := f.request(book.ProductId()); err != nil {
+		f.setErr(fmt.Errorf("unable to resync book after %s - err: %w", reason, err))
+	}
+}
+
+func convertMDEntry(e *Message) (orderbook.Update, error) {
+
+	var side orderbook.Side
+	switch entryType := e.GetString(TagMDEntryType); entryType {
+	case MDEntryTypeBid:
+		side = orderbook.Bid
+	case MDEntryTypeOffer:
+		side = orderbook.Ask
+	default:
+		return orderbook.Update{}, fmt.Errorf("unknown entry type: %s", entryType)
+	}
+
+	price, err := core.StrToNum(e.GetString(TagMDEntryPx))
+	if err != nil {
+		return orderbook.Update{}, fmt.Errorf("invalid price: %s - err: %w", e.GetString(TagMDEntryPx), err)
+	}
+
+	update := orderbook.Update{Side: side, Price: price}
+
+	// Deletes may omit the size; a zero quantity removes the level
+	if e.GetString(TagMDUpdateAction) == MDUpdateActionDelete {
+		return update, nil
+	}
+
+	update.Quantity, err = core.StrToNum(e.GetString(TagMDEntrySize))
+	if err != nil {
+		return orderbook.Update{}, fmt.Errorf("invalid size: %s - err: %w", e.GetString(TagMDEntrySize), err)
+	}
+
+	return update, nil
+}
@@ -0,0 +1,209 @@
+/**
+ * Copyright 2024-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package fix
+
+import (
+	"bytes"
+	"context"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/coinbase-samples/prime-sdk-go/orderbook"
+	"github.com/shopspring/decimal"
+)
+
+func snapshot(productId string, rptSeq int64, entries ...[3]string) *Message {
+	msg := NewMessage(MsgTypeMarketDataSnapshot).
+		Set(TagSymbol, productId).
+		Set(TagRptSeq, decimal.NewFromInt(rptSeq).String()).
+		Set(TagNoMDEntries, decimal.NewFromInt(int64(len(entries))).String())
+
+	for _, e := range entries {
+		msg.Add(TagMDEntryType, e[0]).Add(TagMDEntryPx, e[1]).Add(TagMDEntrySize, e[2])
+	}
+	return msg
+}
+
+func refresh(productId string, rptSeq int64, action, entryType, px, size string) *Message {
+	return NewMessage(MsgTypeMarketDataRefresh).
+		Set(TagNoMDEntries, "1").
+		Add(TagMDUpdateAction, action).
+		Add(TagMDEntryType, entryType).
+		Add(TagSymbol, productId).
+		Add(TagMDEntryPx, px).
+		Add(TagMDEntrySize, size).
+		Add(TagRptSeq, decimal.NewFromInt(rptSeq).String())
+}
+
+// expectRequest reads market data requests until a subscribe for the product.
+func expectRequest(c *testFixConn, productId string) bool {
+	for {
+		req := c.readType(MsgTypeMarketDataRequest)
+		if req == nil {
+			c.t.Errorf("expected a market data request: %s", productId)
+			return false
+		}
+		if req.GetString(TagSymbol) != productId {
+			c.t.Errorf("unexpected market data request: %s", req)
+			return false
+		}
+		if req.GetString(TagSubscriptionReq) == SubscriptionRequestSnapshotAndUpdates {
+			return true
+		}
+	}
+}
+
+func TestMarketDataFeed(t *testing.T) {
+
+	step := make(chan struct{})
+
+	a := newTestAcceptor(t, func(c *testFixConn) {
+
+		if acceptLogon(c) == nil || !expectRequest(c, "BTC-USD") {
+			return
+		}
+
+		c.write(snapshot("BTC-USD", 1,
+			[3]string{MDEntryTypeBid, "100", "1"},
+			[3]string{MDEntryTypeBid, "99", "2"},
+			[3]string{MDEntryTypeOffer, "101", "1"},
+		))
+		c.write(refresh("BTC-USD", 2, MDUpdateActionDelete, MDEntryTypeBid, "100", "0"))
+		c.write(refresh("BTC-USD", 3, MDUpdateActionNew, MDEntryTypeOffer, "100.5", "3"))
+
+		<-step
+
+		// RptSeq 4 is skipped; the feed must request a new snapshot
+		c.write(refresh("BTC-USD", 5, MDUpdateActionChange, MDEntryTypeOffer, "100.5", "1"))
+
+		if !expectRequest(c, "BTC-USD") {
+			return
+		}
+
+		c.write(snapshot("BTC-USD", 10,
+			[3]string{MDEntryTypeBid, "98", "1"},
+			[3]string{MDEntryTypeOffer, "102", "1"},
+		))
+
+		<-step
+
+		// The session sequence skips one; the feed must request a new
+		// snapshot too
+		skipped := c.acceptor.nextSeq()
+		c.write(refresh("BTC-USD", 11, MDUpdateActionNew, MDEntryTypeBid, "98.5", "1"))
+
+		if !expectRequest(c, "BTC-USD") {
+			return
+		}
+
+		resend := c.readType(MsgTypeResendRequest)
+		if resend == nil || resend.GetString(TagBeginSeqNo) != strconv.FormatInt(skipped, 10) {
+			t.Errorf("unexpected resend request: %v", resend)
+			return
+		}
+
+		// The skipped message and the refresh are not resent
+		c.writeSeq(NewMessage(MsgTypeSequenceReset).
+			Set(TagPossDupFlag, "Y").
+			Set(TagGapFillFlag, "Y").
+			Set(TagNewSeqNo, strconv.FormatInt(skipped+2, 10)), skipped)
+
+		c.write(snapshot("BTC-USD", 20,
+			[3]string{MDEntryTypeBid, "97", "1"},
+			[3]string{MDEntryTypeOffer, "103", "1"},
+		))
+
+		acceptLogout(c)
+	})
+
+	var log bytes.Buffer
+
+	session := newTestSession(a).SetMessageLog(&log)
+	feed := NewMarketDataFeed(session)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	if err := session.Logon(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := feed.Subscribe(ctx, "BTC-USD"); err != nil {
+		t.Fatal(err)
+	}
+
+	book, ok := feed.Book("BTC-USD")
+	if !ok {
+		t.Fatal("expected a book")
+	}
+
+	waitForBook(ctx, t, book, "99", "100.5")
+
+	step <- struct{}{}
+
+	waitForBook(ctx, t, book, "98", "102")
+
+	step <- struct{}{}
+
+	waitForBook(ctx, t, book, "97", "103")
+
+	if err := session.Logout(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := session.Err(); err != nil {
+		t.Errorf("expected a clean logout - received: %v", err)
+	}
+
+	if err := feed.Err(); err != nil {
+		t.Error(err)
+	}
+
+	// Replaying the log rebuilds the book up to the last snapshot
+	replayed := NewMarketDataFeed(NewSession(testCredentials))
+
+	if err := replayed.Replay(bytes.NewReader(log.Bytes())); err != nil {
+		t.Fatal(err)
+	}
+
+	book, ok = replayed.Book("BTC-USD")
+	if !ok {
+		t.Fatal("expected a replayed book")
+	}
+
+	waitForBook(ctx, t, book, "97", "103")
+}
+
+func waitForBook(ctx context.Context, t *testing.T, book *orderbook.Book, bid, ask string) {
+	t.Helper()
+
+	for {
+		b, errBid := book.BestBid()
+		a, errAsk := book.BestAsk()
+
+		if errBid == nil && errAsk == nil && b.Price.String() == bid && a.Price.String() == ask {
+			return
+		}
+
+		select {
+		case <-ctx.Done():
+			t.Fatalf("timed out waiting for book - expected: %s / %s", bid, ask)
+		case <-time.After(5 * time.Millisecond):
+		}
+	}
+}
@@ -0,0 +1,329 @@
+/**
+ * Copyright 2024-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package fix
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	BeginString = "FIX.4.2"
+
+	soh = '\x01'
+
+	sendingTimeFormat = "20060102-15:04:05.000"
+)
+
+// Header fields are encoded right after MsgType, in this order, regardless
+// of the order they were set in.
+var headerTags = []int{TagSenderCompId, TagTargetCompId, TagMsgSeqNum, TagSendingTime, TagPossDupFlag, TagOrigSendingTime}
+
+type Field struct {
+	Tag   int
+	Value string
+}
+
+// Message is a FIX message as an ordered list of fields. BeginString,
+// BodyLength and CheckSum are computed when the message is encoded and are
+// not part of the fields.
+type Message struct {
+	Fields []Field
+}
+
+func NewMessage(msgType string) *Message {
+	return (&Message{}).Set(TagMsgType, msgType)
+}
+
+func (m *Message) MsgType() string {
+	return m.GetString(TagMsgType)
+}
+
+func (m *Message) SeqNum() int64 {
+	n, _ := m.GetInt(TagMsgSeqNum)
+	return n
+}
+
+func (m *Message) Get(tag int) (string, bool) {
+	for _, f := range m.Fields {
+		if f.Tag == tag {
+			return f.Value, true
+		}
+	}
+	return "", false
+}
+
+func (m *Message) GetString(tag int) string {
+	v, _ := m.Get(tag)
+	return v
+}
+
+func (m *Message) GetInt(tag int) (int64, error) {
+	v, ok := m.Get(tag)
+	if !ok {
+		return 0, fmt.Errorf("missing tag: %d", tag)
+	}
+	n, err := strconv.ParseInt(v, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid int tag: %d - value: %s - err: %w", tag, v, err)
+	}
+	return n, nil
+}
+
+func (m *Message) Has(tag int) bool {
+	_, ok := m.Get(tag)
+	return ok
+}
+
+// Set replaces the value of the tag or appends the field if it is not set.
+func (m *Message) Set(tag int, value string) *Message {
+	for idx := range m.Fields {
+		if m.Fields[idx].Tag == tag {
+			m.Fields[idx].Value = value
+			return m
+		}
+	}
+	m.Fields = append(m.Fields, Field{Tag: tag, Value: value})
+	return m
+}
+
+// Add appends the field, even if the tag is set, e.g., for the fields of a
+// repeating group.
+func (m *Message) Add(tag int, value string) *Message {
+	m.Fields = append(m.Fields, Field{Tag: tag, Value: value})
+	return m
+}
+
+// Group returns the entries of the repeating group counted by countTag. Each
+// entry starts with firstTag and holds the fields up to the next entry or the
+// end of the message.
+func (m *Message) Group(countTag, firstTag int) ([]*Message, error) {
+
+	count, err := m.GetInt(countTag)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		entries []*Message
+		entry   *Message
+		inGroup bool
+	)
+
+	for _, f := range m.Fields {
+		switch {
+		case f.Tag == countTag:
+			inGroup = true
+		case !inGroup:
+		case f.Tag == firstTag:
+			entry = &Message{}
+			entries = append(entries, entry)
+			entry.Fields = append(entry.Fields, f)
+		case entry != nil:
+			entry.Fields = append(entry.Fields, f)
+		}
+	}
+
+	if int64(len(entries)) != count {
+		return nil, fmt.Errorf("invalid group: %d - expected: %d entries - received: %d", countTag, count, len(entries))
+	}
+
+	return entries, nil
+}
+
+// SetIfNotEmpty sets the tag only if the value is not empty, so optional
+// fields are omitted from the message.
+func (m *Message) SetIfNotEmpty(tag int, value string) *Message {
+	if len(value) > 0 {
+		m.Set(tag, value)
+	}
+	return m
+}
+
+// Bytes encodes the message, computing BodyLength and CheckSum.
+func (m *Message) Bytes() []byte {
+
+	var body bytes.Buffer
+
+	writeField(&body, TagMsgType, m.MsgType())
+
+	for _, tag := range headerTags {
+		if v, ok := m.Get(tag); ok {
+			writeField(&body, tag, v)
+		}
+	}
+
+	for _, f := range m.Fields {
+		if f.Tag == TagMsgType || isHeaderTag(f.Tag) {
+			continue
+		}
+		writeField(&body, f.Tag, f.Value)
+	}
+
+	var b bytes.Buffer
+	writeField(&b, TagBeginString, BeginString)
+	writeField(&b, TagBodyLength, strconv.Itoa(body.Len()))
+	b.Write(body.Bytes())
+	writeField(&b, TagCheckSum, checksum(b.Bytes()))
+
+	return b.Bytes()
+}
+
+// String returns the encoded message with SOH replaced by |, for logging.
+func (m *Message) String() string {
+	return strings.ReplaceAll(string(m.Bytes()), string(soh), "|")
+}
+
+// ParseMessage decodes a raw message and validates its BodyLength and
+// CheckSum.
+func ParseMessage(b []byte) (*Message, error) {
+
+	if len(b) == 0 || b[len(b)-1] != soh {
+		return nil, errors.New("message not terminated by SOH")
+	}
+
+	m := &Message{}
+
+	var (
+		bodyStart   int
+		bodyLength  int
+		checksumPos int
+		received    string
+	)
+
+	pos := 0
+	for _, raw := range bytes.Split(b[:len(b)-1], []byte{soh}) {
+
+		start := pos
+		pos += len(raw) + 1
+
+		eq := bytes.IndexByte(raw, '=')
+		if eq <= 0 {
+			return nil, fmt.Errorf("invalid field: %q", raw)
+		}
+
+		tag, err := strconv.Atoi(string(raw[:eq]))
+		if err != nil {
+			return nil, fmt.Errorf("invalid tag: %q - err: %w", raw[:eq], err)
+		}
+
+		value := string(raw[eq+1:])
+
+		switch tag {
+		case TagBeginString:
+			if value != BeginString {
+				return nil, fmt.Errorf("unsupported begin string: %s", value)
+			}
+		case TagBodyLength:
+			if bodyLength, err = strconv.Atoi(value); err != nil {
+				return nil, fmt.Errorf("invalid body length: %s - err: %w", value, err)
+			}
+			bodyStart = pos
+		case TagCheckSum:
+			checksumPos = start
+			received = value
+		default:
+			m.Fields = append(m.Fields, Field{Tag: tag, Value: value})
+		}
+	}
+
+	if len(received) == 0 {
+		return nil, errors.New("missing checksum")
+	}
+
+	if checksumPos-bodyStart != bodyLength {
+		return nil, fmt.Errorf("invalid body length - expected: %d - received: %d", bodyLength, checksumPos-bodyStart)
+	}
+
+	if expected := checksum(b[:checksumPos]); expected != received {
+		return nil, fmt.Errorf("invalid checksum - expected: %s - received: %s", expected, received)
+	}
+
+	if len(m.MsgType()) == 0 {
+		return nil, errors.New("missing msg type")
+	}
+
+	return m, nil
+}
+
+// ReadMessage reads one raw message, using BodyLength to find its end.
+func ReadMessage(r *bufio.Reader) ([]byte, error) {
+
+	begin, err := r.ReadBytes(soh)
+	if err != nil {
+		return nil, err
+	}
+
+	length, err := r.ReadBytes(soh)
+	if err != nil {
+		return nil, err
+	}
+
+	if !bytes.HasPrefix(length, []byte("9=")) {
+		return nil, fmt.Errorf("expected body length - received: %q", length)
+	}
+
+	n, err := strconv.Atoi(string(length[2 : len(length)-1]))
+	if err != nil || n < 0 {
+		return nil, fmt.Errorf("invalid body length: %q", length)
+	}
+
+	// The body and the 7 byte checksum field, e.g., 10=123<SOH>
+	rest := make([]byte, n+7)
+	if _, err := io.ReadFull(r, rest); err != nil {
+		return nil, err
+	}
+
+	b := make([]byte, 0, len(begin)+len(length)+len(rest))
+	b = append(b, begin...)
+	b = append(b, length...)
+	return append(b, rest...), nil
+}
+
+func writeField(b *bytes.Buffer, tag int, value string) {
+	b.WriteString(strconv.Itoa(tag))
+	b.WriteByte('=')
+	b.WriteString(value)
+	b.WriteByte(soh)
+}
+
+func checksum(b []byte) string {
+	var sum int
+	for _, c := range b {
+		sum += int(c)
+	}
+	return fmt.Sprintf("%03d", sum%256)
+}
+
+func isHeaderTag(tag int) bool {
+	for _, t := range headerTags {
+		if t == tag {
+			return true
+		}
+	}
+	return false
+}
+
+func sendingTime(t time.Time) string {
+	return t.UTC().Format(sendingTimeFormat)
+}
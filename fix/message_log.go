@@ -0,0 +1,129 @@
+/**
+ * Copyright 2024-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package fix
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"time"
+)
+
+const (
+	DirectionIn  = "IN"
+	DirectionOut = "OUT"
+)
+
+// LogEntry is a message recorded in a message log, with the time it was
+// sent or received.
+type LogEntry struct {
+	Time      time.Time
+	Direction string
+	Message   *Message
+}
+
+// maskedValue replaces the values of maskedTags in a message log.
+const maskedValue = "***"
+
+// maskedTags are the Logon credentials: the passphrase, the HMAC signature
+// and the access key.
+var maskedTags = []int{TagPassword, TagRawData, TagAccessKey}
+
+// writeLogEntry writes one line: the time, the direction and the raw
+// message, separated by spaces. FIX messages do not contain newlines, so the
+// log can be read back line by line. Credentials are masked, see maskLogEntry.
+func writeLogEntry(w io.Writer, t time.Time, direction string, raw []byte) error {
+	_, err := fmt.Fprintf(w, "%s %s %s\n", t.UTC().Format(time.RFC3339Nano), direction, maskLogEntry(raw))
+	return err
+}
+
+// maskLogEntry replaces the values of maskedTags and encodes the message
+// again, so BodyLength and CheckSum stay valid for ReplayMessageLog. Messages
+// without credentials are returned as they are.
+func maskLogEntry(raw []byte) []byte {
+
+	var found bool
+	for _, tag := range maskedTags {
+		if bytes.Contains(raw, []byte(fmt.Sprintf("%c%d=", soh, tag))) {
+			found = true
+			break
+		}
+	}
+
+	if !found {
+		return raw
+	}
+
+	msg, err := ParseMessage(raw)
+	if err != nil {
+		// Invalid messages are not replayable anyway, so mask them in place
+		fields := bytes.Split(raw, []byte{soh})
+		for idx, f := range fields {
+			for _, tag := range maskedTags {
+				if bytes.HasPrefix(f, []byte(fmt.Sprintf("%d=", tag))) {
+					fields[idx] = []byte(fmt.Sprintf("%d=%s", tag, maskedValue))
+				}
+			}
+		}
+		return bytes.Join(fields, []byte{soh})
+	}
+
+	for idx, f := range msg.Fields {
+		for _, tag := range maskedTags {
+			if f.Tag == tag {
+				msg.Fields[idx].Value = maskedValue
+			}
+		}
+	}
+
+	return msg.Bytes()
+}
+
+// ReplayMessageLog reads a message log written by a session and calls h for
+// each entry, in order, until h returns an error.
+func ReplayMessageLog(r io.Reader, h func(entry *LogEntry) error) error {
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 10*1024*1024)
+
+	line := 0
+	for scanner.Scan() {
+		line++
+
+		parts := bytes.SplitN(scanner.Bytes(), []byte{' '}, 3)
+		if len(parts) != 3 {
+			return fmt.Errorf("invalid message log entry - line: %d", line)
+		}
+
+		t, err := time.Parse(time.RFC3339Nano, string(parts[0]))
+		if err != nil {
+			return fmt.Errorf("invalid message log time - line: %d - err: %w", line, err)
+		}
+
+		msg, err := ParseMessage(parts[2])
+		if err != nil {
+			return fmt.Errorf("invalid message log message - line: %d - err: %w", line, err)
+		}
+
+		if err := h(&LogEntry{Time: t, Direction: string(parts[1]), Message: msg}); err != nil {
+			return err
+		}
+	}
+
+	return scanner.Err()
+}
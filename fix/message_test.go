@@ -0,0 +1,178 @@
+/**
+ * Copyright 2024-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package fix
+
+import (
+	"bufio"
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/coinbase-samples/prime-sdk-go/model"
+)
+
+func TestParseMessage(t *testing.T) {
+
+	valid := NewMessage(MsgTypeHeartbeat).
+		Set(TagSenderCompId, "svc").
+		Set(TagTargetCompId, "COIN").
+		Set(TagMsgSeqNum, "1").
+		Set(TagTestReqId, "t1").
+		Bytes()
+
+	cases := []struct {
+		description string
+		raw         []byte
+		expectedErr string
+	}{
+		{
+			description: "TestParseMessage0",
+			raw:         valid,
+		},
+		{
+			description: "TestParseMessage1",
+			raw:         bytes.Replace(valid, []byte("112=t1"), []byte("112=t2"), 1),
+			expectedErr: "invalid checksum",
+		},
+		{
+			description: "TestParseMessage2",
+			raw:         bytes.Replace(valid, []byte("112=t1"), []byte("112=t12"), 1),
+			expectedErr: "invalid body length",
+		},
+		{
+			description: "TestParseMessage3",
+			raw:         valid[:len(valid)-1],
+			expectedErr: "not terminated",
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.description, func(t *testing.T) {
+			msg, err := ParseMessage(tt.raw)
+
+			if len(tt.expectedErr) > 0 {
+				if err == nil || !strings.Contains(err.Error(), tt.expectedErr) {
+					t.Errorf("test: %s - expected: %s - received: %v", tt.description, tt.expectedErr, err)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("test: %s - unexpected err: %v", tt.description, err)
+			}
+
+			if !bytes.Equal(msg.Bytes(), tt.raw) {
+				t.Errorf("test: %s - expected: %s - received: %s", tt.description, tt.raw, msg.Bytes())
+			}
+		})
+	}
+}
+
+func TestReadMessage(t *testing.T) {
+
+	first := NewMessage(MsgTypeHeartbeat).Set(TagMsgSeqNum, "1").Bytes()
+	second := NewMessage(MsgTypeTestRequest).Set(TagMsgSeqNum, "2").Set(TagTestReqId, "t1").Bytes()
+
+	r := bufio.NewReader(bytes.NewReader(append(append([]byte{}, first...), second...)))
+
+	for _, expected := range [][]byte{first, second} {
+		b, err := ReadMessage(r)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(b, expected) {
+			t.Errorf("expected: %s - received: %s", expected, b)
+		}
+	}
+}
+
+func TestParseExecutionReport(t *testing.T) {
+
+	cases := []struct {
+		description    string
+		ordStatus      string
+		targetStrategy string
+		ordType        string
+		expectedStatus string
+		expectedType   string
+	}{
+		{
+			description:    "TestParseExecutionReport0",
+			ordStatus:      OrdStatusNew,
+			targetStrategy: TargetStrategyLimit,
+			ordType:        OrdTypeLimit,
+			expectedStatus: model.OrderStatusOpen,
+			expectedType:   model.OrderTypeLimit,
+		},
+		{
+			description:    "TestParseExecutionReport1",
+			ordStatus:      OrdStatusPartiallyFilled,
+			targetStrategy: TargetStrategyTwap,
+			ordType:        OrdTypeLimit,
+			expectedStatus: model.OrderStatusOpen,
+			expectedType:   model.OrderTypeTwap,
+		},
+		{
+			description:    "TestParseExecutionReport2",
+			ordStatus:      OrdStatusFilled,
+			ordType:        OrdTypeMarket,
+			expectedStatus: model.OrderStatusFilled,
+			expectedType:   model.OrderTypeMarket,
+		},
+		{
+			description:    "TestParseExecutionReport3",
+			ordStatus:      OrdStatusRejected,
+			ordType:        OrdTypeLimit,
+			expectedStatus: model.OrderStatusFailed,
+			expectedType:   model.OrderTypeLimit,
+		},
+		{
+			description:    "TestParseExecutionReport4",
+			ordStatus:      OrdStatusExpired,
+			ordType:        OrdTypeLimit,
+			expectedStatus: model.OrderStatusExpired,
+			expectedType:   model.OrderTypeLimit,
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.description, func(t *testing.T) {
+			msg := NewMessage(MsgTypeExecutionReport).
+				Set(TagOrderId, "o1").
+				Set(TagClOrdId, "c1").
+				Set(TagSide, SideSell).
+				Set(TagOrdStatus, tt.ordStatus).
+				Set(TagOrdType, tt.ordType).
+				SetIfNotEmpty(TagTargetStrategy, tt.targetStrategy).
+				Set(TagCumQty, "0.5")
+
+			report := ParseExecutionReport(msg)
+
+			if report.Order.Status != tt.expectedStatus {
+				t.Errorf("test: %s - expected: %s - received: %s", tt.description, tt.expectedStatus, report.Order.Status)
+			}
+
+			if report.Order.Type != tt.expectedType {
+				t.Errorf("test: %s - expected: %s - received: %s", tt.description, tt.expectedType, report.Order.Type)
+			}
+
+			if report.Order.Side != model.OrderSideSell || report.Order.FilledQuantity != "0.5" {
+				t.Errorf("test: %s - unexpected order: %+v", tt.description, report.Order)
+			}
+		})
+	}
+}
@@ -0,0 +1,283 @@
+/**
+ * Copyright 2024-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package fix
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/coinbase-samples/prime-sdk-go/model"
+)
+
+type CancelRequest struct {
+	PortfolioId string
+
+	// The client order id of the cancel request and of the order to cancel
+	ClientOrderId     string
+	OrigClientOrderId string
+
+	OrderId      string
+	ProductId    string
+	Side         string
+	BaseQuantity string
+}
+
+// ExecutionReport is an ExecutionReport mapped to the REST order model. The
+// raw message is kept for the fields that do not map.
+type ExecutionReport struct {
+	Order *model.Order
+
+	ExecId    string
+	ExecType  string
+	OrdStatus string
+
+	// The quantity and price of the fill reported by this message, if any
+	LastQuantity string
+	LastPrice    string
+
+	LeavesQuantity string
+	Text           string
+
+	Message *Message
+}
+
+type ExecutionReportHandler func(report *ExecutionReport)
+
+func (s *sessionImpl) OnExecutionReport(h ExecutionReportHandler) {
+	s.Handle(MsgTypeExecutionReport, func(msg *Message) {
+		h(ParseExecutionReport(msg))
+	})
+}
+
+func (s *sessionImpl) NewOrderSingle(order *model.Order) error {
+	msg, err := NewOrderSingleMessage(order)
+	if err != nil {
+		return err
+	}
+	return s.Send(msg)
+}
+
+func (s *sessionImpl) OrderCancelRequest(request *CancelRequest) error {
+	msg, err := OrderCancelRequestMessage(request)
+	if err != nil {
+		return err
+	}
+	return s.Send(msg)
+}
+
+// NewOrderSingleMessage maps a LIMIT, MARKET or TWAP order to a
+// NewOrderSingle.
+func NewOrderSingleMessage(order *model.Order) (*Message, error) {
+
+	if len(order.ClientOrderId) == 0 {
+		return nil, errors.New("client order id required")
+	}
+
+	side, err := fixSide(order.Side)
+	if err != nil {
+		return nil, err
+	}
+
+	msg := NewMessage(MsgTypeNewOrderSingle).
+		Set(TagAccount, order.PortfolioId).
+		Set(TagClOrdId, order.ClientOrderId).
+		Set(TagSymbol, order.ProductId).
+		Set(TagSide, side).
+		Set(TagTransactTime, sendingTime(time.Now()))
+
+	switch order.Type {
+	case model.OrderTypeMarket:
+		msg.Set(TagOrdType, OrdTypeMarket).Set(TagTargetStrategy, TargetStrategyMarket)
+	case model.OrderTypeLimit:
+		msg.Set(TagOrdType, OrdTypeLimit).Set(TagTargetStrategy, TargetStrategyLimit)
+	case model.OrderTypeTwap:
+		msg.Set(TagOrdType, OrdTypeLimit).Set(TagTargetStrategy, TargetStrategyTwap)
+	default:
+		return nil, fmt.Errorf("unsupported fix order type: %s", order.Type)
+	}
+
+	if len(order.BaseQuantity) == 0 && len(order.QuoteValue) == 0 {
+		return nil, errors.New("base quantity or quote value required")
+	}
+
+	msg.SetIfNotEmpty(TagOrderQty, order.BaseQuantity).
+		SetIfNotEmpty(TagCashOrderQty, order.QuoteValue).
+		SetIfNotEmpty(TagPrice, order.LimitPrice)
+
+	if len(order.TimeInForce) > 0 {
+		tif, err := fixTimeInForce(order.TimeInForce)
+		if err != nil {
+			return nil, err
+		}
+		msg.Set(TagTimeInForce, tif)
+	}
+
+	if err := setFixTime(msg, TagEffectiveTime, order.StartTime); err != nil {
+		return nil, err
+	}
+
+	if err := setFixTime(msg, TagExpireTime, order.ExpiryTime); err != nil {
+		return nil, err
+	}
+
+	return msg, nil
+}
+
+func OrderCancelRequestMessage(request *CancelRequest) (*Message, error) {
+
+	if len(request.ClientOrderId) == 0 || len(request.OrigClientOrderId) == 0 {
+		return nil, errors.New("client order id and orig client order id required")
+	}
+
+	side, err := fixSide(request.Side)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewMessage(MsgTypeOrderCancelRequest).
+		Set(TagAccount, request.PortfolioId).
+		Set(TagClOrdId, request.ClientOrderId).
+		Set(TagOrigClOrdId, request.OrigClientOrderId).
+		SetIfNotEmpty(TagOrderId, request.OrderId).
+		Set(TagSymbol, request.ProductId).
+		Set(TagSide, side).
+		SetIfNotEmpty(TagOrderQty, request.BaseQuantity).
+		Set(TagTransactTime, sendingTime(time.Now())), nil
+}
+
+// ParseExecutionReport maps an ExecutionReport to the order model. Unknown
+// enum values are kept as received.
+func ParseExecutionReport(msg *Message) *ExecutionReport {
+
+	order := &model.Order{
+		Id:                 msg.GetString(TagOrderId),
+		PortfolioId:        msg.GetString(TagAccount),
+		ClientOrderId:      msg.GetString(TagClOrdId),
+		ProductId:          msg.GetString(TagSymbol),
+		Side:               modelSide(msg.GetString(TagSide)),
+		Type:               modelOrderType(msg),
+		Status:             modelOrderStatus(msg.GetString(TagOrdStatus)),
+		BaseQuantity:       msg.GetString(TagOrderQty),
+		QuoteValue:         msg.GetString(TagCashOrderQty),
+		LimitPrice:         msg.GetString(TagPrice),
+		FilledQuantity:     msg.GetString(TagCumQty),
+		AverageFilledPrice: msg.GetString(TagAvgPx),
+		Commission:         msg.GetString(TagCommission),
+	}
+
+	return &ExecutionReport{
+		Order:          order,
+		ExecId:         msg.GetString(TagExecId),
+		ExecType:       msg.GetString(TagExecType),
+		OrdStatus:      msg.GetString(TagOrdStatus),
+		LastQuantity:   msg.GetString(TagLastShares),
+		LastPrice:      msg.GetString(TagLastPx),
+		LeavesQuantity: msg.GetString(TagLeavesQty),
+		Text:           msg.GetString(TagText),
+		Message:        msg,
+	}
+}
+
+func fixSide(side string) (string, error) {
+	switch side {
+	case model.OrderSideBuy:
+		return SideBuy, nil
+	case model.OrderSideSell:
+		return SideSell, nil
+	}
+	return "", fmt.Errorf("unsupported fix side: %s", side)
+}
+
+func modelSide(side string) string {
+	switch side {
+	case SideBuy:
+		return model.OrderSideBuy
+	case SideSell:
+		return model.OrderSideSell
+	}
+	return side
+}
+
+func fixTimeInForce(tif string) (string, error) {
+	switch tif {
+	case model.TimeInForceGoodUntilCancelled:
+		return TimeInForceGoodTillCancel, nil
+	case model.TimeInForceImmediateOrCancel:
+		return TimeInForceImmediateOrCancel, nil
+	case model.TimeInForceGoodUntilTime:
+		return TimeInForceGoodTillDate, nil
+	}
+	return "", fmt.Errorf("unsupported fix time in force: %s", tif)
+}
+
+func modelOrderType(msg *Message) string {
+
+	switch msg.GetString(TagTargetStrategy) {
+	case TargetStrategyMarket:
+		return model.OrderTypeMarket
+	case TargetStrategyLimit:
+		return model.OrderTypeLimit
+	case TargetStrategyTwap:
+		return model.OrderTypeTwap
+	}
+
+	switch ordType := msg.GetString(TagOrdType); ordType {
+	case OrdTypeMarket:
+		return model.OrderTypeMarket
+	case OrdTypeLimit:
+		return model.OrderTypeLimit
+	default:
+		return ordType
+	}
+}
+
+func modelOrderStatus(ordStatus string) string {
+	switch ordStatus {
+	case OrdStatusPendingNew:
+		return model.OrderStatusPending
+	case OrdStatusNew, OrdStatusPartiallyFilled, OrdStatusPendingCancel, OrdStatusPendingReplace:
+		return model.OrderStatusOpen
+	case OrdStatusFilled:
+		return model.OrderStatusFilled
+	case OrdStatusCanceled:
+		return model.OrderStatusCancelled
+	case OrdStatusExpired:
+		return model.OrderStatusExpired
+	case OrdStatusRejected:
+		return model.OrderStatusFailed
+	}
+	return ordStatus
+}
+
+// setFixTime converts an RFC 3339 time of the order model to a FIX UTC
+// timestamp.
+func setFixTime(msg *Message, tag int, value string) error {
+
+	if len(value) == 0 {
+		return nil
+	}
+
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return fmt.Errorf("invalid time: %s - err: %w", value, err)
+	}
+
+	msg.Set(tag, sendingTime(t))
+
+	return nil
+}
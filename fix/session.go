@@ -0,0 +1,711 @@
+/**
+ * Copyright 2024-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package fix
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/coinbase-samples/prime-sdk-go/client"
+	"github.com/coinbase-samples/prime-sdk-go/credentials"
+	"github.com/coinbase-samples/prime-sdk-go/model"
+)
+
+const (
+	DefaultAddress      = "fix.prime.coinbase.com:4198"
+	DefaultTargetCompId = "COIN"
+
+	defaultHeartbeatInterval = 30 * time.Second
+	minHeartbeatInterval     = time.Second
+)
+
+var (
+	// ErrHeartbeatTimeout is returned by Err when the counterparty does not
+	// answer a TestRequest within the heartbeat interval.
+	ErrHeartbeatTimeout = errors.New("fix heartbeat timeout")
+
+	// ErrSeqNumTooLow is returned by Err when a message arrives with a
+	// sequence number lower than expected and is not a possible duplicate.
+	// The session cannot recover; the sequence numbers must be reconciled.
+	ErrSeqNumTooLow = errors.New("fix msg seq num too low")
+
+	ErrNotLoggedOn = errors.New("fix session not logged on")
+)
+
+// LogoutError is returned by Logon and Err when the counterparty ends the
+// session, e.g., when it rejects the logon.
+type LogoutError struct {
+	Text string
+}
+
+func (e *LogoutError) Error() string {
+	return fmt.Sprintf("fix logout: %s", e.Text)
+}
+
+// Dialer opens the transport to the FIX gateway. The default dialer uses TLS.
+type Dialer func(ctx context.Context, address string) (net.Conn, error)
+
+// SequenceGapHandler is called when a message arrives with a sequence number
+// higher than expected, before the ResendRequest is sent.
+type SequenceGapHandler func(expected, received int64)
+
+// MessageHandler is called on the reading goroutine for each application
+// message of a type. Handlers must not block.
+type MessageHandler func(msg *Message)
+
+type Session interface {
+	SetAddress(a string) Session
+	Address() string
+	SetTargetCompId(id string) Session
+
+	// SetHeartbeatInterval sets the HeartBtInt sent in the Logon, in whole
+	// seconds. Intervals under a second, including zero and negative ones,
+	// are raised to a second.
+	SetHeartbeatInterval(d time.Duration) Session
+	SetStore(s SeqStore) Session
+	SetDialer(d Dialer) Session
+
+	// SetMessageLog records every message sent and received, e.g., to a
+	// file, so a session can be replayed with ReplayMessageLog. The Logon
+	// passphrase, signature and access key are masked.
+	SetMessageLog(w io.Writer) Session
+	Credentials() *credentials.Credentials
+	Store() SeqStore
+
+	Handle(msgType string, h MessageHandler)
+	OnExecutionReport(h ExecutionReportHandler)
+	OnSequenceGap(h SequenceGapHandler)
+
+	// Logon connects and logs on, resuming the sequence numbers in the store.
+	// It returns once the counterparty acknowledges the logon.
+	Logon(ctx context.Context) error
+
+	// Logout sends a Logout and waits for the counterparty to acknowledge it
+	// or the context to be done, then closes the connection.
+	Logout(ctx context.Context) error
+
+	// Send stamps the header of an application message and sends it.
+	Send(msg *Message) error
+	NewOrderSingle(order *model.Order) error
+	OrderCancelRequest(request *CancelRequest) error
+
+	// Done is closed when the session ends; Err then returns the reason, or
+	// nil after a clean logout.
+	Done() <-chan struct{}
+	Err() error
+}
+
+func NewSession(credentials *credentials.Credentials) Session {
+	return &sessionImpl{
+		address:           DefaultAddress,
+		targetCompId:      DefaultTargetCompId,
+		heartbeatInterval: defaultHeartbeatInterval,
+		credentials:       credentials,
+		store:             NewMemoryStore(),
+		dialer:            dialTls,
+		handlers:          make(map[string]MessageHandler),
+	}
+}
+
+func dialTls(ctx context.Context, address string) (net.Conn, error) {
+	d := &tls.Dialer{}
+	return d.DialContext(ctx, "tcp", address)
+}
+
+type sessionImpl struct {
+	address           string
+	targetCompId      string
+	heartbeatInterval time.Duration
+	credentials       *credentials.Credentials
+	store             SeqStore
+	dialer            Dialer
+	messageLog        io.Writer
+	logMu             sync.Mutex
+
+	conn    net.Conn
+	writeMu sync.Mutex
+
+	handlers    map[string]MessageHandler
+	gapHandlers []SequenceGapHandler
+
+	loggedOn      chan struct{}
+	loggedOut     chan struct{}
+	done          chan struct{}
+	err           error
+	loggingOut    bool
+	lastSent      time.Time
+	lastReceived  time.Time
+	testRequestAt time.Time
+	resendUpTo    int64
+
+	mu sync.Mutex
+}
+
+func (s *sessionImpl) SetAddress(a string) Session {
+	s.address = a
+	return s
+}
+
+func (s *sessionImpl) Address() string {
+	return s.address
+}
+
+func (s *sessionImpl) SetTargetCompId(id string) Session {
+	s.targetCompId = id
+	return s
+}
+
+func (s *sessionImpl) SetHeartbeatInterval(d time.Duration) Session {
+	s.heartbeatInterval = d
+	return s
+}
+
+// interval returns the heartbeat interval sent in the Logon and used by the
+// heartbeat loop, at least a second.
+func (s *sessionImpl) interval() time.Duration {
+	if s.heartbeatInterval < minHeartbeatInterval {
+		return minHeartbeatInterval
+	}
+	return s.heartbeatInterval.Truncate(time.Second)
+}
+
+func (s *sessionImpl) SetStore(store SeqStore) Session {
+	s.store = store
+	return s
+}
+
+func (s *sessionImpl) SetDialer(d Dialer) Session {
+	s.dialer = d
+	return s
+}
+
+func (s *sessionImpl) SetMessageLog(w io.Writer) Session {
+	s.messageLog = w
+	return s
+}
+
+func (s *sessionImpl) Credentials() *credentials.Credentials {
+	return s.credentials
+}
+
+func (s *sessionImpl) Store() SeqStore {
+	return s.store
+}
+
+func (s *sessionImpl) Handle(msgType string, h MessageHandler) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.handlers[msgType] = h
+}
+
+func (s *sessionImpl) OnSequenceGap(h SequenceGapHandler) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.gapHandlers = append(s.gapHandlers, h)
+}
+
+func (s *sessionImpl) Done() <-chan struct{} {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.done
+}
+
+func (s *sessionImpl) Err() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.err
+}
+
+func (s *sessionImpl) Logon(ctx context.Context) error {
+
+	conn, err := s.dialer(ctx, s.address)
+	if err != nil {
+		return fmt.Errorf("unable to dial fix gateway: %s - err: %w", s.address, err)
+	}
+
+	s.mu.Lock()
+	s.conn = conn
+	s.loggedOn = make(chan struct{})
+	s.loggedOut = make(chan struct{})
+	s.done = make(chan struct{})
+	s.err = nil
+	s.loggingOut = false
+	s.lastReceived = time.Now()
+	s.testRequestAt = time.Time{}
+	s.resendUpTo = 0
+	loggedOn, done := s.loggedOn, s.done
+	s.mu.Unlock()
+
+	go s.read(conn, done)
+
+	if err := s.sendLogon(); err != nil {
+		s.fail(err)
+		return fmt.Errorf("unable to send logon: %w", err)
+	}
+
+	select {
+	case <-loggedOn:
+	case <-done:
+		return fmt.Errorf("logon failed: %w", s.Err())
+	case <-ctx.Done():
+		s.fail(ctx.Err())
+		return fmt.Errorf("logon not acknowledged: %w", ctx.Err())
+	}
+
+	go s.heartbeat(done)
+
+	return nil
+}
+
+// sendLogon signs the logon the way Prime expects: the HMAC-SHA256 of the
+// SendingTime, MsgType, MsgSeqNum, access key, TargetCompID and passphrase,
+// base64 encoded in RawData.
+func (s *sessionImpl) sendLogon() error {
+
+	heartBtInt := int64(s.interval() / time.Second)
+
+	msg := NewMessage(MsgTypeLogon).
+		Set(TagEncryptMethod, "0").
+		Set(TagHeartBtInt, strconv.FormatInt(heartBtInt, 10)).
+		Set(TagPassword, s.credentials.Passphrase).
+		Set(TagAccessKey, s.credentials.AccessKey)
+
+	return s.send(msg, func(m *Message) {
+		m.Set(TagRawData, client.SignMessage(
+			s.credentials.SigningKey,
+			m.GetString(TagSendingTime)+m.MsgType()+m.GetString(TagMsgSeqNum)+s.credentials.AccessKey+s.targetCompId+s.credentials.Passphrase,
+		))
+	})
+}
+
+func (s *sessionImpl) Logout(ctx context.Context) error {
+
+	s.mu.Lock()
+	s.loggingOut = true
+	loggedOut, done := s.loggedOut, s.done
+	s.mu.Unlock()
+
+	if done == nil {
+		return ErrNotLoggedOn
+	}
+
+	if err := s.send(NewMessage(MsgTypeLogout), nil); err != nil {
+		s.fail(nil)
+		return fmt.Errorf("unable to send logout: %w", err)
+	}
+
+	select {
+	case <-loggedOut:
+	case <-done:
+	case <-ctx.Done():
+	}
+
+	s.fail(nil)
+
+	return nil
+}
+
+func (s *sessionImpl) Send(msg *Message) error {
+
+	s.mu.Lock()
+	loggedOn := s.loggedOn
+	s.mu.Unlock()
+
+	if loggedOn == nil {
+		return ErrNotLoggedOn
+	}
+
+	select {
+	case <-loggedOn:
+	default:
+		return ErrNotLoggedOn
+	}
+
+	return s.send(msg, nil)
+}
+
+// send stamps the header, calls sign, if set, and writes the message. The
+// next sender sequence number is persisted only after a successful write.
+func (s *sessionImpl) send(msg *Message, sign func(m *Message)) error {
+
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+
+	s.mu.Lock()
+	conn := s.conn
+	s.mu.Unlock()
+
+	if conn == nil {
+		return ErrNotLoggedOn
+	}
+
+	seq, err := s.store.NextSenderSeqNum()
+	if err != nil {
+		return err
+	}
+
+	msg.Set(TagSenderCompId, s.credentials.SvcAccountId).
+		Set(TagTargetCompId, s.targetCompId).
+		Set(TagMsgSeqNum, strconv.FormatInt(seq, 10)).
+		Set(TagSendingTime, sendingTime(time.Now()))
+
+	if sign != nil {
+		sign(msg)
+	}
+
+	b := msg.Bytes()
+
+	if _, err := conn.Write(b); err != nil {
+		return err
+	}
+
+	s.log(DirectionOut, b)
+
+	s.mu.Lock()
+	s.lastSent = time.Now()
+	s.mu.Unlock()
+
+	return s.store.SetNextSenderSeqNum(seq + 1)
+}
+
+func (s *sessionImpl) read(conn net.Conn, done chan struct{}) {
+
+	r := bufio.NewReader(conn)
+
+	for {
+		b, err := ReadMessage(r)
+		if err != nil {
+			s.mu.Lock()
+			loggingOut := s.loggingOut
+			s.mu.Unlock()
+
+			if loggingOut {
+				s.fail(nil)
+			} else {
+				s.fail(fmt.Errorf("unable to read message: %w", err))
+			}
+			return
+		}
+
+		s.log(DirectionIn, b)
+
+		msg, err := ParseMessage(b)
+		if err != nil {
+			// Garbled messages are ignored; the sequence gap they leave is
+			// recovered with a resend request
+			continue
+		}
+
+		if err := s.receive(msg); err != nil {
+			s.fail(err)
+			return
+		}
+
+		select {
+		case <-done:
+			return
+		default:
+		}
+	}
+}
+
+// receive validates the sequence number of an incoming message and handles
+// it. A gap triggers a ResendRequest and the out of sequence messages are
+// dropped until the counterparty resends them in order.
+func (s *sessionImpl) receive(msg *Message) error {
+
+	s.mu.Lock()
+	s.lastReceived = time.Now()
+	s.testRequestAt = time.Time{}
+	s.mu.Unlock()
+
+	expected, err := s.store.NextTargetSeqNum()
+	if err != nil {
+		return err
+	}
+
+	seq, err := msg.GetInt(TagMsgSeqNum)
+	if err != nil {
+		return err
+	}
+
+	msgType := msg.MsgType()
+
+	if msgType == MsgTypeSequenceReset {
+		return s.sequenceReset(msg, expected)
+	}
+
+	if seq < expected {
+		if msg.GetString(TagPossDupFlag) == "Y" {
+			return nil
+		}
+		s.send(NewMessage(MsgTypeLogout).Set(TagText, fmt.Sprintf("MsgSeqNum too low, expecting %d but received %d", expected, seq)), nil)
+		return fmt.Errorf("expected: %d - received: %d - err: %w", expected, seq, ErrSeqNumTooLow)
+	}
+
+	if seq > expected {
+		// Session level messages are processed even when out of sequence
+		switch msgType {
+		case MsgTypeLogon, MsgTypeLogout:
+			if err := s.dispatch(msg); err != nil {
+				return err
+			}
+		}
+		return s.requestResend(expected, seq)
+	}
+
+	if err := s.store.SetNextTargetSeqNum(seq + 1); err != nil {
+		return err
+	}
+
+	return s.dispatch(msg)
+}
+
+func (s *sessionImpl) requestResend(expected, received int64) error {
+
+	s.mu.Lock()
+	pending := s.resendUpTo >= expected
+	if !pending {
+		s.resendUpTo = received
+	}
+	gapHandlers := s.gapHandlers
+	s.mu.Unlock()
+
+	if pending {
+		return nil
+	}
+
+	for _, h := range gapHandlers {
+		h(expected, received)
+	}
+
+	return s.send(
+		NewMessage(MsgTypeResendRequest).
+			Set(TagBeginSeqNo, strconv.FormatInt(expected, 10)).
+			Set(TagEndSeqNo, "0"),
+		nil,
+	)
+}
+
+func (s *sessionImpl) sequenceReset(msg *Message, expected int64) error {
+
+	newSeq, err := msg.GetInt(TagNewSeqNo)
+	if err != nil {
+		return err
+	}
+
+	// Gap fills only move the sequence forward
+	if newSeq <= expected {
+		return nil
+	}
+
+	return s.store.SetNextTargetSeqNum(newSeq)
+}
+
+func (s *sessionImpl) dispatch(msg *Message) error {
+
+	switch msg.MsgType() {
+
+	case MsgTypeLogon:
+		s.mu.Lock()
+		loggedOn := s.loggedOn
+		s.mu.Unlock()
+
+		select {
+		case <-loggedOn:
+		default:
+			close(loggedOn)
+		}
+		return nil
+
+	case MsgTypeLogout:
+		s.mu.Lock()
+		loggingOut := s.loggingOut
+		loggedOut := s.loggedOut
+		s.mu.Unlock()
+
+		if loggingOut {
+			select {
+			case <-loggedOut:
+			default:
+				close(loggedOut)
+			}
+			return nil
+		}
+
+		s.send(NewMessage(MsgTypeLogout), nil)
+		return &LogoutError{Text: msg.GetString(TagText)}
+
+	case MsgTypeHeartbeat:
+		return nil
+
+	case MsgTypeTestRequest:
+		return s.send(NewMessage(MsgTypeHeartbeat).Set(TagTestReqId, msg.GetString(TagTestReqId)), nil)
+
+	case MsgTypeResendRequest:
+		return s.resend(msg)
+	}
+
+	s.mu.Lock()
+	handler := s.handlers[msg.MsgType()]
+	s.mu.Unlock()
+
+	if handler != nil {
+		handler(msg)
+	}
+
+	return nil
+}
+
+// resend answers a ResendRequest with a SequenceReset-GapFill over the whole
+// range. Application messages are never resent: replaying a stale order or
+// cancel is more dangerous than skipping it, and callers can recover state
+// from the ExecutionReports or the REST API.
+func (s *sessionImpl) resend(msg *Message) error {
+
+	begin, err := msg.GetInt(TagBeginSeqNo)
+	if err != nil {
+		return err
+	}
+
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+
+	s.mu.Lock()
+	conn := s.conn
+	s.mu.Unlock()
+
+	next, err := s.store.NextSenderSeqNum()
+	if err != nil {
+		return err
+	}
+
+	if begin >= next {
+		return nil
+	}
+
+	now := sendingTime(time.Now())
+
+	reset := NewMessage(MsgTypeSequenceReset).
+		Set(TagSenderCompId, s.credentials.SvcAccountId).
+		Set(TagTargetCompId, s.targetCompId).
+		Set(TagMsgSeqNum, strconv.FormatInt(begin, 10)).
+		Set(TagSendingTime, now).
+		Set(TagPossDupFlag, "Y").
+		Set(TagOrigSendingTime, now).
+		Set(TagGapFillFlag, "Y").
+		Set(TagNewSeqNo, strconv.FormatInt(next, 10))
+
+	b := reset.Bytes()
+
+	if _, err := conn.Write(b); err != nil {
+		return err
+	}
+
+	s.log(DirectionOut, b)
+
+	return nil
+}
+
+func (s *sessionImpl) log(direction string, b []byte) {
+	if s.messageLog == nil {
+		return
+	}
+
+	s.logMu.Lock()
+	defer s.logMu.Unlock()
+
+	writeLogEntry(s.messageLog, time.Now(), direction, b)
+}
+
+// heartbeat sends a Heartbeat when nothing was sent for the interval and a
+// TestRequest when nothing was received for the interval. The session fails
+// if the TestRequest is not answered within another interval.
+func (s *sessionImpl) heartbeat(done chan struct{}) {
+
+	interval := s.interval()
+
+	ticker := time.NewTicker(interval / 4)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-done:
+			return
+		case <-ticker.C:
+		}
+
+		now := time.Now()
+
+		s.mu.Lock()
+		sinceSent := now.Sub(s.lastSent)
+		sinceReceived := now.Sub(s.lastReceived)
+		testRequestAt := s.testRequestAt
+		s.mu.Unlock()
+
+		if !testRequestAt.IsZero() {
+			if now.Sub(testRequestAt) >= interval {
+				s.fail(ErrHeartbeatTimeout)
+				return
+			}
+		} else if sinceReceived >= interval {
+			s.mu.Lock()
+			s.testRequestAt = now
+			s.mu.Unlock()
+
+			s.send(NewMessage(MsgTypeTestRequest).Set(TagTestReqId, strconv.FormatInt(now.UnixNano(), 10)), nil)
+			continue
+		}
+
+		if sinceSent >= interval {
+			s.send(NewMessage(MsgTypeHeartbeat), nil)
+		}
+	}
+}
+
+// fail ends the session once, recording the reason and closing the
+// connection.
+func (s *sessionImpl) fail(err error) {
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.done == nil {
+		return
+	}
+
+	select {
+	case <-s.done:
+		return
+	default:
+	}
+
+	s.err = err
+	close(s.done)
+
+	if s.conn != nil {
+		s.conn.Close()
+	}
+}
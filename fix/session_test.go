@@ -0,0 +1,483 @@
+/**
+ * Copyright 2024-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package fix
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/coinbase-samples/prime-sdk-go/client"
+	"github.com/coinbase-samples/prime-sdk-go/credentials"
+	"github.com/coinbase-samples/prime-sdk-go/model"
+)
+
+var testCredentials = &credentials.Credentials{
+	AccessKey:    "key",
+	Passphrase:   "pass",
+	SigningKey:   "secret",
+	SvcAccountId: "svc",
+}
+
+func newTestSession(a *testAcceptor) Session {
+	return NewSession(testCredentials).SetDialer(a.dial)
+}
+
+// acceptLogon reads the logon, checks the signature and acknowledges it.
+func acceptLogon(c *testFixConn) *Message {
+
+	logon := c.readType(MsgTypeLogon)
+	if logon == nil {
+		c.t.Error("expected a logon")
+		return nil
+	}
+
+	expected := client.SignMessage(
+		"secret",
+		logon.GetString(TagSendingTime)+MsgTypeLogon+logon.GetString(TagMsgSeqNum)+"key"+DefaultTargetCompId+"pass",
+	)
+
+	if logon.GetString(TagRawData) != expected || logon.GetString(TagPassword) != "pass" || logon.GetString(TagAccessKey) != "key" {
+		c.t.Errorf("unexpected logon: %s", logon)
+	}
+
+	if logon.GetString(TagSenderCompId) != "svc" || logon.GetString(TagTargetCompId) != DefaultTargetCompId {
+		c.t.Errorf("unexpected logon header: %s", logon)
+	}
+
+	c.write(NewMessage(MsgTypeLogon).Set(TagEncryptMethod, "0").Set(TagHeartBtInt, "30"))
+
+	return logon
+}
+
+func acceptLogout(c *testFixConn) {
+	if c.readType(MsgTypeLogout) != nil {
+		c.write(NewMessage(MsgTypeLogout))
+	}
+}
+
+func TestOrderEntry(t *testing.T) {
+
+	a := newTestAcceptor(t, func(c *testFixConn) {
+
+		if acceptLogon(c) == nil {
+			return
+		}
+
+		nos := c.readType(MsgTypeNewOrderSingle)
+		if nos == nil {
+			t.Error("expected a new order single")
+			return
+		}
+
+		for tag, expected := range map[int]string{
+			TagAccount:        "p1",
+			TagClOrdId:        "c1",
+			TagSymbol:         "BTC-USD",
+			TagSide:           SideBuy,
+			TagOrdType:        OrdTypeLimit,
+			TagTargetStrategy: TargetStrategyLimit,
+			TagOrderQty:       "1.5",
+			TagPrice:          "100",
+			TagTimeInForce:    TimeInForceGoodTillCancel,
+		} {
+			if v := nos.GetString(tag); v != expected {
+				t.Errorf("tag: %d - expected: %s - received: %s", tag, expected, v)
+			}
+		}
+
+		c.write(NewMessage(MsgTypeExecutionReport).
+			Set(TagAccount, "p1").
+			Set(TagOrderId, "o1").
+			Set(TagClOrdId, "c1").
+			Set(TagExecId, "e1").
+			Set(TagExecType, OrdStatusNew).
+			Set(TagOrdStatus, OrdStatusNew).
+			Set(TagSymbol, "BTC-USD").
+			Set(TagSide, SideBuy).
+			Set(TagOrdType, OrdTypeLimit).
+			Set(TagTargetStrategy, TargetStrategyLimit).
+			Set(TagOrderQty, "1.5").
+			Set(TagPrice, "100").
+			Set(TagCumQty, "0").
+			Set(TagLeavesQty, "1.5"))
+
+		cancel := c.readType(MsgTypeOrderCancelRequest)
+		if cancel == nil || cancel.GetString(TagOrigClOrdId) != "c1" || cancel.GetString(TagOrderId) != "o1" {
+			t.Errorf("unexpected cancel request: %v", cancel)
+			return
+		}
+
+		c.write(NewMessage(MsgTypeExecutionReport).
+			Set(TagOrderId, "o1").
+			Set(TagClOrdId, "c2").
+			Set(TagOrigClOrdId, "c1").
+			Set(TagExecId, "e2").
+			Set(TagExecType, OrdStatusCanceled).
+			Set(TagOrdStatus, OrdStatusCanceled).
+			Set(TagSide, SideBuy).
+			Set(TagCumQty, "0").
+			Set(TagLeavesQty, "0"))
+
+		acceptLogout(c)
+	})
+
+	session := newTestSession(a)
+
+	reports := make(chan *ExecutionReport, 10)
+	session.OnExecutionReport(func(r *ExecutionReport) { reports <- r })
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	if err := session.Logon(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := session.NewOrderSingle(&model.Order{
+		PortfolioId:   "p1",
+		ClientOrderId: "c1",
+		ProductId:     "BTC-USD",
+		Side:          model.OrderSideBuy,
+		Type:          model.OrderTypeLimit,
+		BaseQuantity:  "1.5",
+		LimitPrice:    "100",
+		TimeInForce:   model.TimeInForceGoodUntilCancelled,
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	report := <-reports
+	if report.Order.Id != "o1" || report.Order.Status != model.OrderStatusOpen || report.Order.Type != model.OrderTypeLimit || report.LeavesQuantity != "1.5" {
+		t.Fatalf("unexpected report: %+v", report.Order)
+	}
+
+	if err := session.OrderCancelRequest(&CancelRequest{
+		PortfolioId:       "p1",
+		ClientOrderId:     "c2",
+		OrigClientOrderId: "c1",
+		OrderId:           "o1",
+		ProductId:         "BTC-USD",
+		Side:              model.OrderSideBuy,
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	report = <-reports
+	if report.Order.Status != model.OrderStatusCancelled {
+		t.Fatalf("unexpected report: %+v", report.Order)
+	}
+
+	if err := session.Logout(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	<-session.Done()
+
+	if err := session.Err(); err != nil {
+		t.Errorf("expected a clean logout - received: %v", err)
+	}
+}
+
+// The second session resumes the sequence numbers persisted by the first.
+func TestSeqNumPersistence(t *testing.T) {
+
+	logonSeqs := make(chan int64, 2)
+
+	a := newTestAcceptor(t, func(c *testFixConn) {
+		logon := acceptLogon(c)
+		if logon == nil {
+			return
+		}
+		logonSeqs <- logon.SeqNum()
+		acceptLogout(c)
+	})
+
+	path := filepath.Join(t.TempDir(), "seq.json")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	for idx := 0; idx < 2; idx++ {
+
+		store, err := NewFileStore(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		session := newTestSession(a).SetStore(store)
+
+		if err := session.Logon(ctx); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := session.Logout(ctx); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if first, second := <-logonSeqs, <-logonSeqs; first != 1 || second != 3 {
+		t.Errorf("expected logon seq nums: 1, 3 - received: %d, %d", first, second)
+	}
+
+	store, err := NewFileStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if next, _ := store.NextTargetSeqNum(); next != 5 {
+		t.Errorf("expected next target seq num: 5 - received: %d", next)
+	}
+}
+
+// The acceptor skips a sequence number, then asks for a resend.
+func TestResendRequest(t *testing.T) {
+
+	report := func(execId string) *Message {
+		return NewMessage(MsgTypeExecutionReport).
+			Set(TagOrderId, "o1").
+			Set(TagExecId, execId).
+			Set(TagOrdStatus, OrdStatusPartiallyFilled)
+	}
+
+	a := newTestAcceptor(t, func(c *testFixConn) {
+
+		if acceptLogon(c) == nil {
+			return
+		}
+
+		c.writeSeq(report("e3"), 3)
+
+		resend := c.readType(MsgTypeResendRequest)
+		if resend == nil || resend.GetString(TagBeginSeqNo) != "2" || resend.GetString(TagEndSeqNo) != "0" {
+			t.Errorf("unexpected resend request: %v", resend)
+			return
+		}
+
+		c.writeSeq(report("e2").Set(TagPossDupFlag, "Y"), 2)
+		c.writeSeq(report("e3").Set(TagPossDupFlag, "Y"), 3)
+
+		c.writeSeq(NewMessage(MsgTypeResendRequest).Set(TagBeginSeqNo, "1").Set(TagEndSeqNo, "0"), 4)
+
+		reset := c.readType(MsgTypeSequenceReset)
+		if reset == nil ||
+			reset.GetString(TagGapFillFlag) != "Y" ||
+			reset.GetString(TagPossDupFlag) != "Y" ||
+			reset.GetString(TagMsgSeqNum) != "1" ||
+			reset.GetString(TagNewSeqNo) != "3" {
+			t.Errorf("unexpected sequence reset: %v", reset)
+		}
+
+		c.acceptor.setSeq(4)
+
+		acceptLogout(c)
+	})
+
+	session := newTestSession(a)
+
+	reports := make(chan *ExecutionReport, 10)
+	session.OnExecutionReport(func(r *ExecutionReport) { reports <- r })
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	if err := session.Logon(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, expected := range []string{"e2", "e3"} {
+		select {
+		case r := <-reports:
+			if r.ExecId != expected {
+				t.Fatalf("expected exec id: %s - received: %s", expected, r.ExecId)
+			}
+		case <-ctx.Done():
+			t.Fatal("timed out waiting for the execution reports")
+		}
+	}
+
+	if err := session.Logout(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := session.Err(); err != nil {
+		t.Errorf("expected a clean logout - received: %v", err)
+	}
+
+	if next, _ := session.Store().NextTargetSeqNum(); next != 6 {
+		t.Errorf("expected next target seq num: 6 - received: %d", next)
+	}
+
+	select {
+	case r := <-reports:
+		t.Errorf("unexpected duplicate report: %s", r.ExecId)
+	default:
+	}
+}
+
+// The acceptor goes silent after the logon. The session must send a
+// TestRequest and fail when it is not answered.
+func TestHeartbeatTimeout(t *testing.T) {
+
+	testRequests := make(chan *Message, 1)
+
+	a := newTestAcceptor(t, func(c *testFixConn) {
+		if acceptLogon(c) == nil {
+			return
+		}
+		testRequests <- c.readType(MsgTypeTestRequest)
+		for c.read() != nil {
+		}
+	})
+
+	// Raised to a second
+	session := newTestSession(a).SetHeartbeatInterval(0)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	if err := session.Logon(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case <-session.Done():
+	case <-ctx.Done():
+		t.Fatal("timed out waiting for the heartbeat timeout")
+	}
+
+	if !errors.Is(session.Err(), ErrHeartbeatTimeout) {
+		t.Errorf("expected: %v - received: %v", ErrHeartbeatTimeout, session.Err())
+	}
+
+	if r := <-testRequests; r == nil || len(r.GetString(TagTestReqId)) == 0 {
+		t.Errorf("expected a test request - received: %v", r)
+	}
+}
+
+func TestHeartbeatInterval(t *testing.T) {
+
+	cases := []struct {
+		description string
+		interval    time.Duration
+		expected    time.Duration
+	}{
+		{
+			description: "TestHeartbeatInterval0",
+			interval:    0,
+			expected:    time.Second,
+		},
+		{
+			description: "TestHeartbeatInterval1",
+			interval:    -time.Second,
+			expected:    time.Second,
+		},
+		{
+			description: "TestHeartbeatInterval2",
+			interval:    3 * time.Nanosecond,
+			expected:    time.Second,
+		},
+		{
+			description: "TestHeartbeatInterval3",
+			interval:    2500 * time.Millisecond,
+			expected:    2 * time.Second,
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.description, func(t *testing.T) {
+			s := NewSession(testCredentials).SetHeartbeatInterval(tt.interval).(*sessionImpl)
+			if interval := s.interval(); interval != tt.expected {
+				t.Errorf("test: %s - expected: %v - received: %v", tt.description, tt.expected, interval)
+			}
+		})
+	}
+}
+
+func TestMessageLogMasksCredentials(t *testing.T) {
+
+	a := newTestAcceptor(t, func(c *testFixConn) {
+		if acceptLogon(c) == nil {
+			return
+		}
+		acceptLogout(c)
+	})
+
+	var log bytes.Buffer
+
+	session := newTestSession(a).SetMessageLog(&log)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	if err := session.Logon(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := session.Logout(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	var logon *Message
+
+	if err := ReplayMessageLog(bytes.NewReader(log.Bytes()), func(entry *LogEntry) error {
+		if entry.Direction == DirectionOut && entry.Message.MsgType() == MsgTypeLogon {
+			logon = entry.Message
+		}
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	if logon == nil {
+		t.Fatal("expected a logon in the message log")
+	}
+
+	for _, tag := range maskedTags {
+		if v := logon.GetString(tag); v != maskedValue {
+			t.Errorf("tag: %d - expected: %s - received: %s", tag, maskedValue, v)
+		}
+	}
+
+	if bytes.Contains(log.Bytes(), []byte("554=pass")) || bytes.Contains(log.Bytes(), []byte("9407=key")) {
+		t.Errorf("expected masked credentials - received: %s", log.String())
+	}
+}
+
+func TestLogonRejected(t *testing.T) {
+
+	a := newTestAcceptor(t, func(c *testFixConn) {
+		if c.readType(MsgTypeLogon) == nil {
+			return
+		}
+		c.write(NewMessage(MsgTypeLogout).Set(TagText, "invalid signature"))
+		c.read()
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	err := newTestSession(a).Logon(ctx)
+
+	var logoutErr *LogoutError
+	if !errors.As(err, &logoutErr) || logoutErr.Text != "invalid signature" {
+		t.Errorf("expected a logout error - received: %v", err)
+	}
+}
@@ -0,0 +1,177 @@
+/**
+ * Copyright 2024-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package fix
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// SeqStore persists the sequence numbers of a session, so a session resumed
+// after a restart continues the sequence instead of resetting it. Prime keeps
+// the sequence per SenderCompID for the trading day.
+type SeqStore interface {
+	NextSenderSeqNum() (int64, error)
+	NextTargetSeqNum() (int64, error)
+	SetNextSenderSeqNum(n int64) error
+	SetNextTargetSeqNum(n int64) error
+
+	// Reset sets both sequence numbers back to 1.
+	Reset() error
+}
+
+type seqNums struct {
+	NextSender int64 `json:"next_sender_seq_num"`
+	NextTarget int64 `json:"next_target_seq_num"`
+}
+
+func NewMemoryStore() SeqStore {
+	return &memoryStoreImpl{seq: seqNums{NextSender: 1, NextTarget: 1}}
+}
+
+type memoryStoreImpl struct {
+	seq seqNums
+	mu  sync.Mutex
+}
+
+func (s *memoryStoreImpl) NextSenderSeqNum() (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.seq.NextSender, nil
+}
+
+func (s *memoryStoreImpl) NextTargetSeqNum() (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.seq.NextTarget, nil
+}
+
+func (s *memoryStoreImpl) SetNextSenderSeqNum(n int64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.seq.NextSender = n
+	return nil
+}
+
+func (s *memoryStoreImpl) SetNextTargetSeqNum(n int64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.seq.NextTarget = n
+	return nil
+}
+
+func (s *memoryStoreImpl) Reset() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.seq = seqNums{NextSender: 1, NextTarget: 1}
+	return nil
+}
+
+// NewFileStore returns a store that persists the sequence numbers as JSON in
+// the file at path. The file is created on the first write and is replaced
+// atomically, so a crash never leaves a partial file.
+func NewFileStore(path string) (SeqStore, error) {
+
+	s := &fileStoreImpl{path: path, seq: seqNums{NextSender: 1, NextTarget: 1}}
+
+	b, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("unable to read seq store: %s - err: %w", path, err)
+	}
+
+	if err := json.Unmarshal(b, &s.seq); err != nil {
+		return nil, fmt.Errorf("unable to deserialize seq store: %s - err: %w", path, err)
+	}
+
+	return s, nil
+}
+
+type fileStoreImpl struct {
+	path string
+	seq  seqNums
+	mu   sync.Mutex
+}
+
+func (s *fileStoreImpl) NextSenderSeqNum() (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.seq.NextSender, nil
+}
+
+func (s *fileStoreImpl) NextTargetSeqNum() (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.seq.NextTarget, nil
+}
+
+func (s *fileStoreImpl) SetNextSenderSeqNum(n int64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.seq.NextSender = n
+	return s.write()
+}
+
+func (s *fileStoreImpl) SetNextTargetSeqNum(n int64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.seq.NextTarget = n
+	return s.write()
+}
+
+func (s *fileStoreImpl) Reset() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.seq = seqNums{NextSender: 1, NextTarget: 1}
+	return s.write()
+}
+
+func (s *fileStoreImpl) write() error {
+
+	b, err := json.Marshal(&s.seq)
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(s.path), filepath.Base(s.path)+".tmp")
+	if err != nil {
+		return fmt.Errorf("unable to write seq store: %s - err: %w", s.path, err)
+	}
+
+	if _, err := tmp.Write(b); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return fmt.Errorf("unable to write seq store: %s - err: %w", s.path, err)
+	}
+
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return fmt.Errorf("unable to write seq store: %s - err: %w", s.path, err)
+	}
+
+	if err := os.Rename(tmp.Name(), s.path); err != nil {
+		return fmt.Errorf("unable to write seq store: %s - err: %w", s.path, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,135 @@
+/**
+ * Copyright 2024-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package fix
+
+const (
+	TagAccount         = 1
+	TagAvgPx           = 6
+	TagBeginSeqNo      = 7
+	TagBeginString     = 8
+	TagBodyLength      = 9
+	TagCheckSum        = 10
+	TagClOrdId         = 11
+	TagCommission      = 12
+	TagCumQty          = 14
+	TagEndSeqNo        = 16
+	TagExecId          = 17
+	TagLastPx          = 31
+	TagLastShares      = 32
+	TagMsgSeqNum       = 34
+	TagMsgType         = 35
+	TagNewSeqNo        = 36
+	TagOrderId         = 37
+	TagOrderQty        = 38
+	TagOrdStatus       = 39
+	TagOrdType         = 40
+	TagOrigClOrdId     = 41
+	TagPossDupFlag     = 43
+	TagPrice           = 44
+	TagRefSeqNum       = 45
+	TagSenderCompId    = 49
+	TagSendingTime     = 52
+	TagSide            = 54
+	TagSymbol          = 55
+	TagTargetCompId    = 56
+	TagText            = 58
+	TagTimeInForce     = 59
+	TagTransactTime    = 60
+	TagRptSeq          = 83
+	TagRawData         = 96
+	TagEncryptMethod   = 98
+	TagHeartBtInt      = 108
+	TagTestReqId       = 112
+	TagOrigSendingTime = 122
+	TagGapFillFlag     = 123
+	TagExpireTime      = 126
+	TagExecType        = 150
+	TagLeavesQty       = 151
+	TagNoRelatedSym    = 146
+	TagCashOrderQty    = 152
+	TagEffectiveTime   = 168
+	TagMDReqId         = 262
+	TagSubscriptionReq = 263
+	TagMarketDepth     = 264
+	TagMDUpdateType    = 265
+	TagNoMDEntryTypes  = 267
+	TagNoMDEntries     = 268
+	TagMDEntryType     = 269
+	TagMDEntryPx       = 270
+	TagMDEntrySize     = 271
+	TagMDUpdateAction  = 279
+	TagMDReqRejReason  = 281
+	TagPassword        = 554
+	TagTargetStrategy  = 847
+	TagAccessKey       = 9407
+)
+
+const (
+	MsgTypeHeartbeat          = "0"
+	MsgTypeTestRequest        = "1"
+	MsgTypeResendRequest      = "2"
+	MsgTypeReject             = "3"
+	MsgTypeSequenceReset      = "4"
+	MsgTypeLogout             = "5"
+	MsgTypeExecutionReport    = "8"
+	MsgTypeOrderCancelReject  = "9"
+	MsgTypeLogon              = "A"
+	MsgTypeNewOrderSingle     = "D"
+	MsgTypeOrderCancelRequest = "F"
+	MsgTypeMarketDataRequest  = "V"
+	MsgTypeMarketDataSnapshot = "W"
+	MsgTypeMarketDataRefresh  = "X"
+	MsgTypeMarketDataReject   = "Y"
+)
+
+const (
+	SideBuy  = "1"
+	SideSell = "2"
+
+	OrdTypeMarket = "1"
+	OrdTypeLimit  = "2"
+
+	TargetStrategyLimit  = "L"
+	TargetStrategyMarket = "M"
+	TargetStrategyTwap   = "T"
+
+	TimeInForceGoodTillCancel    = "1"
+	TimeInForceImmediateOrCancel = "3"
+	TimeInForceGoodTillDate      = "6"
+
+	OrdStatusNew             = "0"
+	OrdStatusPartiallyFilled = "1"
+	OrdStatusFilled          = "2"
+	OrdStatusCanceled        = "4"
+	OrdStatusPendingCancel   = "6"
+	OrdStatusRejected        = "8"
+	OrdStatusPendingNew      = "A"
+	OrdStatusExpired         = "C"
+	OrdStatusPendingReplace  = "E"
+
+	SubscriptionRequestSnapshotAndUpdates = "1"
+	SubscriptionRequestUnsubscribe        = "2"
+
+	MDUpdateTypeIncremental = "1"
+
+	MDEntryTypeBid   = "0"
+	MDEntryTypeOffer = "1"
+
+	MDUpdateActionNew    = "0"
+	MDUpdateActionChange = "1"
+	MDUpdateActionDelete = "2"
+)
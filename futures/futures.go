@@ -0,0 +1,41 @@
+/**
+ * Copyright 2024-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package futures
+
+import (
+	"context"
+
+	"github.com/coinbase-samples/prime-sdk-go/client"
+)
+
+type FuturesService interface {
+	GetFcmBalance(ctx context.Context, request *GetFcmBalanceRequest) (*GetFcmBalanceResponse, error)
+	ListFcmPositions(ctx context.Context, request *ListFcmPositionsRequest) (*ListFcmPositionsResponse, error)
+	ListFcmSweeps(ctx context.Context, request *ListFcmSweepsRequest) (*ListFcmSweepsResponse, error)
+	ScheduleFcmSweep(ctx context.Context, request *ScheduleFcmSweepRequest) (*ScheduleFcmSweepResponse, error)
+	CancelFcmSweep(ctx context.Context, request *CancelFcmSweepRequest) (*CancelFcmSweepResponse, error)
+	SetFcmAutoSweep(ctx context.Context, request *SetFcmAutoSweepRequest) (*SetFcmAutoSweepResponse, error)
+	GetFcmRiskLimits(ctx context.Context, request *GetFcmRiskLimitsRequest) (*GetFcmRiskLimitsResponse, error)
+}
+
+func NewFuturesService(c client.RestClient) FuturesService {
+	return &futuresServiceImpl{client: c}
+}
+
+type futuresServiceImpl struct {
+	client client.RestClient
+}
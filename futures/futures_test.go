@@ -0,0 +1,167 @@
+/**
+ * Copyright 2024-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package futures
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/coinbase-samples/prime-sdk-go/client"
+	"github.com/coinbase-samples/prime-sdk-go/credentials"
+	"github.com/coinbase-samples/prime-sdk-go/model"
+)
+
+var futuresResponses = map[string]string{
+	"GET /entities/e1/futures/balance_summary": `{"balance_summary":{"portfolio_id":"p1","cfm_usd_balance":"250000.50","unrealized_pnl":"-1200.25","daily_realized_pnl":"300","excess_liquidity":"180000","futures_buying_power":"175000","initial_margin":"70000","maintenance_margin":"56000","clearing_calls_due":"0","deficit_due":"0"}}`,
+	"GET /entities/e1/futures/positions":       `{"positions":[{"product_id":"BIT-27DEC24-CDE","side":"LONG","number_of_contracts":"12","daily_realized_pnl":"0","unrealized_pnl":"845.10","current_price":"67250","avg_entry_price":"66550","expiration_time":"2024-12-27T16:00:00Z"}]}`,
+	"GET /entities/e1/futures/sweeps":          `{"sweeps":[{"id":"s1","requested_amount":{"currency":"USD","amount":"5000"},"should_sweep_all":false,"status":"PENDING","scheduled_time":"2024-06-01T22:00:00Z"}],"auto_sweep":true}`,
+	"POST /entities/e1/futures/sweeps":         `{"success":true,"request_id":"r1"}`,
+	"DELETE /entities/e1/futures/sweeps":       `{"success":true,"request_id":"r2"}`,
+	"POST /entities/e1/futures/auto_sweep":     `{"success":true,"request_id":"r3"}`,
+	"GET /entities/e1/futures/risk_limits":     `{"cfm_risk_limit":"1000000","cfm_risk_limit_utilization":"420000","cfm_total_open_orders_notional":"35000"}`,
+}
+
+func TestFutures(t *testing.T) {
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, ok := futuresResponses[fmt.Sprintf("%s %s", r.Method, r.URL.Path)]
+		if !ok {
+			t.Errorf("unexpected request: %s %s", r.Method, r.URL.Path)
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte(body))
+	}))
+	defer server.Close()
+
+	service := NewFuturesService(client.NewRestClient(&credentials.Credentials{}, http.Client{}).SetBaseUrl(server.URL))
+
+	entityId := "e1"
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	cases := []struct {
+		description string
+		call        func() (string, error)
+		expected    string
+	}{
+		{
+			description: "TestFutures0",
+			call: func() (string, error) {
+				response, err := service.GetFcmBalance(ctx, &GetFcmBalanceRequest{EntityId: entityId})
+				if err != nil {
+					return "", err
+				}
+				amount, err := response.Balance.ExcessLiquidityNum()
+				return amount.String(), err
+			},
+			expected: "180000",
+		},
+		{
+			description: "TestFutures1",
+			call: func() (string, error) {
+				response, err := service.ListFcmPositions(ctx, &ListFcmPositionsRequest{EntityId: entityId, ProductId: "BIT-27DEC24-CDE"})
+				if err != nil {
+					return "", err
+				}
+				if len(response.Positions) != 1 || response.Positions[0].Side != model.FcmPositionSideLong {
+					t.Errorf("test: TestFutures1 - unexpected positions: %v", response.Positions)
+				}
+				contracts, err := response.Positions[0].NumberOfContractsNum()
+				return contracts.String(), err
+			},
+			expected: "12",
+		},
+		{
+			description: "TestFutures2",
+			call: func() (string, error) {
+				response, err := service.ListFcmSweeps(ctx, &ListFcmSweepsRequest{EntityId: entityId})
+				if err != nil {
+					return "", err
+				}
+				if !response.AutoSweep || len(response.Sweeps) != 1 {
+					t.Errorf("test: TestFutures2 - unexpected sweeps: %v", response.Sweeps)
+				}
+				return response.Sweeps[0].Status, nil
+			},
+			expected: model.FcmSweepStatusPending,
+		},
+		{
+			description: "TestFutures3",
+			call: func() (string, error) {
+				response, err := service.ScheduleFcmSweep(ctx, &ScheduleFcmSweepRequest{EntityId: entityId, Currency: "USD", Amount: "5000"})
+				if err != nil {
+					return "", err
+				}
+				return response.RequestId, nil
+			},
+			expected: "r1",
+		},
+		{
+			description: "TestFutures4",
+			call: func() (string, error) {
+				response, err := service.CancelFcmSweep(ctx, &CancelFcmSweepRequest{EntityId: entityId})
+				if err != nil {
+					return "", err
+				}
+				return response.RequestId, nil
+			},
+			expected: "r2",
+		},
+		{
+			description: "TestFutures5",
+			call: func() (string, error) {
+				response, err := service.SetFcmAutoSweep(ctx, &SetFcmAutoSweepRequest{EntityId: entityId, AutoSweep: false})
+				if err != nil {
+					return "", err
+				}
+				return response.RequestId, nil
+			},
+			expected: "r3",
+		},
+		{
+			description: "TestFutures6",
+			call: func() (string, error) {
+				response, err := service.GetFcmRiskLimits(ctx, &GetFcmRiskLimitsRequest{EntityId: entityId})
+				if err != nil {
+					return "", err
+				}
+				utilization, err := response.CfmRiskLimitUtilizationNum()
+				return utilization.String(), err
+			},
+			expected: "420000",
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.description, func(t *testing.T) {
+			received, err := tt.call()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if received != tt.expected {
+				t.Errorf("test: %s - expected: %s - received: %s", tt.description, tt.expected, received)
+			}
+		})
+	}
+}
@@ -0,0 +1,68 @@
+/**
+ * Copyright 2024-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package futures
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/coinbase-samples/core-go"
+	"github.com/coinbase-samples/prime-sdk-go/client"
+	"github.com/coinbase-samples/prime-sdk-go/model"
+)
+
+type ListFcmPositionsRequest struct {
+	EntityId  string `json:"entity_id"`
+	ProductId string `json:"product_id"`
+}
+
+type ListFcmPositionsResponse struct {
+	Positions []*model.FcmPosition     `json:"positions"`
+	Request   *ListFcmPositionsRequest `json:"request"`
+}
+
+// ListFcmPositions returns the open futures positions of an entity. Set the
+// ProductId to return the position of a single contract.
+func (s *futuresServiceImpl) ListFcmPositions(
+	ctx context.Context,
+	request *ListFcmPositionsRequest,
+) (*ListFcmPositionsResponse, error) {
+
+	path := fmt.Sprintf("/entities/%s/futures/positions", request.EntityId)
+
+	var queryParams string
+	if len(request.ProductId) > 0 {
+		queryParams = core.AppendHttpQueryParam(queryParams, "product_id", request.ProductId)
+	}
+
+	response := &ListFcmPositionsResponse{Request: request}
+
+	if err := core.HttpGet(
+		ctx,
+		s.client,
+		path,
+		queryParams,
+		client.DefaultSuccessHttpStatusCodes,
+		request,
+		response,
+		s.client.HeadersFunc(),
+	); err != nil {
+		return nil, err
+	}
+
+	return response, nil
+}
@@ -0,0 +1,66 @@
+/**
+ * Copyright 2024-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package futures
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/coinbase-samples/core-go"
+	"github.com/coinbase-samples/prime-sdk-go/client"
+)
+
+type ScheduleFcmSweepRequest struct {
+	EntityId string `json:"entity_id"`
+	Currency string `json:"currency"`
+
+	// Leave empty to sweep all available USD
+	Amount string `json:"amount,omitempty"`
+}
+
+type ScheduleFcmSweepResponse struct {
+	Success   bool                     `json:"success"`
+	RequestId string                   `json:"request_id"`
+	Request   *ScheduleFcmSweepRequest `json:"request"`
+}
+
+// ScheduleFcmSweep schedules a sweep of USD from the futures (FCM) account to
+// the spot trading balance. Only one sweep can be pending at a time.
+func (s *futuresServiceImpl) ScheduleFcmSweep(
+	ctx context.Context,
+	request *ScheduleFcmSweepRequest,
+) (*ScheduleFcmSweepResponse, error) {
+
+	path := fmt.Sprintf("/entities/%s/futures/sweeps", request.EntityId)
+
+	response := &ScheduleFcmSweepResponse{Request: request}
+
+	if err := core.HttpPost(
+		ctx,
+		s.client,
+		path,
+		core.EmptyQueryParams,
+		client.DefaultSuccessHttpStatusCodes,
+		request,
+		response,
+		s.client.HeadersFunc(),
+	); err != nil {
+		return nil, err
+	}
+
+	return response, nil
+}
@@ -4,11 +4,12 @@ go 1.19
 
 require (
 	github.com/coinbase-samples/core-go v0.2.0
+	github.com/gorilla/websocket v1.5.3
 	github.com/shopspring/decimal v1.4.0
+	gopkg.in/yaml.v3 v3.0.1
 )
 
 require (
-	github.com/gorilla/websocket v1.5.3 // indirect
 	golang.org/x/net v0.30.0 // indirect
 	golang.org/x/text v0.19.0 // indirect
 )
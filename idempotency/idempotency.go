@@ -0,0 +1,87 @@
+/**
+ * Copyright 2024-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package idempotency
+
+import (
+	"crypto/rand"
+	"crypto/sha1"
+	"encoding/binary"
+	"fmt"
+	"time"
+)
+
+// DefaultNamespace is used to derive keys from business references when a
+// key store is not configured with its own namespace.
+const DefaultNamespace = "prime-sdk-go"
+
+// Generator returns a new, random idempotency key.
+type Generator func() (string, error)
+
+// DefaultGenerator is used by NewKey. It can be replaced with NewUuidV7 if
+// time ordered keys are preferred.
+var DefaultGenerator Generator = NewUuidV4
+
+// NewKey returns a new idempotency key from the DefaultGenerator.
+func NewKey() (string, error) {
+	return DefaultGenerator()
+}
+
+// NewUuidV4 returns a random (version 4) UUID.
+func NewUuidV4() (string, error) {
+	var u [16]byte
+	if _, err := rand.Read(u[:]); err != nil {
+		return "", fmt.Errorf("unable to read random bytes: %w", err)
+	}
+	return formatUuid(u, 4), nil
+}
+
+// NewUuidV7 returns a time ordered (version 7) UUID.
+func NewUuidV7() (string, error) {
+	var u [16]byte
+	if _, err := rand.Read(u[6:]); err != nil {
+		return "", fmt.Errorf("unable to read random bytes: %w", err)
+	}
+
+	var ts [8]byte
+	binary.BigEndian.PutUint64(ts[:], uint64(time.Now().UnixMilli()))
+	copy(u[:6], ts[2:])
+
+	return formatUuid(u, 7), nil
+}
+
+// DeriveKey returns a UUID formatted key from the SHA-1 of the namespace and
+// reference. It carries the version 5 bits, but it is not an RFC 4122
+// name based UUID, since the namespace is a string rather than a UUID. The
+// same namespace and reference always produce the same key, which allows a
+// request to be safely replayed after a crash.
+func DeriveKey(namespace, reference string) string {
+	h := sha1.New()
+	h.Write([]byte(namespace))
+	h.Write([]byte{0})
+	h.Write([]byte(reference))
+
+	var u [16]byte
+	copy(u[:], h.Sum(nil))
+
+	return formatUuid(u, 5)
+}
+
+func formatUuid(u [16]byte, version byte) string {
+	u[6] = (u[6] & 0x0f) | (version << 4)
+	u[8] = (u[8] & 0x3f) | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", u[0:4], u[4:6], u[6:8], u[8:10], u[10:16])
+}
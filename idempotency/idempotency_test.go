@@ -0,0 +1,155 @@
+/**
+ * Copyright 2024-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package idempotency
+
+import (
+	"path/filepath"
+	"regexp"
+	"testing"
+)
+
+var uuidPattern = regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-([0-9a-f])[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`)
+
+func TestUuidVersions(t *testing.T) {
+
+	cases := []struct {
+		description string
+		generator   Generator
+		version     string
+	}{
+		{
+			description: "TestUuidVersions0",
+			generator:   NewUuidV4,
+			version:     "4",
+		},
+		{
+			description: "TestUuidVersions1",
+			generator:   NewUuidV7,
+			version:     "7",
+		},
+		{
+			description: "TestUuidVersions2",
+			generator:   func() (string, error) { return DeriveKey(DefaultNamespace, "payout-1"), nil },
+			version:     "5",
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.description, func(t *testing.T) {
+			key, err := tt.generator()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			m := uuidPattern.FindStringSubmatch(key)
+			if m == nil {
+				t.Fatalf("test: %s - invalid uuid: %s", tt.description, key)
+			}
+
+			if m[1] != tt.version {
+				t.Errorf("test: %s - expected version: %s - received: %s", tt.description, tt.version, m[1])
+			}
+		})
+	}
+}
+
+func TestDeriveKey(t *testing.T) {
+
+	if DeriveKey("ns", "payout-1") != DeriveKey("ns", "payout-1") {
+		t.Error("expected the same reference to derive the same key")
+	}
+
+	if DeriveKey("ns", "payout-1") == DeriveKey("ns", "payout-2") {
+		t.Error("expected different references to derive different keys")
+	}
+
+	if DeriveKey("ns1", "payout-1") == DeriveKey("ns2", "payout-1") {
+		t.Error("expected different namespaces to derive different keys")
+	}
+}
+
+func TestResolveKey(t *testing.T) {
+
+	store := NewDerivedKeyStore("ns")
+
+	key, err := ResolveKey(store, "stake", "k1", "payout-1")
+	if err != nil || key != "k1" {
+		t.Errorf("expected the caller supplied key - received: %s - err: %v", key, err)
+	}
+
+	stake, _ := ResolveKey(store, "stake", "", "payout-1")
+	unstake, _ := ResolveKey(store, "unstake", "", "payout-1")
+	if stake == unstake {
+		t.Error("expected different operations to resolve different keys")
+	}
+
+	if again, _ := ResolveKey(store, "stake", "", "payout-1"); again != stake {
+		t.Errorf("expected the same key for the same reference - received: %s, %s", stake, again)
+	}
+
+	first, _ := ResolveKey(store, "stake", "", "")
+	second, _ := ResolveKey(store, "stake", "", "")
+	if len(first) == 0 || first == second {
+		t.Error("expected a new key without a reference")
+	}
+}
+
+func TestFileKeyStore(t *testing.T) {
+
+	path := filepath.Join(t.TempDir(), "keys.json")
+
+	store, err := NewFileKeyStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	key, err := store.Key("payout-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if key == DeriveKey(DefaultNamespace, "payout-1") {
+		t.Error("expected a random key")
+	}
+
+	reloaded, err := NewFileKeyStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	replayed, err := reloaded.Key("payout-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if key != replayed {
+		t.Errorf("expected persisted key: %s - received: %s", key, replayed)
+	}
+
+	other, err := NewFileKeyStore(filepath.Join(t.TempDir(), "keys.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if otherKey, _ := other.Key("payout-1"); otherKey == key {
+		t.Errorf("expected another store to generate another key - received: %s", otherKey)
+	}
+
+	if _, err := store.Key(""); err == nil {
+		t.Error("expected an error for an empty reference")
+	}
+}
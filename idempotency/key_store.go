@@ -0,0 +1,162 @@
+/**
+ * Copyright 2024-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package idempotency
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// KeyStore returns the idempotency key for a caller supplied business
+// reference, e.g., a payout id. A reference always maps to the same key.
+type KeyStore interface {
+	Key(reference string) (string, error)
+}
+
+// NewDerivedKeyStore returns a key store that derives keys from the
+// namespace and reference with DeriveKey. Nothing is kept, since the same
+// reference derives the same key in any process, including after a restart.
+func NewDerivedKeyStore(namespace string) KeyStore {
+	return &derivedKeyStore{namespace: namespace}
+}
+
+type derivedKeyStore struct {
+	namespace string
+}
+
+func (s *derivedKeyStore) Key(reference string) (string, error) {
+	if len(reference) == 0 {
+		return "", errors.New("reference not set")
+	}
+	return DeriveKey(s.namespace, reference), nil
+}
+
+// NewFileKeyStore returns a key store that generates a random key with
+// NewKey for each new reference and persists the reference to key mapping
+// as JSON at path, so keys cannot be derived from references and remain
+// stable across process restarts. The file grows with every reference and
+// must not be shared by processes that run at the same time.
+func NewFileKeyStore(path string) (KeyStore, error) {
+
+	s := &fileKeyStore{path: path, keys: make(map[string]string)}
+
+	b, err := os.ReadFile(path)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("unable to read key store: %s - err: %w", path, err)
+	}
+
+	if len(b) > 0 {
+		if err := json.Unmarshal(b, &s.keys); err != nil {
+			return nil, fmt.Errorf("unable to deserialize key store: %s - err: %w", path, err)
+		}
+	}
+
+	return s, nil
+}
+
+type fileKeyStore struct {
+	path string
+	keys map[string]string
+	mu   sync.Mutex
+}
+
+func (s *fileKeyStore) Key(reference string) (string, error) {
+	if len(reference) == 0 {
+		return "", errors.New("reference not set")
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if key, ok := s.keys[reference]; ok {
+		return key, nil
+	}
+
+	key, err := NewKey()
+	if err != nil {
+		return "", err
+	}
+
+	s.keys[reference] = key
+
+	if err := s.save(); err != nil {
+		delete(s.keys, reference)
+		return "", err
+	}
+
+	return key, nil
+}
+
+// save writes to a temporary file and renames it, so a crash never leaves
+// a partially written store behind.
+func (s *fileKeyStore) save() error {
+
+	b, err := json.MarshalIndent(s.keys, "", "  ")
+	if err != nil {
+		return fmt.Errorf("unable to serialize key store: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(s.path), filepath.Base(s.path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("unable to create key store file: %w", err)
+	}
+
+	if _, err := tmp.Write(b); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return fmt.Errorf("unable to write key store: %w", err)
+	}
+
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return fmt.Errorf("unable to sync key store: %w", err)
+	}
+
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return fmt.Errorf("unable to close key store: %w", err)
+	}
+
+	if err := os.Rename(tmp.Name(), s.path); err != nil {
+		os.Remove(tmp.Name())
+		return fmt.Errorf("unable to replace key store: %s - err: %w", s.path, err)
+	}
+
+	return nil
+}
+
+// ResolveKey returns the key to send with a request. A caller supplied key
+// is always used. Otherwise, the key is resolved through the store from the
+// operation and reference, if set, so the same reference used for two
+// operations gives two keys, or a new key is generated.
+func ResolveKey(store KeyStore, operation, key, reference string) (string, error) {
+
+	if len(key) > 0 {
+		return key, nil
+	}
+
+	if len(reference) > 0 {
+		return store.Key(operation + "/" + reference)
+	}
+
+	return NewKey()
+}
@@ -17,7 +17,10 @@
 package model
 
 import (
+	"encoding/hex"
 	"fmt"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/coinbase-samples/core-go"
@@ -45,9 +48,45 @@ const (
 	OrderTypeTwap   = "TWAP"
 	OrderTypeBlock  = "BLOCK"
 
+	OrderStatusPending   = "PENDING"
+	OrderStatusOpen      = "OPEN"
+	OrderStatusFilled    = "FILLED"
+	OrderStatusCancelled = "CANCELLED"
+	OrderStatusExpired   = "EXPIRED"
+	OrderStatusFailed    = "FAILED"
+
 	TimeInForceGoodUntilTime      = "GOOD_UNTIL_DATE_TIME"
 	TimeInForceGoodUntilCancelled = "GOOD_UNTIL_CANCELLED"
 	TimeInForceImmediateOrCancel  = "IMMEDIATE_OR_CANCEL"
+
+	OnchainTransactionStatusPending   = "PENDING"
+	OnchainTransactionStatusSigned    = "SIGNED"
+	OnchainTransactionStatusBroadcast = "BROADCAST"
+	OnchainTransactionStatusConfirmed = "CONFIRMED"
+	OnchainTransactionStatusFailed    = "FAILED"
+	OnchainTransactionStatusReplaced  = "REPLACED"
+
+	TransactionStatusCreated      = "TRANSACTION_CREATED"
+	TransactionStatusRequested    = "TRANSACTION_REQUESTED"
+	TransactionStatusApproved     = "TRANSACTION_APPROVED"
+	TransactionStatusProcessing   = "TRANSACTION_PROCESSING"
+	TransactionStatusBroadcasting = "TRANSACTION_BROADCASTING"
+	TransactionStatusDone         = "TRANSACTION_DONE"
+	TransactionStatusCancelled    = "TRANSACTION_CANCELLED"
+	TransactionStatusRejected     = "TRANSACTION_REJECTED"
+	TransactionStatusFailed       = "TRANSACTION_FAILED"
+	TransactionStatusExpired      = "TRANSACTION_EXPIRED"
+
+	ActivityStatusProcessing = "ACTIVITY_STATUS_PROCESSING"
+	ActivityStatusCompleted  = "ACTIVITY_STATUS_COMPLETED"
+	ActivityStatusCancelled  = "ACTIVITY_STATUS_CANCELLED"
+	ActivityStatusExpired    = "ACTIVITY_STATUS_EXPIRED"
+	ActivityStatusFailed     = "ACTIVITY_STATUS_FAILED"
+
+	AddressBookStatePendingApproval = "PENDING_APPROVAL"
+	AddressBookStateActive          = "ACTIVE"
+	AddressBookStateRejected        = "REJECTED"
+	AddressBookStateArchived        = "ARCHIVED"
 )
 
 type ErrorMessage struct {
@@ -100,11 +139,69 @@ func (b Balance) HoldsNum() (holds decimal.Decimal, err error) {
 	return
 }
 
+func (b Balance) BondedAmountNum() (amount decimal.Decimal, err error) {
+	amount, err = core.StrToNum(b.BondedAmount)
+	if err != nil {
+		err = fmt.Errorf("Invalid bonded amount: %s - symbol: %s - msg: %v", b.BondedAmount, b.Symbol, err)
+	}
+	return
+}
+
+func (b Balance) UnbondingAmountNum() (amount decimal.Decimal, err error) {
+	amount, err = core.StrToNum(b.UnbondingAmount)
+	if err != nil {
+		err = fmt.Errorf("Invalid unbonding amount: %s - symbol: %s - msg: %v", b.UnbondingAmount, b.Symbol, err)
+	}
+	return
+}
+
+func (b Balance) BondableAmountNum() (amount decimal.Decimal, err error) {
+	amount, err = core.StrToNum(b.BondableAmount)
+	if err != nil {
+		err = fmt.Errorf("Invalid bondable amount: %s - symbol: %s - msg: %v", b.BondableAmount, b.Symbol, err)
+	}
+	return
+}
+
+func (b Balance) PendingRewardsAmountNum() (amount decimal.Decimal, err error) {
+	amount, err = core.StrToNum(b.PendingRewardsAmount)
+	if err != nil {
+		err = fmt.Errorf("Invalid pending rewards amount: %s - symbol: %s - msg: %v", b.PendingRewardsAmount, b.Symbol, err)
+	}
+	return
+}
+
 type BalanceWithHolds struct {
 	Total string `json:"total"`
 	Holds string `json:"holds"`
 }
 
+// EntityBalance is the balance of an asset aggregated across the portfolios
+// of an entity.
+type EntityBalance struct {
+	Symbol        string `json:"symbol"`
+	LongAmount    string `json:"long_amount"`
+	LongNotional  string `json:"long_notional"`
+	ShortAmount   string `json:"short_amount"`
+	ShortNotional string `json:"short_notional"`
+}
+
+func (b EntityBalance) LongAmountNum() (amount decimal.Decimal, err error) {
+	amount, err = core.StrToNum(b.LongAmount)
+	if err != nil {
+		err = fmt.Errorf("invalid long amount: %s - symbol: %s - err: %w", b.LongAmount, b.Symbol, err)
+	}
+	return
+}
+
+func (b EntityBalance) ShortAmountNum() (amount decimal.Decimal, err error) {
+	amount, err = core.StrToNum(b.ShortAmount)
+	if err != nil {
+		err = fmt.Errorf("invalid short amount: %s - symbol: %s - err: %w", b.ShortAmount, b.Symbol, err)
+	}
+	return
+}
+
 type PaginationParams struct {
 	Cursor        string `json:"cursor"`
 	Limit         string `json:"limit"`
@@ -162,6 +259,10 @@ type Allocation struct {
 type BlockchainAddress struct {
 	Address           string `json:"address"`
 	AccountIdentifier string `json:"account_identifier"`
+
+	// The network of the address. Required for assets supported on more than
+	// one network; if empty, the default network of the asset is used.
+	Network *Network `json:"network,omitempty"`
 }
 
 type Pagination struct {
@@ -245,6 +346,8 @@ func (p Product) QuoteIncrementNum() (amount decimal.Decimal, err error) {
 
 type OrderFill struct {
 	Id             string    `json:"id"`
+	OrderId        string    `json:"order_id"`
+	ClientOrderId  string    `json:"client_order_id"`
 	Side           string    `json:"side"`
 	ProductId      string    `json:"product_id"`
 	FilledQuantity string    `json:"filled_quantity"`
@@ -299,6 +402,7 @@ type Order struct {
 	// Used for describe order and create order preview
 	Id                 string `json:"id,omitempty"`
 	UserId             string `json:"user_id,omitempty"`
+	Status             string `json:"status,omitempty"`
 	Created            string `json:"created_at,omitempty"`
 	FilledQuantity     string `json:"filled_quantity,omitempty"`
 	FilledValue        string `json:"filled_value,omitempty"`
@@ -311,6 +415,44 @@ type Order struct {
 	Slippage           string `json:"slippage,omitempty"`
 }
 
+// IsTerminal returns true if the order can no longer be filled or cancelled.
+func (o Order) IsTerminal() bool {
+	switch o.Status {
+	case OrderStatusFilled, OrderStatusCancelled, OrderStatusExpired, OrderStatusFailed:
+		return true
+	}
+	return false
+}
+
+type Quote struct {
+	QuoteId              string    `json:"quote_id"`
+	ExpirationTime       time.Time `json:"expiration_time"`
+	BestPrice            string    `json:"best_price"`
+	OrderTotal           string    `json:"order_total"`
+	PriceInclusiveOfFees string    `json:"price_inclusive_of_fees"`
+}
+
+// Expired returns true if the quote can no longer be accepted at time t.
+func (q Quote) Expired(t time.Time) bool {
+	return !t.Before(q.ExpirationTime)
+}
+
+func (q Quote) BestPriceNum() (price decimal.Decimal, err error) {
+	price, err = core.StrToNum(q.BestPrice)
+	if err != nil {
+		err = fmt.Errorf("invalid quote best price: %s - id: %s - err: %w", q.BestPrice, q.QuoteId, err)
+	}
+	return
+}
+
+func (q Quote) OrderTotalNum() (total decimal.Decimal, err error) {
+	total, err = core.StrToNum(q.OrderTotal)
+	if err != nil {
+		err = fmt.Errorf("invalid quote order total: %s - id: %s - err: %w", q.OrderTotal, q.QuoteId, err)
+	}
+	return
+}
+
 type Transaction struct {
 	Id                string    `json:"id"`
 	WalletId          string    `json:"wallet_id"`
@@ -331,6 +473,92 @@ type Transaction struct {
 	DestinationSymbol string    `json:"destination_symbol"`
 }
 
+// IsTerminal returns true if the transaction is done or will not complete.
+func (t Transaction) IsTerminal() bool {
+	switch t.Status {
+	case TransactionStatusDone, TransactionStatusCancelled, TransactionStatusRejected, TransactionStatusFailed, TransactionStatusExpired:
+		return true
+	}
+	return false
+}
+
+// EvmParams are the EVM specific parameters of an onchain transaction. Gas
+// values are in wei.
+type EvmParams struct {
+	ChainId string `json:"chain_id,omitempty"`
+	Nonce   string `json:"nonce,omitempty"`
+
+	// Set to true to keep the gas values of the raw transaction instead of
+	// letting Prime price them at signing time
+	DisableDynamicGas bool `json:"disable_dynamic_gas"`
+
+	// Set to speed up or cancel a pending transaction with the same nonce
+	ReplacedTransactionId string `json:"replaced_transaction_id,omitempty"`
+
+	GasLimit             string `json:"gas_limit,omitempty"`
+	GasPrice             string `json:"gas_price,omitempty"`
+	MaxFeePerGas         string `json:"max_fee_per_gas,omitempty"`
+	MaxPriorityFeePerGas string `json:"max_priority_fee_per_gas,omitempty"`
+}
+
+func (p EvmParams) NonceNum() (nonce uint64, err error) {
+	nonce, err = strconv.ParseUint(p.Nonce, 10, 64)
+	if err != nil {
+		err = fmt.Errorf("invalid nonce: %s - msg: %v", p.Nonce, err)
+	}
+	return
+}
+
+func (p EvmParams) ChainIdNum() (chainId uint64, err error) {
+	chainId, err = strconv.ParseUint(p.ChainId, 10, 64)
+	if err != nil {
+		err = fmt.Errorf("invalid chain id: %s - msg: %v", p.ChainId, err)
+	}
+	return
+}
+
+// OnchainRpc configures the broadcast of a signed onchain transaction.
+type OnchainRpc struct {
+	// Set to true to only sign the transaction; the caller broadcasts it
+	SkipBroadcast bool `json:"skip_broadcast"`
+
+	// An optional custom RPC endpoint to broadcast through
+	Url string `json:"url,omitempty"`
+}
+
+type OnchainTransaction struct {
+	Id          string `json:"id"`
+	WalletId    string `json:"wallet_id"`
+	PortfolioId string `json:"portfolio_id"`
+
+	// The network, e.g., ethereum-mainnet or solana-mainnet
+	Network string `json:"network"`
+	Status  string `json:"status"`
+
+	RawUnsignedTransaction string     `json:"raw_unsigned_txn"`
+	SignedTransaction      string     `json:"signed_txn"`
+	TransactionHash        string     `json:"tx_hash"`
+	EvmParams              *EvmParams `json:"evm_params"`
+
+	// The broadcast result, set once the transaction is broadcast
+	BroadcastStatus string `json:"broadcast_status"`
+	BroadcastError  string `json:"broadcast_error"`
+
+	NetworkFees string    `json:"network_fees"`
+	Created     time.Time `json:"created_at"`
+	Updated     time.Time `json:"updated_at"`
+	Completed   time.Time `json:"completed_at"`
+}
+
+// IsTerminal returns true if the transaction can no longer change state.
+func (t OnchainTransaction) IsTerminal() bool {
+	switch t.Status {
+	case OnchainTransactionStatusConfirmed, OnchainTransactionStatusFailed, OnchainTransactionStatusReplaced:
+		return true
+	}
+	return false
+}
+
 type Transfer struct {
 	Type  string `json:"type"`
 	Value string `json:"value"`
@@ -345,21 +573,44 @@ func (tr Transfer) ValueNum() (amount decimal.Decimal, err error) {
 }
 
 type Activity struct {
-	Id              string           `json:"id"`
-	ReferenceId     string           `json:"reference_id"`
-	Category        string           `json:"category"`
-	PrimaryType     string           `json:"type"`
-	SecondaryType   string           `json:"secondary_type"`
-	Status          string           `json:"status"`
-	CreatedBy       string           `json:"created_by"`
-	Title           string           `json:"title"`
-	Description     string           `json:"description"`
-	UserActions     []*UserAction    `json:"user_actions"`
-	AccountMetadata *AccountMetadata `json:"account_metadata"`
-	OrdersMetadata  *OrdersMetadata  `json:"orders_metadata"`
-	Symbols         []string         `json:"symbols"`
-	Created         string           `json:"created_at"`
-	Updated         string           `json:"updated_at"`
+	Id                   string                `json:"id"`
+	ReferenceId          string                `json:"reference_id"`
+	Category             string                `json:"category"`
+	PrimaryType          string                `json:"type"`
+	SecondaryType        string                `json:"secondary_type"`
+	Status               string                `json:"status"`
+	CreatedBy            string                `json:"created_by"`
+	Title                string                `json:"title"`
+	Description          string                `json:"description"`
+	UserActions          []*UserAction         `json:"user_actions"`
+	TransactionsMetadata *TransactionsMetadata `json:"transactions_metadata"`
+	AccountMetadata      *AccountMetadata      `json:"account_metadata"`
+	OrdersMetadata       *OrdersMetadata       `json:"orders_metadata"`
+	Symbols              []string              `json:"symbols"`
+	Created              string                `json:"created_at"`
+	Updated              string                `json:"updated_at"`
+}
+
+// Consensus returns the approval state of the activity, from the transaction
+// or account metadata, or nil if the activity has none.
+func (a Activity) Consensus() *Consensus {
+	if a.TransactionsMetadata != nil && a.TransactionsMetadata.Consensus != nil {
+		return a.TransactionsMetadata.Consensus
+	}
+	if a.AccountMetadata != nil {
+		return a.AccountMetadata.Consensus
+	}
+	return nil
+}
+
+// IsTerminal returns true if the activity is no longer processing, e.g., it
+// was approved and completed or it was rejected, cancelled or expired.
+func (a Activity) IsTerminal() bool {
+	switch a.Status {
+	case ActivityStatusCompleted, ActivityStatusCancelled, ActivityStatusExpired, ActivityStatusFailed:
+		return true
+	}
+	return false
 }
 
 type TransactionsMetadata struct {
@@ -405,20 +656,238 @@ type AddressBookEntryAddedBy struct {
 	AvatarUrl string `json:"avatar_url"`
 }
 
+// AddressGroup is a named set of addresses on one network type, e.g., EVM
+// addresses shared across chains.
+type AddressGroup struct {
+	Id          string               `json:"uuid"`
+	Name        string               `json:"name"`
+	NetworkType string               `json:"network_type"`
+	Addresses   []*AddressGroupEntry `json:"addresses"`
+}
+
+type AddressGroupEntry struct {
+	Name     string   `json:"name"`
+	Address  string   `json:"address"`
+	ChainIds []string `json:"chain_ids"`
+}
+
+// Contains returns true if the address is a member of the group. Hex EVM
+// addresses match in any case, e.g., with or without checksum casing.
+func (g AddressGroup) Contains(address string) bool {
+	address = canonicalGroupAddress(address)
+	for _, a := range g.Addresses {
+		if canonicalGroupAddress(a.Address) == address {
+			return true
+		}
+	}
+	return false
+}
+
+// Add adds the entries that are not yet members and returns the number
+// added.
+func (g *AddressGroup) Add(entries ...*AddressGroupEntry) (added int) {
+	for _, e := range entries {
+		if !g.Contains(e.Address) {
+			g.Addresses = append(g.Addresses, e)
+			added++
+		}
+	}
+	return
+}
+
+// Remove removes the addresses from the group and returns the number
+// removed. Addresses match as in Contains.
+func (g *AddressGroup) Remove(addresses ...string) (removed int) {
+	remove := make(map[string]bool, len(addresses))
+	for _, a := range addresses {
+		remove[canonicalGroupAddress(a)] = true
+	}
+
+	kept := g.Addresses[:0]
+	for _, a := range g.Addresses {
+		if remove[canonicalGroupAddress(a.Address)] {
+			removed++
+			continue
+		}
+		kept = append(kept, a)
+	}
+	g.Addresses = kept
+	return
+}
+
+// canonicalGroupAddress lowercases hex EVM addresses, the same way as
+// addressvalidation.CanonicalAddress, which depends on this package.
+func canonicalGroupAddress(address string) string {
+	address = strings.TrimSpace(address)
+	if len(address) == 42 && strings.HasPrefix(strings.ToLower(address), "0x") {
+		if _, err := hex.DecodeString(address[2:]); err == nil {
+			return strings.ToLower(address)
+		}
+	}
+	return address
+}
+
 type Asset struct {
-	Name             string `json:"name"`
-	Symbol           string `json:"symbol"`
-	DecimalPrecision string `json:"decial_precision"`
-	TradingSupported bool   `json:"trading_supported"`
-	ExplorerUrl      string `json:"explorer_url"`
+	Name             string          `json:"name"`
+	Symbol           string          `json:"symbol"`
+	DecimalPrecision string          `json:"decial_precision"`
+	TradingSupported bool            `json:"trading_supported"`
+	ExplorerUrl      string          `json:"explorer_url"`
+	Networks         []*AssetNetwork `json:"networks"`
+}
+
+// Network returns the network of the asset with the id.
+func (a Asset) Network(id string) (*AssetNetwork, bool) {
+	for _, n := range a.Networks {
+		if n.Network != nil && n.Network.Id == id {
+			return n, true
+		}
+	}
+	return nil, false
+}
+
+// DefaultNetwork returns the network used when a request does not specify
+// one.
+func (a Asset) DefaultNetwork() (*AssetNetwork, bool) {
+	for _, n := range a.Networks {
+		if n.Default {
+			return n, true
+		}
+	}
+	return nil, false
+}
+
+// CheapestNetwork returns the network with the lowest withdrawal fee that
+// accepts a withdrawal of amount. Ties go to the default network.
+func (a Asset) CheapestNetwork(amount decimal.Decimal) (*AssetNetwork, error) {
+
+	var (
+		cheapest    *AssetNetwork
+		cheapestFee decimal.Decimal
+	)
+
+	for _, n := range a.Networks {
+
+		ok, err := n.AcceptsWithdrawal(amount)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			continue
+		}
+
+		fee, err := n.WithdrawalFeeNum()
+		if err != nil {
+			return nil, err
+		}
+
+		if cheapest == nil || fee.LessThan(cheapestFee) || (fee.Equal(cheapestFee) && n.Default) {
+			cheapest = n
+			cheapestFee = fee
+		}
+	}
+
+	if cheapest == nil {
+		return nil, fmt.Errorf("no network accepts a withdrawal of: %s - symbol: %s", amount, a.Symbol)
+	}
+
+	return cheapest, nil
+}
+
+// AssetNetwork is a network an asset is supported on, with its withdrawal
+// limits and fee. Empty limits are not enforced.
+type AssetNetwork struct {
+	Network                *Network `json:"network"`
+	Name                   string   `json:"name"`
+	MaxDecimals            string   `json:"max_decimals"`
+	Default                bool     `json:"default"`
+	TradingSupported       bool     `json:"trading_supported"`
+	VaultSupported         bool     `json:"vault_supported"`
+	DestinationTagRequired bool     `json:"destination_tag_required"`
+	NetworkLink            string   `json:"network_link"`
+	MinWithdrawalAmount    string   `json:"min_withdrawal_amt"`
+	MaxWithdrawalAmount    string   `json:"max_withdrawal_amt"`
+	WithdrawalFee          string   `json:"withdrawal_fee"`
+
+	// The number of confirmations before a deposit is credited
+	RequiredConfirmations int32 `json:"required_confirmations"`
+}
+
+func (n AssetNetwork) MinWithdrawalAmountNum() (amount decimal.Decimal, err error) {
+	return optionalNum(n.MinWithdrawalAmount, "min withdrawal amount", n.Name)
+}
+
+func (n AssetNetwork) MaxWithdrawalAmountNum() (amount decimal.Decimal, err error) {
+	return optionalNum(n.MaxWithdrawalAmount, "max withdrawal amount", n.Name)
+}
+
+func (n AssetNetwork) WithdrawalFeeNum() (fee decimal.Decimal, err error) {
+	return optionalNum(n.WithdrawalFee, "withdrawal fee", n.Name)
+}
+
+// AcceptsWithdrawal returns true if amount is within the withdrawal limits
+// of the network.
+func (n AssetNetwork) AcceptsWithdrawal(amount decimal.Decimal) (bool, error) {
+
+	min, err := n.MinWithdrawalAmountNum()
+	if err != nil {
+		return false, err
+	}
+
+	max, err := n.MaxWithdrawalAmountNum()
+	if err != nil {
+		return false, err
+	}
+
+	if amount.LessThan(min) {
+		return false, nil
+	}
+
+	if len(n.MaxWithdrawalAmount) > 0 && amount.GreaterThan(max) {
+		return false, nil
+	}
+
+	return true, nil
+}
+
+// optionalNum converts an optional amount, returning zero if it is empty.
+func optionalNum(v, name, network string) (amount decimal.Decimal, err error) {
+	if len(v) == 0 {
+		return decimal.Zero, nil
+	}
+	amount, err = core.StrToNum(v)
+	if err != nil {
+		err = fmt.Errorf("invalid %s: %s - network: %s - msg: %v", name, v, network, err)
+	}
+	return
 }
 
 type CryptoDepositInstructions struct {
-	Id                string `json:"id"`
-	Name              string `json:"name"`
-	Type              string `json:"type"`
-	Address           string `json:"address"`
-	AccountIdentifier string `json:"account_identifier"`
+	Id                string   `json:"id"`
+	Name              string   `json:"name"`
+	Type              string   `json:"type"`
+	Address           string   `json:"address"`
+	AccountIdentifier string   `json:"account_identifier"`
+	Network           *Network `json:"network"`
+}
+
+type Network struct {
+	// The network id, e.g., ethereum-mainnet or base-mainnet
+	Id   string `json:"id"`
+	Type string `json:"type"`
+}
+
+// DepositAddress is one of the deposit addresses of a crypto wallet, e.g.,
+// a per-client address on an omnibus wallet.
+type DepositAddress struct {
+	Id      string   `json:"id"`
+	Address string   `json:"address"`
+	Network *Network `json:"network"`
+
+	// The memo or destination tag, required by some networks to credit the
+	// deposit
+	AccountIdentifier string    `json:"account_identifier"`
+	Created           time.Time `json:"created_at"`
 }
 
 type FiatDepositInstructions struct {
@@ -473,3 +942,495 @@ type PostTradeCredit struct {
 	AdjustedCreditUtilized string                      `json:"adjusted_credit_utilized"`
 	AdjustedEquity         string                      `json:"adjusted_portfolio_equity"`
 }
+
+const (
+	MarginCallStatusOpen    = "OPEN"
+	MarginCallStatusSettled = "SETTLED"
+	MarginCallStatusOverdue = "OVERDUE"
+
+	LocateStatusPending  = "PENDING"
+	LocateStatusApproved = "APPROVED"
+	LocateStatusRejected = "REJECTED"
+	LocateStatusExpired  = "EXPIRED"
+)
+
+// MarginSummary is the portfolio margin position of an entity. Amounts are in
+// USD.
+type MarginSummary struct {
+	EntityId          string `json:"entity_id"`
+	MarginEquity      string `json:"margin_equity"`
+	MarginRequirement string `json:"margin_requirement"`
+
+	// Margin equity less the margin requirement; negative in a deficit
+	ExcessDeficit string `json:"excess_deficit"`
+
+	LongMarketValue  string `json:"long_market_value"`
+	ShortMarketValue string `json:"short_market_value"`
+	GrossMarketValue string `json:"gross_market_value"`
+	NetMarketValue   string `json:"net_market_value"`
+
+	TotalLoanValue string        `json:"total_loan_value"`
+	MarginCalls    []*MarginCall `json:"margin_calls"`
+	Updated        time.Time     `json:"updated_at"`
+}
+
+func (m MarginSummary) MarginEquityNum() (amount decimal.Decimal, err error) {
+	amount, err = core.StrToNum(m.MarginEquity)
+	if err != nil {
+		err = fmt.Errorf("Invalid margin equity: %s - entity: %s - msg: %v", m.MarginEquity, m.EntityId, err)
+	}
+	return
+}
+
+func (m MarginSummary) MarginRequirementNum() (amount decimal.Decimal, err error) {
+	amount, err = core.StrToNum(m.MarginRequirement)
+	if err != nil {
+		err = fmt.Errorf("Invalid margin requirement: %s - entity: %s - msg: %v", m.MarginRequirement, m.EntityId, err)
+	}
+	return
+}
+
+func (m MarginSummary) ExcessDeficitNum() (amount decimal.Decimal, err error) {
+	amount, err = core.StrToNum(m.ExcessDeficit)
+	if err != nil {
+		err = fmt.Errorf("Invalid excess deficit: %s - entity: %s - msg: %v", m.ExcessDeficit, m.EntityId, err)
+	}
+	return
+}
+
+func (m MarginSummary) LongMarketValueNum() (amount decimal.Decimal, err error) {
+	amount, err = core.StrToNum(m.LongMarketValue)
+	if err != nil {
+		err = fmt.Errorf("Invalid long market value: %s - entity: %s - msg: %v", m.LongMarketValue, m.EntityId, err)
+	}
+	return
+}
+
+func (m MarginSummary) ShortMarketValueNum() (amount decimal.Decimal, err error) {
+	amount, err = core.StrToNum(m.ShortMarketValue)
+	if err != nil {
+		err = fmt.Errorf("Invalid short market value: %s - entity: %s - msg: %v", m.ShortMarketValue, m.EntityId, err)
+	}
+	return
+}
+
+func (m MarginSummary) GrossMarketValueNum() (amount decimal.Decimal, err error) {
+	amount, err = core.StrToNum(m.GrossMarketValue)
+	if err != nil {
+		err = fmt.Errorf("Invalid gross market value: %s - entity: %s - msg: %v", m.GrossMarketValue, m.EntityId, err)
+	}
+	return
+}
+
+func (m MarginSummary) NetMarketValueNum() (amount decimal.Decimal, err error) {
+	amount, err = core.StrToNum(m.NetMarketValue)
+	if err != nil {
+		err = fmt.Errorf("Invalid net market value: %s - entity: %s - msg: %v", m.NetMarketValue, m.EntityId, err)
+	}
+	return
+}
+
+func (m MarginSummary) TotalLoanValueNum() (amount decimal.Decimal, err error) {
+	amount, err = core.StrToNum(m.TotalLoanValue)
+	if err != nil {
+		err = fmt.Errorf("Invalid total loan value: %s - entity: %s - msg: %v", m.TotalLoanValue, m.EntityId, err)
+	}
+	return
+}
+
+// GrossLeverage returns the gross market value over the margin equity. It
+// is zero when there is no equity.
+func (m MarginSummary) GrossLeverage() (decimal.Decimal, error) {
+	return m.leverage(m.GrossMarketValueNum)
+}
+
+// NetLeverage returns the net market value over the margin equity. It is
+// zero when there is no equity.
+func (m MarginSummary) NetLeverage() (decimal.Decimal, error) {
+	return m.leverage(m.NetMarketValueNum)
+}
+
+func (m MarginSummary) leverage(marketValue func() (decimal.Decimal, error)) (decimal.Decimal, error) {
+	equity, err := m.MarginEquityNum()
+	if err != nil {
+		return decimal.Zero, err
+	}
+
+	if !equity.IsPositive() {
+		return decimal.Zero, nil
+	}
+
+	value, err := marketValue()
+	if err != nil {
+		return decimal.Zero, err
+	}
+
+	return value.Div(equity), nil
+}
+
+// InDeficit returns true if the margin equity does not cover the margin
+// requirement.
+func (m MarginSummary) InDeficit() (bool, error) {
+	deficit, err := m.ExcessDeficitNum()
+	if err != nil {
+		return false, err
+	}
+	return deficit.IsNegative(), nil
+}
+
+type MarginCall struct {
+	Id              string    `json:"margin_call_id"`
+	Currency        string    `json:"currency"`
+	InitialAmount   string    `json:"initial_amount"`
+	RemainingAmount string    `json:"remaining_amount"`
+	Status          string    `json:"status"`
+	Created         time.Time `json:"created_at"`
+	Due             time.Time `json:"due_at"`
+}
+
+func (c MarginCall) InitialAmountNum() (amount decimal.Decimal, err error) {
+	amount, err = core.StrToNum(c.InitialAmount)
+	if err != nil {
+		err = fmt.Errorf("Invalid initial amount: %s - margin call: %s - msg: %v", c.InitialAmount, c.Id, err)
+	}
+	return
+}
+
+func (c MarginCall) RemainingAmountNum() (amount decimal.Decimal, err error) {
+	amount, err = core.StrToNum(c.RemainingAmount)
+	if err != nil {
+		err = fmt.Errorf("Invalid remaining amount: %s - margin call: %s - msg: %v", c.RemainingAmount, c.Id, err)
+	}
+	return
+}
+
+type BuyingPower struct {
+	PortfolioId      string `json:"portfolio_id"`
+	BaseCurrency     string `json:"base_currency"`
+	QuoteCurrency    string `json:"quote_currency"`
+	BaseBuyingPower  string `json:"base_buying_power"`
+	QuoteBuyingPower string `json:"quote_buying_power"`
+}
+
+func (b BuyingPower) BaseBuyingPowerNum() (amount decimal.Decimal, err error) {
+	amount, err = core.StrToNum(b.BaseBuyingPower)
+	if err != nil {
+		err = fmt.Errorf("Invalid base buying power: %s - portfolio: %s - msg: %v", b.BaseBuyingPower, b.PortfolioId, err)
+	}
+	return
+}
+
+func (b BuyingPower) QuoteBuyingPowerNum() (amount decimal.Decimal, err error) {
+	amount, err = core.StrToNum(b.QuoteBuyingPower)
+	if err != nil {
+		err = fmt.Errorf("Invalid quote buying power: %s - portfolio: %s - msg: %v", b.QuoteBuyingPower, b.PortfolioId, err)
+	}
+	return
+}
+
+type WithdrawalPower struct {
+	Symbol string `json:"symbol"`
+	Amount string `json:"amount"`
+}
+
+func (w WithdrawalPower) AmountNum() (amount decimal.Decimal, err error) {
+	amount, err = core.StrToNum(w.Amount)
+	if err != nil {
+		err = fmt.Errorf("Invalid withdrawal power: %s - symbol: %s - msg: %v", w.Amount, w.Symbol, err)
+	}
+	return
+}
+
+// Locate is a request to borrow an asset before selling it short.
+type Locate struct {
+	Id              string    `json:"locate_id"`
+	EntityId        string    `json:"entity_id"`
+	PortfolioId     string    `json:"portfolio_id"`
+	Symbol          string    `json:"symbol"`
+	RequestedAmount string    `json:"requested_amount"`
+	ApprovedAmount  string    `json:"approved_amount"`
+	InterestRate    string    `json:"interest_rate"`
+	Status          string    `json:"status"`
+	LocateDate      string    `json:"locate_date"`
+	Created         time.Time `json:"created_at"`
+}
+
+func (l Locate) RequestedAmountNum() (amount decimal.Decimal, err error) {
+	amount, err = core.StrToNum(l.RequestedAmount)
+	if err != nil {
+		err = fmt.Errorf("Invalid requested amount: %s - locate: %s - msg: %v", l.RequestedAmount, l.Id, err)
+	}
+	return
+}
+
+func (l Locate) ApprovedAmountNum() (amount decimal.Decimal, err error) {
+	amount, err = core.StrToNum(l.ApprovedAmount)
+	if err != nil {
+		err = fmt.Errorf("Invalid approved amount: %s - locate: %s - msg: %v", l.ApprovedAmount, l.Id, err)
+	}
+	return
+}
+
+func (l Locate) InterestRateNum() (amount decimal.Decimal, err error) {
+	amount, err = core.StrToNum(l.InterestRate)
+	if err != nil {
+		err = fmt.Errorf("Invalid interest rate: %s - locate: %s - msg: %v", l.InterestRate, l.Id, err)
+	}
+	return
+}
+
+// TradeFinanceTier is a tier of the trade finance fee schedule. The rate
+// applies to the borrowed notional between the bounds.
+type TradeFinanceTier struct {
+	Symbol     string `json:"symbol"`
+	LowerBound string `json:"lower_bound"`
+	UpperBound string `json:"upper_bound"`
+	FeeRate    string `json:"fee_rate"`
+}
+
+func (t TradeFinanceTier) LowerBoundNum() (amount decimal.Decimal, err error) {
+	amount, err = core.StrToNum(t.LowerBound)
+	if err != nil {
+		err = fmt.Errorf("Invalid lower bound: %s - symbol: %s - msg: %v", t.LowerBound, t.Symbol, err)
+	}
+	return
+}
+
+func (t TradeFinanceTier) UpperBoundNum() (amount decimal.Decimal, err error) {
+	amount, err = core.StrToNum(t.UpperBound)
+	if err != nil {
+		err = fmt.Errorf("Invalid upper bound: %s - symbol: %s - msg: %v", t.UpperBound, t.Symbol, err)
+	}
+	return
+}
+
+func (t TradeFinanceTier) FeeRateNum() (amount decimal.Decimal, err error) {
+	amount, err = core.StrToNum(t.FeeRate)
+	if err != nil {
+		err = fmt.Errorf("Invalid fee rate: %s - symbol: %s - msg: %v", t.FeeRate, t.Symbol, err)
+	}
+	return
+}
+
+type InterestAccrual struct {
+	Id             string `json:"accrual_id"`
+	Date           string `json:"date"`
+	PortfolioId    string `json:"portfolio_id"`
+	Symbol         string `json:"symbol"`
+	Type           string `json:"type"`
+	InterestRate   string `json:"rate"`
+	NotionalAmount string `json:"loan_amount"`
+	Amount         string `json:"accrual_amount"`
+	UsdAmount      string `json:"accrual_amount_usd"`
+}
+
+func (a InterestAccrual) InterestRateNum() (amount decimal.Decimal, err error) {
+	amount, err = core.StrToNum(a.InterestRate)
+	if err != nil {
+		err = fmt.Errorf("Invalid interest rate: %s - accrual: %s - msg: %v", a.InterestRate, a.Id, err)
+	}
+	return
+}
+
+func (a InterestAccrual) NotionalAmountNum() (amount decimal.Decimal, err error) {
+	amount, err = core.StrToNum(a.NotionalAmount)
+	if err != nil {
+		err = fmt.Errorf("Invalid notional amount: %s - accrual: %s - msg: %v", a.NotionalAmount, a.Id, err)
+	}
+	return
+}
+
+func (a InterestAccrual) AmountNum() (amount decimal.Decimal, err error) {
+	amount, err = core.StrToNum(a.Amount)
+	if err != nil {
+		err = fmt.Errorf("Invalid accrual amount: %s - accrual: %s - msg: %v", a.Amount, a.Id, err)
+	}
+	return
+}
+
+func (a InterestAccrual) UsdAmountNum() (amount decimal.Decimal, err error) {
+	amount, err = core.StrToNum(a.UsdAmount)
+	if err != nil {
+		err = fmt.Errorf("Invalid accrual usd amount: %s - accrual: %s - msg: %v", a.UsdAmount, a.Id, err)
+	}
+	return
+}
+
+const (
+	PositionReferenceTypeEntity    = "ENTITY"
+	PositionReferenceTypePortfolio = "PORTFOLIO"
+)
+
+type PositionReference struct {
+	Id   string `json:"id"`
+	Type string `json:"type"`
+}
+
+// Position is the aggregate long and short quantity of an asset held by an
+// entity or portfolio, including financed and derivative positions. The long
+// quantity already includes the balance held. An empty quantity is read as
+// zero.
+type Position struct {
+	Symbol    string             `json:"symbol"`
+	Long      string             `json:"long"`
+	Short     string             `json:"short"`
+	Reference *PositionReference `json:"position_reference"`
+}
+
+func (p Position) LongNum() (amount decimal.Decimal, err error) {
+	if len(p.Long) == 0 {
+		return decimal.Zero, nil
+	}
+	amount, err = core.StrToNum(p.Long)
+	if err != nil {
+		err = fmt.Errorf("Invalid long position: %s - symbol: %s - msg: %v", p.Long, p.Symbol, err)
+	}
+	return
+}
+
+func (p Position) ShortNum() (amount decimal.Decimal, err error) {
+	if len(p.Short) == 0 {
+		return decimal.Zero, nil
+	}
+	amount, err = core.StrToNum(p.Short)
+	if err != nil {
+		err = fmt.Errorf("Invalid short position: %s - symbol: %s - msg: %v", p.Short, p.Symbol, err)
+	}
+	return
+}
+
+// Net returns the long quantity less the short quantity.
+func (p Position) Net() (decimal.Decimal, error) {
+	long, err := p.LongNum()
+	if err != nil {
+		return decimal.Zero, err
+	}
+
+	short, err := p.ShortNum()
+	if err != nil {
+		return decimal.Zero, err
+	}
+
+	return long.Sub(short), nil
+}
+
+const (
+	FcmPositionSideLong  = "LONG"
+	FcmPositionSideShort = "SHORT"
+
+	FcmSweepStatusPending    = "PENDING"
+	FcmSweepStatusProcessing = "PROCESSING"
+)
+
+// FcmBalance is the futures commission merchant (FCM) balance summary of an
+// entity. Amounts are in USD.
+type FcmBalance struct {
+	PortfolioId         string `json:"portfolio_id"`
+	CfmUsdBalance       string `json:"cfm_usd_balance"`
+	UnrealizedPnl       string `json:"unrealized_pnl"`
+	DailyRealizedPnl    string `json:"daily_realized_pnl"`
+	ExcessLiquidity     string `json:"excess_liquidity"`
+	FuturesBuyingPower  string `json:"futures_buying_power"`
+	InitialMargin       string `json:"initial_margin"`
+	MaintenanceMargin   string `json:"maintenance_margin"`
+	ClearingCallsDue    string `json:"clearing_calls_due"`
+	ClearingCallDueTime string `json:"clearing_call_due_time"`
+	DeficitDue          string `json:"deficit_due"`
+	DeficitDueTime      string `json:"deficit_due_time"`
+}
+
+func (b FcmBalance) CfmUsdBalanceNum() (amount decimal.Decimal, err error) {
+	amount, err = core.StrToNum(b.CfmUsdBalance)
+	if err != nil {
+		err = fmt.Errorf("invalid cfm usd balance: %s - portfolio: %s - err: %w", b.CfmUsdBalance, b.PortfolioId, err)
+	}
+	return
+}
+
+func (b FcmBalance) UnrealizedPnlNum() (amount decimal.Decimal, err error) {
+	amount, err = core.StrToNum(b.UnrealizedPnl)
+	if err != nil {
+		err = fmt.Errorf("invalid unrealized pnl: %s - portfolio: %s - err: %w", b.UnrealizedPnl, b.PortfolioId, err)
+	}
+	return
+}
+
+func (b FcmBalance) ExcessLiquidityNum() (amount decimal.Decimal, err error) {
+	amount, err = core.StrToNum(b.ExcessLiquidity)
+	if err != nil {
+		err = fmt.Errorf("invalid excess liquidity: %s - portfolio: %s - err: %w", b.ExcessLiquidity, b.PortfolioId, err)
+	}
+	return
+}
+
+func (b FcmBalance) FuturesBuyingPowerNum() (amount decimal.Decimal, err error) {
+	amount, err = core.StrToNum(b.FuturesBuyingPower)
+	if err != nil {
+		err = fmt.Errorf("invalid futures buying power: %s - portfolio: %s - err: %w", b.FuturesBuyingPower, b.PortfolioId, err)
+	}
+	return
+}
+
+type FcmPosition struct {
+	ProductId         string `json:"product_id"`
+	Side              string `json:"side"`
+	NumberOfContracts string `json:"number_of_contracts"`
+	DailyRealizedPnl  string `json:"daily_realized_pnl"`
+	UnrealizedPnl     string `json:"unrealized_pnl"`
+	CurrentPrice      string `json:"current_price"`
+	AvgEntryPrice     string `json:"avg_entry_price"`
+	ExpirationTime    string `json:"expiration_time"`
+}
+
+func (p FcmPosition) NumberOfContractsNum() (contracts decimal.Decimal, err error) {
+	contracts, err = core.StrToNum(p.NumberOfContracts)
+	if err != nil {
+		err = fmt.Errorf("invalid number of contracts: %s - product: %s - err: %w", p.NumberOfContracts, p.ProductId, err)
+	}
+	return
+}
+
+func (p FcmPosition) UnrealizedPnlNum() (amount decimal.Decimal, err error) {
+	amount, err = core.StrToNum(p.UnrealizedPnl)
+	if err != nil {
+		err = fmt.Errorf("invalid unrealized pnl: %s - product: %s - err: %w", p.UnrealizedPnl, p.ProductId, err)
+	}
+	return
+}
+
+type FcmSweepAmount struct {
+	Currency string `json:"currency"`
+	Amount   string `json:"amount"`
+}
+
+// FcmSweep is a transfer of USD between the futures (FCM) account and the
+// spot trading balance.
+type FcmSweep struct {
+	Id              string          `json:"id"`
+	RequestedAmount *FcmSweepAmount `json:"requested_amount"`
+	ShouldSweepAll  bool            `json:"should_sweep_all"`
+	Status          string          `json:"status"`
+	ScheduledTime   string          `json:"scheduled_time"`
+}
+
+type FcmRiskLimits struct {
+	CfmRiskLimit               string `json:"cfm_risk_limit"`
+	CfmRiskLimitUtilization    string `json:"cfm_risk_limit_utilization"`
+	CfmTotalOpenOrdersNotional string `json:"cfm_total_open_orders_notional"`
+}
+
+func (r FcmRiskLimits) CfmRiskLimitNum() (amount decimal.Decimal, err error) {
+	amount, err = core.StrToNum(r.CfmRiskLimit)
+	if err != nil {
+		err = fmt.Errorf("invalid cfm risk limit: %s - err: %w", r.CfmRiskLimit, err)
+	}
+	return
+}
+
+func (r FcmRiskLimits) CfmRiskLimitUtilizationNum() (amount decimal.Decimal, err error) {
+	amount, err = core.StrToNum(r.CfmRiskLimitUtilization)
+	if err != nil {
+		err = fmt.Errorf("invalid cfm risk limit utilization: %s - err: %w", r.CfmRiskLimitUtilization, err)
+	}
+	return
+}
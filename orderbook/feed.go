@@ -0,0 +1,33 @@
+/**
+ * Copyright 2024-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package orderbook
+
+import "context"
+
+// Feed maintains a Book per product from a market data transport. The
+// WebSocket and FIX feeds both implement it, so strategies can switch
+// transport without changing code.
+type Feed interface {
+	Subscribe(ctx context.Context, productIds ...string) error
+
+	// Book returns the book for a subscribed product. Check Synced before
+	// relying on the contents.
+	Book(productId string) (*Book, bool)
+
+	// Err returns the last error encountered while applying messages.
+	Err() error
+}
@@ -0,0 +1,270 @@
+/**
+ * Copyright 2024-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package orderbook
+
+import (
+	"errors"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+type Side int
+
+const (
+	Bid Side = iota
+	Ask
+)
+
+func (s Side) String() string {
+	if s == Bid {
+		return "bid"
+	}
+	return "ask"
+}
+
+// ErrInsufficientDepth is returned by Vwap when the book does not hold
+// enough quantity to fill the requested size.
+var ErrInsufficientDepth = errors.New("insufficient depth")
+
+// ErrNotSynced is returned by queries on a book that has not received a
+// snapshot since it was created or invalidated.
+var ErrNotSynced = errors.New("book not synced")
+
+type Level struct {
+	Price    decimal.Decimal
+	Quantity decimal.Decimal
+}
+
+// Update sets the quantity at a price level. A zero quantity removes the
+// level.
+type Update struct {
+	Side     Side
+	Price    decimal.Decimal
+	Quantity decimal.Decimal
+}
+
+// Book is an in-memory L2 order book for a single product. It is transport
+// agnostic; feeds apply a snapshot followed by incremental updates. Book is
+// safe for concurrent use.
+type Book struct {
+	productId string
+
+	// Bids are sorted by descending price, asks by ascending price
+	bids []Level
+	asks []Level
+
+	synced  bool
+	updated time.Time
+	mu      sync.RWMutex
+}
+
+func NewBook(productId string) *Book {
+	return &Book{productId: productId}
+}
+
+func (b *Book) ProductId() string {
+	return b.productId
+}
+
+// ApplySnapshot replaces the contents of the book and marks it as synced.
+func (b *Book) ApplySnapshot(updates []Update) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.bids = b.bids[:0]
+	b.asks = b.asks[:0]
+
+	for _, u := range updates {
+		b.set(u)
+	}
+
+	b.synced = true
+	b.updated = time.Now()
+}
+
+// Apply applies incremental updates. Updates received before the first
+// snapshot are ignored, as they cannot be applied to a known state.
+func (b *Book) Apply(updates []Update) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if !b.synced {
+		return
+	}
+
+	for _, u := range updates {
+		b.set(u)
+	}
+
+	b.updated = time.Now()
+}
+
+// Invalidate clears the book and marks it as not synced, e.g., after a
+// sequence gap. Queries return ErrNotSynced until the next snapshot.
+func (b *Book) Invalidate() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.bids = b.bids[:0]
+	b.asks = b.asks[:0]
+	b.synced = false
+}
+
+// Synced returns true if the book reflects a snapshot and all updates since.
+func (b *Book) Synced() bool {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return b.synced
+}
+
+// Updated returns the time the book was last changed.
+func (b *Book) Updated() time.Time {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return b.updated
+}
+
+func (b *Book) BestBid() (Level, error) {
+	return b.best(Bid)
+}
+
+func (b *Book) BestAsk() (Level, error) {
+	return b.best(Ask)
+}
+
+// Spread returns the best ask less the best bid.
+func (b *Book) Spread() (decimal.Decimal, error) {
+	bid, err := b.BestBid()
+	if err != nil {
+		return decimal.Zero, err
+	}
+
+	ask, err := b.BestAsk()
+	if err != nil {
+		return decimal.Zero, err
+	}
+
+	return ask.Price.Sub(bid.Price), nil
+}
+
+// Depth returns up to n levels from the top of a side of the book. A
+// non-positive n returns every level.
+func (b *Book) Depth(side Side, n int) ([]Level, error) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	if !b.synced {
+		return nil, ErrNotSynced
+	}
+
+	levels := b.levels(side)
+	if n <= 0 || n > len(levels) {
+		n = len(levels)
+	}
+
+	depth := make([]Level, n)
+	copy(depth, levels[:n])
+	return depth, nil
+}
+
+// Vwap returns the volume weighted average price to fill size by walking a
+// side of the book from the top, e.g., walk the asks to price a buy.
+func (b *Book) Vwap(side Side, size decimal.Decimal) (decimal.Decimal, error) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	if !b.synced {
+		return decimal.Zero, ErrNotSynced
+	}
+
+	if !size.IsPositive() {
+		return decimal.Zero, errors.New("size must be positive")
+	}
+
+	remaining := size
+	notional := decimal.Zero
+
+	for _, l := range b.levels(side) {
+		fill := decimal.Min(remaining, l.Quantity)
+		notional = notional.Add(fill.Mul(l.Price))
+		remaining = remaining.Sub(fill)
+		if remaining.IsZero() {
+			return notional.Div(size), nil
+		}
+	}
+
+	return decimal.Zero, ErrInsufficientDepth
+}
+
+func (b *Book) best(side Side) (Level, error) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	if !b.synced {
+		return Level{}, ErrNotSynced
+	}
+
+	levels := b.levels(side)
+	if len(levels) == 0 {
+		return Level{}, ErrInsufficientDepth
+	}
+
+	return levels[0], nil
+}
+
+func (b *Book) levels(side Side) []Level {
+	if side == Bid {
+		return b.bids
+	}
+	return b.asks
+}
+
+func (b *Book) set(u Update) {
+
+	levels := b.levels(u.Side)
+
+	idx := sort.Search(len(levels), func(i int) bool {
+		if u.Side == Bid {
+			return levels[i].Price.LessThanOrEqual(u.Price)
+		}
+		return levels[i].Price.GreaterThanOrEqual(u.Price)
+	})
+
+	exists := idx < len(levels) && levels[idx].Price.Equal(u.Price)
+
+	switch {
+	case !u.Quantity.IsPositive() && exists:
+		levels = append(levels[:idx], levels[idx+1:]...)
+	case !u.Quantity.IsPositive():
+		return
+	case exists:
+		levels[idx].Quantity = u.Quantity
+	default:
+		levels = append(levels, Level{})
+		copy(levels[idx+1:], levels[idx:])
+		levels[idx] = Level{Price: u.Price, Quantity: u.Quantity}
+	}
+
+	if u.Side == Bid {
+		b.bids = levels
+	} else {
+		b.asks = levels
+	}
+}
@@ -0,0 +1,148 @@
+/**
+ * Copyright 2024-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package orderbook
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/shopspring/decimal"
+)
+
+func update(side Side, price, qty string) Update {
+	return Update{Side: side, Price: decimal.RequireFromString(price), Quantity: decimal.RequireFromString(qty)}
+}
+
+func TestBook(t *testing.T) {
+
+	book := NewBook("BTC-USD")
+
+	book.Apply([]Update{update(Bid, "99", "1")})
+
+	if _, err := book.BestBid(); !errors.Is(err, ErrNotSynced) {
+		t.Fatalf("expected updates before a snapshot to be ignored - err: %v", err)
+	}
+
+	book.ApplySnapshot([]Update{
+		update(Bid, "99", "1"),
+		update(Bid, "100", "2"),
+		update(Ask, "102", "1"),
+		update(Ask, "101", "1"),
+	})
+
+	book.Apply([]Update{
+		update(Bid, "100", "0"),
+		update(Bid, "98", "3"),
+		update(Ask, "101", "0.5"),
+		update(Ask, "103", "0"),
+	})
+
+	bid, err := book.BestBid()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !bid.Price.Equal(decimal.NewFromInt(99)) {
+		t.Errorf("expected best bid: 99 - received: %v", bid.Price)
+	}
+
+	depth, err := book.Depth(Ask, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(depth) != 2 || !depth[0].Price.Equal(decimal.NewFromInt(101)) || !depth[0].Quantity.Equal(decimal.NewFromFloat(0.5)) {
+		t.Errorf("unexpected ask depth: %v", depth)
+	}
+
+	spread, err := book.Spread()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !spread.Equal(decimal.NewFromInt(2)) {
+		t.Errorf("expected spread: 2 - received: %v", spread)
+	}
+
+	book.Invalidate()
+
+	if book.Synced() {
+		t.Error("expected an invalidated book to not be synced")
+	}
+}
+
+func TestVwap(t *testing.T) {
+
+	book := NewBook("BTC-USD")
+	book.ApplySnapshot([]Update{
+		update(Ask, "100", "1"),
+		update(Ask, "101", "1"),
+		update(Ask, "103", "2"),
+		update(Bid, "99", "1"),
+	})
+
+	cases := []struct {
+		description string
+		side        Side
+		size        string
+		expected    string
+		err         error
+	}{
+		{
+			description: "TestVwap0",
+			side:        Ask,
+			size:        "0.5",
+			expected:    "100",
+		},
+		{
+			description: "TestVwap1",
+			side:        Ask,
+			size:        "2",
+			expected:    "100.5",
+		},
+		{
+			description: "TestVwap2",
+			side:        Ask,
+			size:        "4",
+			expected:    "101.75",
+		},
+		{
+			description: "TestVwap3",
+			side:        Ask,
+			size:        "5",
+			err:         ErrInsufficientDepth,
+		},
+		{
+			description: "TestVwap4",
+			side:        Bid,
+			size:        "1",
+			expected:    "99",
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.description, func(t *testing.T) {
+			result, err := book.Vwap(tt.side, decimal.RequireFromString(tt.size))
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("test: %s - expected err: %v - received: %v", tt.description, tt.err, err)
+			}
+			if tt.err == nil && !result.Equal(decimal.RequireFromString(tt.expected)) {
+				t.Errorf("test: %s - expected: %s - received: %v", tt.description, tt.expected, result)
+			}
+		})
+	}
+}
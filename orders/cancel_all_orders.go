@@ -0,0 +1,121 @@
+/**
+ * Copyright 2024-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package orders
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/coinbase-samples/prime-sdk-go/model"
+)
+
+type CancelAllOrdersRequest struct {
+	PortfolioId string `json:"portfolio_id"`
+
+	// Optional filters. An open order is cancelled only if it matches all of
+	// the filters that are set.
+	ProductIds          []string `json:"product_ids"`
+	Side                string   `json:"side"`
+	ClientOrderIdPrefix string   `json:"client_order_id_prefix"`
+
+	// The maximum number of cancel requests in flight. Defaults to 5. Requests
+	// are also subject to the client rate limiter, if set.
+	Concurrency int `json:"concurrency"`
+
+	// When true, the open orders are listed again after cancelling and any
+	// matching orders are returned in Remaining.
+	Verify bool `json:"verify"`
+}
+
+type CancelAllOrdersResponse struct {
+	Results   []*CancelOrderResult    `json:"results"`
+	Remaining []*model.Order          `json:"remaining"`
+	Request   *CancelAllOrdersRequest `json:"request"`
+}
+
+// Failed returns the results of orders that could not be cancelled.
+func (r CancelAllOrdersResponse) Failed() []*CancelOrderResult {
+	return failedCancelResults(r.Results)
+}
+
+// Flat returns true if every matching order was cancelled or already
+// terminal and, if verified, no matching open orders remain.
+func (r CancelAllOrdersResponse) Flat() bool {
+	return len(r.Failed()) == 0 && len(r.Remaining) == 0
+}
+
+// CancelAllOrders lists the open orders in the portfolio, cancels the ones
+// that match the request filters and returns a result for each. This is
+// intended as a kill switch, so a failure to cancel one order does not stop
+// the others. An error is only returned if the open orders cannot be listed.
+func (s *ordersServiceImpl) CancelAllOrders(
+	ctx context.Context,
+	request *CancelAllOrdersRequest,
+) (*CancelAllOrdersResponse, error) {
+
+	if len(request.PortfolioId) == 0 {
+		return nil, errors.New("portfolio id not set on request")
+	}
+
+	open, err := s.listMatchingOpenOrders(ctx, request)
+	if err != nil {
+		return nil, fmt.Errorf("unable to list open orders: %w", err)
+	}
+
+	response := &CancelAllOrdersResponse{
+		Results: s.cancelOrders(ctx, request.PortfolioId, open, request.Concurrency),
+		Request: request,
+	}
+
+	if request.Verify {
+		if response.Remaining, err = s.listMatchingOpenOrders(ctx, request); err != nil {
+			return response, fmt.Errorf("unable to verify open orders: %w", err)
+		}
+	}
+
+	return response, nil
+}
+
+func (s *ordersServiceImpl) listMatchingOpenOrders(
+	ctx context.Context,
+	request *CancelAllOrdersRequest,
+) ([]*model.Order, error) {
+
+	response, err := s.ListAllOpenOrders(ctx, &ListAllOpenOrdersRequest{
+		PortfolioId: request.PortfolioId,
+		ProductIds:  request.ProductIds,
+		Side:        request.Side,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var matched []*model.Order
+	for _, o := range response.Orders {
+		if request.matches(o) {
+			matched = append(matched, o)
+		}
+	}
+
+	return matched, nil
+}
+
+func (r CancelAllOrdersRequest) matches(o *model.Order) bool {
+	return len(r.ClientOrderIdPrefix) == 0 || strings.HasPrefix(o.ClientOrderId, r.ClientOrderIdPrefix)
+}
@@ -0,0 +1,159 @@
+/**
+ * Copyright 2024-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package orders
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"github.com/coinbase-samples/prime-sdk-go/model"
+)
+
+const (
+	CancelResultCancelled       = "CANCELLED"
+	CancelResultAlreadyTerminal = "ALREADY_TERMINAL"
+	CancelResultFailed          = "FAILED"
+
+	defaultCancelConcurrency = 5
+)
+
+type CancelOrdersRequest struct {
+	PortfolioId string   `json:"portfolio_id"`
+	OrderIds    []string `json:"order_ids"`
+
+	// The maximum number of cancel requests in flight. Defaults to 5. Requests
+	// are also subject to the client rate limiter, if set.
+	Concurrency int `json:"concurrency"`
+}
+
+type CancelOrdersResponse struct {
+	Results []*CancelOrderResult `json:"results"`
+	Request *CancelOrdersRequest `json:"request"`
+}
+
+// CancelOrderResult reports the outcome of a single cancel. Status is one of
+// CancelResultCancelled, CancelResultAlreadyTerminal or CancelResultFailed.
+// Order is set to the latest known state of the order, if available.
+type CancelOrderResult struct {
+	OrderId string       `json:"order_id"`
+	Status  string       `json:"status"`
+	Order   *model.Order `json:"order"`
+	Err     error        `json:"-"`
+}
+
+// CancelOrders cancels the orders concurrently and returns a result for each
+// order id, in request order. An order that fails to cancel does not stop
+// the others; check each result or call Failed on the response.
+func (s *ordersServiceImpl) CancelOrders(
+	ctx context.Context,
+	request *CancelOrdersRequest,
+) (*CancelOrdersResponse, error) {
+
+	if len(request.PortfolioId) == 0 {
+		return nil, errors.New("portfolio id not set on request")
+	}
+
+	orders := make([]*model.Order, len(request.OrderIds))
+	for i, id := range request.OrderIds {
+		orders[i] = &model.Order{Id: id, PortfolioId: request.PortfolioId}
+	}
+
+	results := s.cancelOrders(ctx, request.PortfolioId, orders, request.Concurrency)
+
+	return &CancelOrdersResponse{Results: results, Request: request}, nil
+}
+
+// Failed returns the results of orders that could not be cancelled.
+func (r CancelOrdersResponse) Failed() []*CancelOrderResult {
+	return failedCancelResults(r.Results)
+}
+
+func (s *ordersServiceImpl) cancelOrders(
+	ctx context.Context,
+	portfolioId string,
+	orders []*model.Order,
+	concurrency int,
+) []*CancelOrderResult {
+
+	if concurrency <= 0 {
+		concurrency = defaultCancelConcurrency
+	}
+
+	results := make([]*CancelOrderResult, len(orders))
+
+	sem := make(chan struct{}, concurrency)
+
+	var wg sync.WaitGroup
+
+	for i, o := range orders {
+
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			results[i] = &CancelOrderResult{OrderId: o.Id, Status: CancelResultFailed, Order: o, Err: ctx.Err()}
+			continue
+		}
+
+		wg.Add(1)
+		go func(i int, o *model.Order) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			results[i] = s.cancelOrder(ctx, portfolioId, o)
+		}(i, o)
+	}
+
+	wg.Wait()
+
+	return results
+}
+
+// cancelOrder cancels a single order. When the cancel fails, the order is
+// looked up to distinguish an order that already reached a terminal state
+// from one that is still working.
+func (s *ordersServiceImpl) cancelOrder(ctx context.Context, portfolioId string, o *model.Order) *CancelOrderResult {
+
+	result := &CancelOrderResult{OrderId: o.Id, Order: o}
+
+	_, err := s.CancelOrder(ctx, &CancelOrderRequest{PortfolioId: portfolioId, OrderId: o.Id})
+	if err == nil {
+		result.Status = CancelResultCancelled
+		return result
+	}
+
+	response, getErr := s.GetOrder(ctx, &GetOrderRequest{PortfolioId: portfolioId, OrderId: o.Id})
+	if getErr == nil && response.Order != nil {
+		result.Order = response.Order
+		if response.Order.IsTerminal() {
+			result.Status = CancelResultAlreadyTerminal
+			return result
+		}
+	}
+
+	result.Status = CancelResultFailed
+	result.Err = err
+	return result
+}
+
+func failedCancelResults(results []*CancelOrderResult) (failed []*CancelOrderResult) {
+	for _, r := range results {
+		if r.Status == CancelResultFailed {
+			failed = append(failed, r)
+		}
+	}
+	return
+}
@@ -0,0 +1,276 @@
+/**
+ * Copyright 2024-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package orders
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/coinbase-samples/prime-sdk-go/client"
+	"github.com/coinbase-samples/prime-sdk-go/credentials"
+	"github.com/coinbase-samples/prime-sdk-go/model"
+)
+
+// fakeOrderBook serves the open orders, get order and cancel endpoints. The
+// cancel of "filled-*" orders fails because they filled first and the cancel
+// of "stuck-*" orders fails while they stay open.
+type fakeOrderBook struct {
+	orders   map[string]*model.Order
+	inFlight int32
+	peak     int32
+	mu       sync.Mutex
+}
+
+func newFakeOrderBook(orders ...*model.Order) *fakeOrderBook {
+	b := &fakeOrderBook{orders: make(map[string]*model.Order)}
+	for _, o := range orders {
+		o.Status = model.OrderStatusOpen
+		b.orders[o.Id] = o
+	}
+	return b
+}
+
+func (b *fakeOrderBook) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+
+	parts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+
+	switch {
+	case len(parts) == 3 && parts[2] == "products":
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"products": []*model.Product{{Id: "BTC-USD"}, {Id: "ETH-USD"}},
+		})
+	case len(parts) == 3 && parts[2] == "open_orders":
+		b.mu.Lock()
+		defer b.mu.Unlock()
+
+		var open []*model.Order
+		for _, o := range b.orders {
+			if o.Status == model.OrderStatusOpen {
+				open = append(open, o)
+			}
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"orders": open})
+	case len(parts) == 5 && parts[4] == "cancel":
+		n := atomic.AddInt32(&b.inFlight, 1)
+		defer atomic.AddInt32(&b.inFlight, -1)
+
+		for {
+			peak := atomic.LoadInt32(&b.peak)
+			if n <= peak || atomic.CompareAndSwapInt32(&b.peak, peak, n) {
+				break
+			}
+		}
+
+		time.Sleep(20 * time.Millisecond)
+
+		b.mu.Lock()
+		defer b.mu.Unlock()
+
+		o, ok := b.orders[parts[3]]
+		switch {
+		case !ok:
+			w.WriteHeader(http.StatusNotFound)
+		case strings.HasPrefix(o.Id, "filled-"):
+			o.Status = model.OrderStatusFilled
+			w.WriteHeader(http.StatusBadRequest)
+		case strings.HasPrefix(o.Id, "stuck-"):
+			w.WriteHeader(http.StatusInternalServerError)
+		default:
+			o.Status = model.OrderStatusCancelled
+			w.Write([]byte(`{}`))
+		}
+	case len(parts) == 4 && parts[2] == "orders":
+		b.mu.Lock()
+		defer b.mu.Unlock()
+
+		o, ok := b.orders[parts[3]]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"order": o})
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func TestCancelOrders(t *testing.T) {
+
+	cases := []struct {
+		description string
+		orderIds    []string
+		concurrency int
+		expected    []string
+	}{
+		{
+			description: "TestCancelOrders0",
+			orderIds:    []string{"o-0", "o-1", "o-2", "o-3", "o-4", "o-5", "o-6", "o-7"},
+			concurrency: 3,
+			expected: []string{
+				CancelResultCancelled, CancelResultCancelled, CancelResultCancelled, CancelResultCancelled,
+				CancelResultCancelled, CancelResultCancelled, CancelResultCancelled, CancelResultCancelled,
+			},
+		},
+		{
+			description: "TestCancelOrders1",
+			orderIds:    []string{"o-0", "filled-0", "stuck-0", "missing-0", "o-1"},
+			expected: []string{
+				CancelResultCancelled, CancelResultAlreadyTerminal, CancelResultFailed, CancelResultFailed, CancelResultCancelled,
+			},
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.description, func(t *testing.T) {
+
+			var orders []*model.Order
+			for _, id := range tt.orderIds {
+				if !strings.HasPrefix(id, "missing-") {
+					orders = append(orders, &model.Order{Id: id, ProductId: "BTC-USD"})
+				}
+			}
+
+			book := newFakeOrderBook(orders...)
+
+			server := httptest.NewServer(book)
+			defer server.Close()
+
+			svc := NewOrdersService(client.NewRestClient(&credentials.Credentials{}, http.Client{}).SetBaseUrl(server.URL))
+
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+
+			response, err := svc.CancelOrders(ctx, &CancelOrdersRequest{
+				PortfolioId: "p1",
+				OrderIds:    tt.orderIds,
+				Concurrency: tt.concurrency,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var failed int
+			for i, r := range response.Results {
+				if r.OrderId != tt.orderIds[i] || r.Status != tt.expected[i] {
+					t.Errorf("test: %s - expected: %s %s - received: %s %s", tt.description, tt.orderIds[i], tt.expected[i], r.OrderId, r.Status)
+				}
+				if r.Status == CancelResultFailed {
+					failed++
+					if r.Err == nil {
+						t.Errorf("test: %s - expected an error for: %s", tt.description, r.OrderId)
+					}
+				}
+			}
+
+			if len(response.Failed()) != failed {
+				t.Errorf("test: %s - expected: %d failed - received: %d", tt.description, failed, len(response.Failed()))
+			}
+
+			concurrency := tt.concurrency
+			if concurrency == 0 {
+				concurrency = defaultCancelConcurrency
+			}
+
+			if peak := int(atomic.LoadInt32(&book.peak)); peak > concurrency || peak < 2 {
+				t.Errorf("test: %s - expected: 2 to %d cancels in flight - received: %d", tt.description, concurrency, peak)
+			}
+		})
+	}
+}
+
+func TestCancelAllOrders(t *testing.T) {
+
+	cases := []struct {
+		description string
+		request     *CancelAllOrdersRequest
+		cancelled   int
+		failed      int
+		remaining   int
+		flat        bool
+	}{
+		{
+			description: "TestCancelAllOrders0",
+			request:     &CancelAllOrdersRequest{PortfolioId: "p1", Verify: true},
+			cancelled:   5,
+			failed:      1,
+			remaining:   1,
+		},
+		{
+			description: "TestCancelAllOrders1",
+			request:     &CancelAllOrdersRequest{PortfolioId: "p1", ClientOrderIdPrefix: "mm-", Verify: true},
+			cancelled:   3,
+			flat:        true,
+		},
+		{
+			description: "TestCancelAllOrders2",
+			request:     &CancelAllOrdersRequest{PortfolioId: "p1", ClientOrderIdPrefix: "hedge-"},
+			failed:      1,
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.description, func(t *testing.T) {
+
+			var orders []*model.Order
+			for i := 0; i < 3; i++ {
+				orders = append(orders, &model.Order{Id: fmt.Sprintf("o-%d", i), ProductId: "BTC-USD", ClientOrderId: fmt.Sprintf("mm-%d", i)})
+			}
+			orders = append(orders,
+				&model.Order{Id: "o-3", ProductId: "ETH-USD", ClientOrderId: "other-0"},
+				&model.Order{Id: "filled-0", ProductId: "BTC-USD", ClientOrderId: "other-1"},
+				&model.Order{Id: "stuck-0", ProductId: "ETH-USD", ClientOrderId: "hedge-0"},
+			)
+
+			server := httptest.NewServer(newFakeOrderBook(orders...))
+			defer server.Close()
+
+			svc := NewOrdersService(client.NewRestClient(&credentials.Credentials{}, http.Client{}).SetBaseUrl(server.URL))
+
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+
+			response, err := svc.CancelAllOrders(ctx, tt.request)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if len(response.Results) != tt.cancelled+tt.failed {
+				t.Errorf("test: %s - expected: %d results - received: %d", tt.description, tt.cancelled+tt.failed, len(response.Results))
+			}
+
+			if len(response.Failed()) != tt.failed {
+				t.Errorf("test: %s - expected: %d failed - received: %d", tt.description, tt.failed, len(response.Failed()))
+			}
+
+			if len(response.Remaining) != tt.remaining {
+				t.Errorf("test: %s - expected: %d remaining - received: %d", tt.description, tt.remaining, len(response.Remaining))
+			}
+
+			if response.Flat() != tt.flat {
+				t.Errorf("test: %s - expected: %v - received: %v", tt.description, tt.flat, response.Flat())
+			}
+		})
+	}
+}
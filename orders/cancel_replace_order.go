@@ -0,0 +1,260 @@
+/**
+ * Copyright 2024-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package orders
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/coinbase-samples/core-go"
+	"github.com/coinbase-samples/prime-sdk-go/model"
+	"github.com/shopspring/decimal"
+)
+
+const (
+	defaultCancelReplacePollInterval = 250 * time.Millisecond
+
+	// How long the original order is polled after a failed cancel, e.g.,
+	// because it filled between the lookup and the cancel
+	cancelFailureWait = 2 * time.Second
+
+	// Separates the original client order id from the replacement suffix
+	linkedClientOrderIdSep = ".r"
+)
+
+type CancelReplaceOrderRequest struct {
+	PortfolioId string `json:"portfolio_id"`
+	OrderId     string `json:"order_id"`
+
+	// The new terms. Empty values keep the terms of the original order.
+	LimitPrice   string `json:"limit_price"`
+	BaseQuantity string `json:"base_quantity"`
+
+	// The client order id of the replacement. If empty, it is linked to the
+	// client order id of the original order.
+	ClientOrderId string `json:"client_order_id"`
+
+	// By default, the replacement size is reduced by the quantity filled on
+	// the original order, so fills that race the cancel do not increase the
+	// total exposure. Set to true to submit the full size regardless.
+	IgnoreFills bool `json:"ignore_fills"`
+
+	// How often the original order is polled for cancel confirmation.
+	// Defaults to 250ms. Use a context deadline to bound the wait.
+	PollInterval time.Duration `json:"poll_interval"`
+}
+
+type CancelReplaceOrderResponse struct {
+	// The terminal state of the original order
+	CancelledOrder *model.Order `json:"cancelled_order"`
+
+	// The quantity filled on the original order before the cancel took effect
+	FilledQuantity string `json:"filled_quantity"`
+
+	// True if the original order was partially filled before the cancel took
+	// effect
+	PartiallyFilled bool `json:"partially_filled"`
+
+	// Set if a replacement order was submitted
+	NewOrderId string       `json:"new_order_id"`
+	NewOrder   *model.Order `json:"new_order"`
+
+	Request *CancelReplaceOrderRequest `json:"request"`
+}
+
+// Replaced returns true if a replacement order was submitted.
+func (r CancelReplaceOrderResponse) Replaced() bool {
+	return len(r.NewOrderId) > 0
+}
+
+var (
+	// ErrOrderFilled is returned by CancelReplaceOrder when the original order
+	// was completely filled before it could be cancelled. No replacement is
+	// submitted.
+	ErrOrderFilled = errors.New("order filled before cancel")
+
+	// ErrNothingToReplace is returned by CancelReplaceOrder when the quantity
+	// filled on the original order leaves no size for the replacement.
+	ErrNothingToReplace = errors.New("no remaining size to replace")
+)
+
+// CancelReplaceOrder cancels a working LIMIT order, waits until the cancel is
+// confirmed and submits a replacement with the new terms and a linked client
+// order id. The response is returned with ErrOrderFilled or
+// ErrNothingToReplace, so callers always see the terminal state of the
+// original order and any partial fill.
+func (s *ordersServiceImpl) CancelReplaceOrder(
+	ctx context.Context,
+	request *CancelReplaceOrderRequest,
+) (*CancelReplaceOrderResponse, error) {
+
+	original, err := s.GetOrder(ctx, &GetOrderRequest{PortfolioId: request.PortfolioId, OrderId: request.OrderId})
+	if err != nil {
+		return nil, fmt.Errorf("unable to get order: %s - err: %w", request.OrderId, err)
+	}
+
+	if original.Order == nil {
+		return nil, fmt.Errorf("order not found: %s", request.OrderId)
+	}
+
+	if original.Order.Type != model.OrderTypeLimit {
+		return nil, fmt.Errorf("only limit orders can be replaced - order: %s - type: %s", request.OrderId, original.Order.Type)
+	}
+
+	response := &CancelReplaceOrderResponse{Request: request}
+
+	if !original.Order.IsTerminal() {
+		if _, err := s.CancelOrder(ctx, &CancelOrderRequest{PortfolioId: request.PortfolioId, OrderId: request.OrderId}); err != nil {
+			// The cancel of an order that just filled is rejected, so check if
+			// the order reached a terminal state before reporting the failure
+			waitCtx, cancel := context.WithTimeout(ctx, cancelFailureWait)
+			response.CancelledOrder, _ = s.waitForTerminalOrder(waitCtx, request)
+			cancel()
+
+			if response.CancelledOrder == nil {
+				return nil, fmt.Errorf("unable to cancel order: %s - err: %w", request.OrderId, err)
+			}
+		}
+	}
+
+	if response.CancelledOrder == nil {
+		if response.CancelledOrder, err = s.waitForTerminalOrder(ctx, request); err != nil {
+			return response, err
+		}
+	}
+
+	filled, err := decimalOrZero(response.CancelledOrder.FilledQuantity)
+	if err != nil {
+		return response, fmt.Errorf("invalid filled quantity: %s - order: %s - err: %w", response.CancelledOrder.FilledQuantity, request.OrderId, err)
+	}
+
+	response.FilledQuantity = filled.String()
+
+	if response.CancelledOrder.Status == model.OrderStatusFilled {
+		return response, ErrOrderFilled
+	}
+
+	response.PartiallyFilled = filled.IsPositive()
+
+	size := request.BaseQuantity
+	if len(size) == 0 {
+		size = response.CancelledOrder.BaseQuantity
+	}
+
+	quantity, err := core.StrToNum(size)
+	if err != nil {
+		return response, fmt.Errorf("invalid base quantity: %s - err: %w", size, err)
+	}
+
+	if !request.IgnoreFills {
+		quantity = quantity.Sub(filled)
+	}
+
+	if !quantity.IsPositive() {
+		return response, ErrNothingToReplace
+	}
+
+	response.NewOrder = replacementOrder(response.CancelledOrder, request, quantity)
+
+	created, err := s.CreateOrder(ctx, &CreateOrderRequest{Order: response.NewOrder})
+	if err != nil {
+		return response, fmt.Errorf("unable to create replacement order - client order id: %s - err: %w", response.NewOrder.ClientOrderId, err)
+	}
+
+	response.NewOrderId = created.OrderId
+
+	return response, nil
+}
+
+func (s *ordersServiceImpl) waitForTerminalOrder(ctx context.Context, request *CancelReplaceOrderRequest) (*model.Order, error) {
+
+	interval := request.PollInterval
+	if interval <= 0 {
+		interval = defaultCancelReplacePollInterval
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		response, err := s.GetOrder(ctx, &GetOrderRequest{PortfolioId: request.PortfolioId, OrderId: request.OrderId})
+		if err == nil && response.Order != nil && response.Order.IsTerminal() {
+			return response.Order, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("cancel not confirmed - order: %s - err: %w", request.OrderId, ctx.Err())
+		case <-ticker.C:
+		}
+	}
+}
+
+func replacementOrder(original *model.Order, request *CancelReplaceOrderRequest, quantity decimal.Decimal) *model.Order {
+
+	limitPrice := request.LimitPrice
+	if len(limitPrice) == 0 {
+		limitPrice = original.LimitPrice
+	}
+
+	clientOrderId := request.ClientOrderId
+	if len(clientOrderId) == 0 {
+		clientOrderId = LinkedClientOrderId(original.ClientOrderId)
+	}
+
+	return &model.Order{
+		PortfolioId:      original.PortfolioId,
+		Side:             original.Side,
+		ClientOrderId:    clientOrderId,
+		ProductId:        original.ProductId,
+		Type:             model.OrderTypeLimit,
+		BaseQuantity:     quantity.String(),
+		LimitPrice:       limitPrice,
+		ExpiryTime:       original.ExpiryTime,
+		TimeInForce:      original.TimeInForce,
+		StpId:            original.StpId,
+		DisplayBaseSize:  original.DisplayBaseSize,
+		DisplayQuoteSize: original.DisplayQuoteSize,
+	}
+}
+
+// LinkedClientOrderId returns a new client order id that shares the root of
+// the original, e.g., "quote-42" becomes "quote-42.rlx8k2q1". Replacing a
+// replacement keeps the root, so a chain of orders can be traced back. An
+// empty client order id has no root, so the new id becomes the root, e.g.,
+// "rlx8k2q1".
+func LinkedClientOrderId(clientOrderId string) string {
+	suffix := strconv.FormatInt(time.Now().UnixNano(), 36)
+
+	root := strings.SplitN(clientOrderId, linkedClientOrderIdSep, 2)[0]
+	if len(root) == 0 {
+		return "r" + suffix
+	}
+
+	return root + linkedClientOrderIdSep + suffix
+}
+
+func decimalOrZero(v string) (decimal.Decimal, error) {
+	if len(v) == 0 {
+		return decimal.Zero, nil
+	}
+	return core.StrToNum(v)
+}
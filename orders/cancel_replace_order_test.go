@@ -0,0 +1,248 @@
+/**
+ * Copyright 2024-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package orders
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/coinbase-samples/prime-sdk-go/client"
+	"github.com/coinbase-samples/prime-sdk-go/credentials"
+	"github.com/coinbase-samples/prime-sdk-go/model"
+)
+
+func TestCancelReplaceOrder(t *testing.T) {
+
+	cases := []struct {
+		description string
+		cancelled   model.Order
+		ignoreFills bool
+		cancelFails bool
+		expectedErr error
+		expectedQty string
+		partial     bool
+	}{
+		{
+			description: "TestCancelReplaceOrder0",
+			cancelled:   model.Order{Status: model.OrderStatusCancelled},
+			expectedQty: "1",
+		},
+		{
+			description: "TestCancelReplaceOrder1",
+			cancelled:   model.Order{Status: model.OrderStatusCancelled, FilledQuantity: "0.4"},
+			expectedQty: "0.6",
+			partial:     true,
+		},
+		{
+			description: "TestCancelReplaceOrder2",
+			cancelled:   model.Order{Status: model.OrderStatusCancelled, FilledQuantity: "0.4"},
+			ignoreFills: true,
+			expectedQty: "1",
+			partial:     true,
+		},
+		{
+			description: "TestCancelReplaceOrder3",
+			cancelled:   model.Order{Status: model.OrderStatusFilled, FilledQuantity: "1"},
+			expectedErr: ErrOrderFilled,
+		},
+		{
+			description: "TestCancelReplaceOrder4",
+			cancelled:   model.Order{Status: model.OrderStatusFilled, FilledQuantity: "1"},
+			cancelFails: true,
+			expectedErr: ErrOrderFilled,
+		},
+		{
+			description: "TestCancelReplaceOrder5",
+			cancelled:   model.Order{Status: model.OrderStatusCancelled, FilledQuantity: "0.25"},
+			cancelFails: true,
+			expectedQty: "0.75",
+			partial:     true,
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.description, func(t *testing.T) {
+
+			original := model.Order{
+				Id:            "o1",
+				PortfolioId:   "p1",
+				ClientOrderId: "quote-42",
+				ProductId:     "BTC-USD",
+				Side:          model.OrderSideBuy,
+				Type:          model.OrderTypeLimit,
+				BaseQuantity:  "1",
+				LimitPrice:    "100",
+				Status:        model.OrderStatusOpen,
+			}
+
+			cancelled := original
+			cancelled.Status = tt.cancelled.Status
+			cancelled.FilledQuantity = tt.cancelled.FilledQuantity
+
+			var (
+				cancelSent bool
+				created    *model.Order
+			)
+
+			mux := http.NewServeMux()
+
+			mux.HandleFunc("/portfolios/p1/orders/o1", func(w http.ResponseWriter, r *http.Request) {
+				o := original
+				if cancelSent {
+					o = cancelled
+				}
+				json.NewEncoder(w).Encode(map[string]interface{}{"order": o})
+			})
+
+			mux.HandleFunc("/portfolios/p1/orders/o1/cancel", func(w http.ResponseWriter, r *http.Request) {
+				cancelSent = true
+				if tt.cancelFails {
+					w.WriteHeader(http.StatusBadRequest)
+					return
+				}
+				json.NewEncoder(w).Encode(map[string]interface{}{"id": "o1"})
+			})
+
+			mux.HandleFunc("/portfolios/p1/order", func(w http.ResponseWriter, r *http.Request) {
+				created = &model.Order{}
+				json.NewDecoder(r.Body).Decode(created)
+				json.NewEncoder(w).Encode(map[string]interface{}{"order_id": "o2"})
+			})
+
+			server := httptest.NewServer(mux)
+			defer server.Close()
+
+			svc := NewOrdersService(client.NewRestClient(&credentials.Credentials{}, http.Client{}).SetBaseUrl(server.URL))
+
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+
+			response, err := svc.CancelReplaceOrder(ctx, &CancelReplaceOrderRequest{
+				PortfolioId:  "p1",
+				OrderId:      "o1",
+				LimitPrice:   "101",
+				IgnoreFills:  tt.ignoreFills,
+				PollInterval: time.Millisecond,
+			})
+
+			if !errors.Is(err, tt.expectedErr) {
+				t.Fatalf("test: %s - expected err: %v - received: %v", tt.description, tt.expectedErr, err)
+			}
+
+			if tt.expectedErr == ErrOrderFilled && response.FilledQuantity != "1" {
+				t.Errorf("test: %s - expected filled quantity: 1 - received: %s", tt.description, response.FilledQuantity)
+			}
+
+			if response.PartiallyFilled != tt.partial {
+				t.Errorf("test: %s - expected partially filled: %v", tt.description, tt.partial)
+			}
+
+			if tt.expectedErr != nil {
+				if created != nil || response.Replaced() {
+					t.Errorf("test: %s - expected no replacement", tt.description)
+				}
+				return
+			}
+
+			if created == nil || response.NewOrderId != "o2" {
+				t.Fatalf("test: %s - expected a replacement order", tt.description)
+			}
+
+			if created.BaseQuantity != tt.expectedQty || created.LimitPrice != "101" {
+				t.Errorf("test: %s - unexpected replacement - qty: %s - price: %s", tt.description, created.BaseQuantity, created.LimitPrice)
+			}
+
+			if !strings.HasPrefix(created.ClientOrderId, "quote-42.r") {
+				t.Errorf("test: %s - expected a linked client order id - received: %s", tt.description, created.ClientOrderId)
+			}
+		})
+	}
+}
+
+func TestCancelReplaceOrderStillOpen(t *testing.T) {
+
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("/portfolios/p1/orders/o1", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]interface{}{"order": model.Order{
+			Id:     "o1",
+			Type:   model.OrderTypeLimit,
+			Status: model.OrderStatusOpen,
+		}})
+	})
+
+	mux.HandleFunc("/portfolios/p1/orders/o1/cancel", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	})
+
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	svc := NewOrdersService(client.NewRestClient(&credentials.Credentials{}, http.Client{}).SetBaseUrl(server.URL))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	response, err := svc.CancelReplaceOrder(ctx, &CancelReplaceOrderRequest{
+		PortfolioId:  "p1",
+		OrderId:      "o1",
+		PollInterval: 10 * time.Millisecond,
+	})
+
+	if err == nil || errors.Is(err, ErrOrderFilled) || response != nil {
+		t.Errorf("test: TestCancelReplaceOrderStillOpen - expected a cancel error - received: %v", err)
+	}
+}
+
+func TestLinkedClientOrderId(t *testing.T) {
+
+	cases := []struct {
+		description   string
+		clientOrderId string
+		prefix        string
+	}{
+		{
+			description:   "TestLinkedClientOrderId0",
+			clientOrderId: "quote-42",
+			prefix:        "quote-42.r",
+		},
+		{
+			description:   "TestLinkedClientOrderId1",
+			clientOrderId: "quote-42.rlx8k2q1",
+			prefix:        "quote-42.r",
+		},
+		{
+			description: "TestLinkedClientOrderId2",
+			prefix:      "r",
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.description, func(t *testing.T) {
+			id := LinkedClientOrderId(tt.clientOrderId)
+			if !strings.HasPrefix(id, tt.prefix) || strings.Count(id, ".") > 1 || strings.HasPrefix(id, ".") {
+				t.Errorf("test: %s - expected prefix: %s - received: %s", tt.description, tt.prefix, id)
+			}
+		})
+	}
+}
@@ -43,6 +43,10 @@ func (s *ordersServiceImpl) CreateOrder(ctx context.Context, request *CreateOrde
 
 	path := fmt.Sprintf("/portfolios/%s/order", request.Order.PortfolioId)
 
+	if err := client.RunRequestHooks(ctx, s.client, request); err != nil {
+		return nil, err
+	}
+
 	response := &CreateOrderResponse{Request: request}
 
 	if err := core.HttpPost(
@@ -55,8 +59,11 @@ func (s *ordersServiceImpl) CreateOrder(ctx context.Context, request *CreateOrde
 		response,
 		s.client.HeadersFunc(),
 	); err != nil {
+		client.RunResponseHooks(ctx, s.client, request, err)
 		return nil, err
 	}
 
+	client.RunResponseHooks(ctx, s.client, request, nil)
+
 	return response, nil
 }
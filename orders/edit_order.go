@@ -0,0 +1,79 @@
+/**
+ * Copyright 2024-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package orders
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/coinbase-samples/core-go"
+	"github.com/coinbase-samples/prime-sdk-go/client"
+)
+
+type EditOrderRequest struct {
+	PortfolioId string `json:"portfolio_id"`
+	OrderId     string `json:"order_id"`
+
+	// The client order id of the working order and the new client order id
+	// to assign to the edited order
+	OrigClientOrderId string `json:"orig_client_order_id"`
+	ClientOrderId     string `json:"client_order_id"`
+
+	BaseQuantity     string `json:"base_quantity,omitempty"`
+	QuoteValue       string `json:"quote_value,omitempty"`
+	LimitPrice       string `json:"limit_price,omitempty"`
+	ExpiryTime       string `json:"expiry_time,omitempty"`
+	DisplayBaseSize  string `json:"display_base_size,omitempty"`
+	DisplayQuoteSize string `json:"display_quote_size,omitempty"`
+}
+
+type EditOrderResponse struct {
+	OrderId string            `json:"order_id"`
+	Request *EditOrderRequest `json:"request"`
+}
+
+// EditOrder amends the price and/or size of a working LIMIT order in place.
+// Not every venue supports edits; use CancelReplaceOrder if the edit is
+// rejected.
+func (s *ordersServiceImpl) EditOrder(ctx context.Context, request *EditOrderRequest) (*EditOrderResponse, error) {
+
+	path := fmt.Sprintf("/portfolios/%s/orders/%s/edit", request.PortfolioId, request.OrderId)
+
+	if err := client.RunRequestHooks(ctx, s.client, request); err != nil {
+		return nil, err
+	}
+
+	response := &EditOrderResponse{Request: request}
+
+	if err := core.HttpPost(
+		ctx,
+		s.client,
+		path,
+		core.EmptyQueryParams,
+		client.DefaultSuccessHttpStatusCodes,
+		request,
+		response,
+		s.client.HeadersFunc(),
+	); err != nil {
+		client.RunResponseHooks(ctx, s.client, request, err)
+		return nil, err
+	}
+
+	client.RunResponseHooks(ctx, s.client, request, nil)
+
+	return response, nil
+}
@@ -0,0 +1,124 @@
+/**
+ * Copyright 2024-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package orders
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/coinbase-samples/prime-sdk-go/client"
+	"github.com/coinbase-samples/prime-sdk-go/credentials"
+)
+
+func TestEditOrder(t *testing.T) {
+
+	cases := []struct {
+		description string
+		request     *EditOrderRequest
+		status      int
+		expected    map[string]interface{}
+		expectedErr bool
+	}{
+		{
+			description: "TestEditOrder0",
+			request: &EditOrderRequest{
+				PortfolioId:       "p1",
+				OrderId:           "o1",
+				OrigClientOrderId: "quote-42",
+				ClientOrderId:     "quote-43",
+				LimitPrice:        "101",
+			},
+			status: http.StatusOK,
+			expected: map[string]interface{}{
+				"portfolio_id":         "p1",
+				"order_id":             "o1",
+				"orig_client_order_id": "quote-42",
+				"client_order_id":      "quote-43",
+				"limit_price":          "101",
+			},
+		},
+		{
+			description: "TestEditOrder1",
+			request: &EditOrderRequest{
+				PortfolioId:       "p1",
+				OrderId:           "o1",
+				OrigClientOrderId: "quote-42",
+				ClientOrderId:     "quote-43",
+				BaseQuantity:      "2",
+			},
+			status:      http.StatusBadRequest,
+			expectedErr: true,
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.description, func(t *testing.T) {
+
+			var body map[string]interface{}
+
+			mux := http.NewServeMux()
+
+			mux.HandleFunc("/portfolios/p1/orders/o1/edit", func(w http.ResponseWriter, r *http.Request) {
+				if r.Method != http.MethodPost {
+					t.Errorf("test: %s - expected: POST - received: %s", tt.description, r.Method)
+				}
+				json.NewDecoder(r.Body).Decode(&body)
+				w.WriteHeader(tt.status)
+				json.NewEncoder(w).Encode(map[string]interface{}{"order_id": "o1"})
+			})
+
+			server := httptest.NewServer(mux)
+			defer server.Close()
+
+			svc := NewOrdersService(client.NewRestClient(&credentials.Credentials{}, http.Client{}).SetBaseUrl(server.URL))
+
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+
+			response, err := svc.EditOrder(ctx, tt.request)
+
+			if tt.expectedErr {
+				if err == nil {
+					t.Errorf("test: %s - expected an error", tt.description)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if response.OrderId != "o1" {
+				t.Errorf("test: %s - expected: o1 - received: %s", tt.description, response.OrderId)
+			}
+
+			if len(body) != len(tt.expected) {
+				t.Errorf("test: %s - expected: %v - received: %v", tt.description, tt.expected, body)
+			}
+
+			for k, v := range tt.expected {
+				if body[k] != v {
+					t.Errorf("test: %s - expected: %s=%v - received: %v", tt.description, k, v, body[k])
+				}
+			}
+		})
+	}
+}
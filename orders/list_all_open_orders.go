@@ -0,0 +1,232 @@
+/**
+ * Copyright 2024-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package orders
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/coinbase-samples/prime-sdk-go/model"
+	"github.com/coinbase-samples/prime-sdk-go/products"
+)
+
+const (
+	// The maximum number of open orders returned by a single unpaginated call
+	openOrdersLimit = 1000
+
+	defaultOpenOrdersProductBatchSize = 20
+)
+
+// Used as the start of the time window when a request must be sharded by time
+// and no start time is set.
+var openOrdersEpoch = time.Date(2018, 1, 1, 0, 0, 0, 0, time.UTC)
+
+type ListAllOpenOrdersRequest struct {
+	PortfolioId string `json:"portfolio_id"`
+
+	// If empty, every product available to the portfolio is queried.
+	ProductIds []string  `json:"product_ids"`
+	Type       string    `json:"order_type"`
+	Side       string    `json:"order_side"`
+	Start      time.Time `json:"start_date"`
+	End        time.Time `json:"end_date"`
+
+	// The number of product ids sent per call. Defaults to 20.
+	ProductBatchSize int `json:"product_batch_size"`
+}
+
+type ListAllOpenOrdersResponse struct {
+	Orders  []*model.Order            `json:"orders"`
+	Request *ListAllOpenOrdersRequest `json:"request"`
+}
+
+// ListAllOpenOrders returns the complete set of open orders that match the
+// request. Product ids are queried in batches and each batch is paginated.
+// If the Prime endpoint returns a full, unpaginated page, the query is
+// split by product and then by time window until every shard fits in a
+// single page. Orders are de-duplicated by id.
+func (s *ordersServiceImpl) ListAllOpenOrders(
+	ctx context.Context,
+	request *ListAllOpenOrdersRequest,
+) (*ListAllOpenOrdersResponse, error) {
+
+	if len(request.PortfolioId) == 0 {
+		return nil, errors.New("portfolio id not set on request")
+	}
+
+	productIds := request.ProductIds
+	if len(productIds) == 0 {
+		var err error
+		if productIds, err = s.listPortfolioProductIds(ctx, request.PortfolioId); err != nil {
+			return nil, fmt.Errorf("unable to list products: %w", err)
+		}
+	}
+
+	batchSize := request.ProductBatchSize
+	if batchSize <= 0 {
+		batchSize = defaultOpenOrdersProductBatchSize
+	}
+
+	response := &ListAllOpenOrdersResponse{Request: request}
+
+	seen := make(map[string]bool)
+
+	for start := 0; start < len(productIds); start += batchSize {
+
+		end := start + batchSize
+		if end > len(productIds) {
+			end = len(productIds)
+		}
+
+		orders, err := s.listOpenOrdersShard(ctx, request, productIds[start:end], request.Start, request.End)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, o := range orders {
+			if !seen[o.Id] {
+				seen[o.Id] = true
+				response.Orders = append(response.Orders, o)
+			}
+		}
+	}
+
+	return response, nil
+}
+
+func (s *ordersServiceImpl) listOpenOrdersShard(
+	ctx context.Context,
+	request *ListAllOpenOrdersRequest,
+	productIds []string,
+	start,
+	end time.Time,
+) ([]*model.Order, error) {
+
+	orders, truncated, err := s.listOpenOrdersPages(ctx, request, productIds, start, end)
+	if err != nil || !truncated {
+		return orders, err
+	}
+
+	if len(productIds) > 1 {
+		var merged []*model.Order
+		for _, productId := range productIds {
+			o, err := s.listOpenOrdersShard(ctx, request, []string{productId}, start, end)
+			if err != nil {
+				return nil, err
+			}
+			merged = append(merged, o...)
+		}
+		return merged, nil
+	}
+
+	if start.IsZero() {
+		start = openOrdersEpoch
+	}
+
+	if end.IsZero() {
+		end = time.Now().UTC()
+	}
+
+	mid := start.Add(end.Sub(start) / 2).Truncate(time.Second)
+	if !mid.After(start) || !mid.Before(end) {
+		return nil, fmt.Errorf("unable to shard open orders below one second - product: %s - start: %v", productIds[0], start)
+	}
+
+	first, err := s.listOpenOrdersShard(ctx, request, productIds, start, mid)
+	if err != nil {
+		return nil, err
+	}
+
+	second, err := s.listOpenOrdersShard(ctx, request, productIds, mid, end)
+	if err != nil {
+		return nil, err
+	}
+
+	return append(first, second...), nil
+}
+
+// listOpenOrdersPages follows the pagination cursor for a single shard.
+// Truncated is true if a page reached the endpoint limit without a cursor
+// to the next page.
+func (s *ordersServiceImpl) listOpenOrdersPages(
+	ctx context.Context,
+	request *ListAllOpenOrdersRequest,
+	productIds []string,
+	start,
+	end time.Time,
+) (orders []*model.Order, truncated bool, err error) {
+
+	pagination := &model.PaginationParams{}
+
+	for {
+
+		var response *ListOpenOrdersResponse
+
+		response, err = s.ListOpenOrders(ctx, &ListOpenOrdersRequest{
+			PortfolioId: request.PortfolioId,
+			ProductIds:  productIds,
+			Type:        request.Type,
+			Side:        request.Side,
+			Start:       start,
+			End:         end,
+			Pagination:  pagination,
+		})
+		if err != nil {
+			return
+		}
+
+		orders = append(orders, response.Orders...)
+
+		if !response.HasNext() {
+			truncated = len(response.Orders) >= openOrdersLimit
+			return
+		}
+
+		pagination = &model.PaginationParams{Cursor: response.Pagination.NextCursor}
+	}
+}
+
+func (s *ordersServiceImpl) listPortfolioProductIds(ctx context.Context, portfolioId string) ([]string, error) {
+
+	svc := products.NewProductsService(s.client)
+
+	var ids []string
+
+	pagination := &model.PaginationParams{}
+
+	for {
+		response, err := svc.ListProducts(ctx, &products.ListProductsRequest{
+			PortfolioId: portfolioId,
+			Pagination:  pagination,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		for _, p := range response.Products {
+			ids = append(ids, p.Id)
+		}
+
+		if response.Pagination == nil || !response.Pagination.HasNext {
+			return ids, nil
+		}
+
+		pagination = &model.PaginationParams{Cursor: response.Pagination.NextCursor}
+	}
+}
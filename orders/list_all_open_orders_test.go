@@ -0,0 +1,122 @@
+/**
+ * Copyright 2024-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package orders
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/coinbase-samples/prime-sdk-go/client"
+	"github.com/coinbase-samples/prime-sdk-go/credentials"
+	"github.com/coinbase-samples/prime-sdk-go/model"
+)
+
+// The fake open orders endpoint holds 1500 BTC-USD orders, one per minute, and
+// a single ETH-USD order. Like Prime, it silently caps unpaginated responses
+// at 1k orders.
+func TestListAllOpenOrders(t *testing.T) {
+
+	base := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	var book []*model.Order
+	for idx := 0; idx < 1500; idx++ {
+		book = append(book, &model.Order{
+			Id:        fmt.Sprintf("btc-%d", idx),
+			ProductId: "BTC-USD",
+			Created:   base.Add(time.Duration(idx) * time.Minute).Format(time.RFC3339),
+		})
+	}
+
+	book = append(book, &model.Order{Id: "eth-0", ProductId: "ETH-USD", Created: base.Format(time.RFC3339)})
+
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("/portfolios/p1/products", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"products": []*model.Product{{Id: "BTC-USD"}, {Id: "ETH-USD"}},
+		})
+	})
+
+	mux.HandleFunc("/portfolios/p1/open_orders", func(w http.ResponseWriter, r *http.Request) {
+
+		q := r.URL.Query()
+
+		products := make(map[string]bool)
+		for _, p := range q["product_ids"] {
+			products[p] = true
+		}
+
+		start, _ := time.Parse(time.RFC3339, q.Get("start_date"))
+		end, _ := time.Parse(time.RFC3339, q.Get("end_date"))
+
+		var orders []*model.Order
+		for _, o := range book {
+			created, _ := time.Parse(time.RFC3339, o.Created)
+			if !products[o.ProductId] {
+				continue
+			}
+			if !start.IsZero() && created.Before(start) {
+				continue
+			}
+			if !end.IsZero() && !created.Before(end) {
+				continue
+			}
+			orders = append(orders, o)
+		}
+
+		if len(orders) > openOrdersLimit {
+			orders = orders[:openOrdersLimit]
+		}
+
+		json.NewEncoder(w).Encode(map[string]interface{}{"orders": orders})
+	})
+
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	c := client.NewRestClient(&credentials.Credentials{}, http.Client{}).SetBaseUrl(server.URL)
+
+	svc := NewOrdersService(c)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	response, err := svc.ListAllOpenOrders(ctx, &ListAllOpenOrdersRequest{
+		PortfolioId: "p1",
+		End:         base.Add(48 * time.Hour),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(response.Orders) != len(book) {
+		t.Fatalf("expected: %d orders - received: %d", len(book), len(response.Orders))
+	}
+
+	seen := make(map[string]bool)
+	for _, o := range response.Orders {
+		if seen[o.Id] {
+			t.Fatalf("duplicate order: %s", o.Id)
+		}
+		seen[o.Id] = true
+	}
+}
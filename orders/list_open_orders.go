@@ -19,26 +19,41 @@ package orders
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/coinbase-samples/core-go"
 	"github.com/coinbase-samples/prime-sdk-go/client"
 	"github.com/coinbase-samples/prime-sdk-go/model"
+	"github.com/coinbase-samples/prime-sdk-go/utils"
 )
 
 type ListOpenOrdersRequest struct {
 	PortfolioId string `json:"portfolio_id"`
-	ProductId   string `json:"product_id"`
+
+	// Deprecated: use ProductIds. If set, it is sent in addition to ProductIds.
+	ProductId string `json:"product_id"`
+
+	ProductIds []string                `json:"product_ids"`
+	Type       string                  `json:"order_type"`
+	Side       string                  `json:"order_side"`
+	Start      time.Time               `json:"start_date"`
+	End        time.Time               `json:"end_date"`
+	Pagination *model.PaginationParams `json:"pagination_params"`
 }
 
 type ListOpenOrdersResponse struct {
-	Orders  []*model.Order         `json:"orders"`
-	Request *ListOpenOrdersRequest `json:"request"`
+	Orders     []*model.Order         `json:"orders"`
+	Pagination *model.Pagination      `json:"pagination"`
+	Request    *ListOpenOrdersRequest `json:"request"`
+}
+
+func (r ListOpenOrdersResponse) HasNext() bool {
+	return r.Pagination != nil && r.Pagination.HasNext
 }
 
-// ListOpenOrders enables searching for open orders by product id.
-// This API endpoint is currently being adjusted by Coinbase.
-// This function will change once the Prime endpoint design is finalized.
-// This will not return more than 1k open orders and pagination is not supported.
+// ListOpenOrders enables searching for open orders by product ids, side, type
+// and order creation time. A single call returns at most 1k open orders. Use
+// ListAllOpenOrders to retrieve the complete open order book.
 // https://docs.cloud.coinbase.com/prime/reference/primerestapi_getopenorders
 func (s *ordersServiceImpl) ListOpenOrders(
 	ctx context.Context,
@@ -47,7 +62,32 @@ func (s *ordersServiceImpl) ListOpenOrders(
 
 	path := fmt.Sprintf("/portfolios/%s/open_orders", request.PortfolioId)
 
-	queryParams := core.AppendHttpQueryParam(core.EmptyQueryParams, "product_ids", request.ProductId)
+	var queryParams string
+	if len(request.ProductId) > 0 {
+		queryParams = core.AppendHttpQueryParam(queryParams, "product_ids", request.ProductId)
+	}
+
+	for _, p := range request.ProductIds {
+		queryParams = core.AppendHttpQueryParam(queryParams, "product_ids", p)
+	}
+
+	if len(request.Type) > 0 {
+		queryParams = core.AppendHttpQueryParam(queryParams, "order_type", request.Type)
+	}
+
+	if len(request.Side) > 0 {
+		queryParams = core.AppendHttpQueryParam(queryParams, "order_side", request.Side)
+	}
+
+	if !request.Start.IsZero() {
+		queryParams = core.AppendHttpQueryParam(queryParams, "start_date", utils.TimeToStr(request.Start))
+	}
+
+	if !request.End.IsZero() {
+		queryParams = core.AppendHttpQueryParam(queryParams, "end_date", utils.TimeToStr(request.End))
+	}
+
+	queryParams = utils.AppendPaginationParams(queryParams, request.Pagination)
 
 	response := &ListOpenOrdersResponse{Request: request}
 
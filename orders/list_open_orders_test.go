@@ -0,0 +1,81 @@
+/**
+ * Copyright 2024-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package orders
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/coinbase-samples/prime-sdk-go/client"
+	"github.com/coinbase-samples/prime-sdk-go/credentials"
+)
+
+func TestListOpenOrders(t *testing.T) {
+
+	cases := []struct {
+		description string
+		request     *ListOpenOrdersRequest
+		expected    []string
+	}{
+		{
+			description: "TestListOpenOrders0",
+			request:     &ListOpenOrdersRequest{PortfolioId: "p1", ProductIds: []string{"BTC-USD", "ETH-USD"}},
+			expected:    []string{"BTC-USD", "ETH-USD"},
+		},
+		{
+			description: "TestListOpenOrders1",
+			request:     &ListOpenOrdersRequest{PortfolioId: "p1", ProductId: "SOL-USD"},
+			expected:    []string{"SOL-USD"},
+		},
+		{
+			description: "TestListOpenOrders2",
+			request:     &ListOpenOrdersRequest{PortfolioId: "p1", ProductId: "SOL-USD", ProductIds: []string{"BTC-USD"}},
+			expected:    []string{"SOL-USD", "BTC-USD"},
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.description, func(t *testing.T) {
+
+			var received []string
+
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				received = r.URL.Query()["product_ids"]
+				json.NewEncoder(w).Encode(map[string]interface{}{"orders": []interface{}{}})
+			}))
+			defer server.Close()
+
+			svc := NewOrdersService(client.NewRestClient(&credentials.Credentials{}, http.Client{}).SetBaseUrl(server.URL))
+
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+
+			if _, err := svc.ListOpenOrders(ctx, tt.request); err != nil {
+				t.Fatal(err)
+			}
+
+			if !reflect.DeepEqual(received, tt.expected) {
+				t.Errorf("test: %s - expected: %v - received: %v", tt.description, tt.expected, received)
+			}
+		})
+	}
+}
@@ -44,6 +44,10 @@ type ListOrdersResponse struct {
 	Request    *ListOrdersRequest `json:"request"`
 }
 
+func (r ListOrdersResponse) HasNext() bool {
+	return r.Pagination != nil && r.Pagination.HasNext
+}
+
 // ListOrders returns orders based on query params. Start time is required.
 // This API endpoint cannot list open orders, so do not add an OPEN status
 // to the status param.
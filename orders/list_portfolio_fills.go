@@ -40,6 +40,10 @@ type ListPortfolioFillsResponse struct {
 	Request    *ListPortfolioFillsRequest `json:"request"`
 }
 
+func (r ListPortfolioFillsResponse) HasNext() bool {
+	return r.Pagination != nil && r.Pagination.HasNext
+}
+
 func (s *ordersServiceImpl) ListPortfolioFills(
 	ctx context.Context,
 	request *ListPortfolioFillsRequest,
@@ -24,11 +24,16 @@ import (
 
 type OrdersService interface {
 	ListOpenOrders(ctx context.Context, request *ListOpenOrdersRequest) (*ListOpenOrdersResponse, error)
+	ListAllOpenOrders(ctx context.Context, request *ListAllOpenOrdersRequest) (*ListAllOpenOrdersResponse, error)
 	CreateOrder(ctx context.Context, request *CreateOrderRequest) (*CreateOrderResponse, error)
 	CreateOrderPreview(ctx context.Context, request *CreateOrderRequest) (*CreateOrderPreviewResponse, error)
 	ListOrders(ctx context.Context, request *ListOrdersRequest) (*ListOrdersResponse, error)
 	GetOrder(ctx context.Context, request *GetOrderRequest) (*GetOrderResponse, error)
 	CancelOrder(ctx context.Context, request *CancelOrderRequest) (*CancelOrderResponse, error)
+	CancelOrders(ctx context.Context, request *CancelOrdersRequest) (*CancelOrdersResponse, error)
+	CancelAllOrders(ctx context.Context, request *CancelAllOrdersRequest) (*CancelAllOrdersResponse, error)
+	EditOrder(ctx context.Context, request *EditOrderRequest) (*EditOrderResponse, error)
+	CancelReplaceOrder(ctx context.Context, request *CancelReplaceOrderRequest) (*CancelReplaceOrderResponse, error)
 	ListOrderFills(ctx context.Context, request *ListOrderFillsRequest) (*ListOrderFillsResponse, error)
 	ListPortfolioFills(ctx context.Context, request *ListPortfolioFillsRequest) (*ListPortfolioFillsResponse, error)
 }
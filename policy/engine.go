@@ -0,0 +1,611 @@
+/**
+ * Copyright 2024-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package policy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/coinbase-samples/core-go"
+	"github.com/coinbase-samples/prime-sdk-go/addressbook"
+	"github.com/coinbase-samples/prime-sdk-go/addressvalidation"
+	"github.com/coinbase-samples/prime-sdk-go/client"
+	"github.com/coinbase-samples/prime-sdk-go/model"
+	"github.com/coinbase-samples/prime-sdk-go/orders"
+	"github.com/coinbase-samples/prime-sdk-go/quotes"
+	"github.com/coinbase-samples/prime-sdk-go/transactions"
+	"github.com/shopspring/decimal"
+)
+
+const (
+	RuleAllowedProducts    = "allowed_products"
+	RuleMaxOrderNotional   = "max_order_notional"
+	RuleMaxDailyWithdrawal = "max_daily_withdrawal"
+	RuleAddressBook        = "require_address_book_destination"
+	RulePriceBand          = "price_band"
+	RuleOnchainTransaction = "allow_onchain_transactions"
+
+	OperationCreateOrder              = "CreateOrder"
+	OperationEditOrder                = "EditOrder"
+	OperationCreateQuoteRequest       = "CreateQuoteRequest"
+	OperationAcceptQuote              = "AcceptQuote"
+	OperationCreateWalletWithdrawal   = "CreateWalletWithdrawal"
+	OperationCreateConversion         = "CreateConversion"
+	OperationCreateOnchainTransaction = "CreateOnchainTransaction"
+)
+
+// PolicyViolation is returned by the request hook when a request breaks a
+// rule. Use errors.As to inspect it.
+type PolicyViolation struct {
+	Rule      string `json:"rule"`
+	Operation string `json:"operation"`
+
+	// The product id of orders, edits, quotes and conversions, the symbol of
+	// withdrawals or the wallet id of onchain transactions
+	Subject string `json:"subject"`
+	Message string `json:"message"`
+}
+
+func (v *PolicyViolation) Error() string {
+	return fmt.Sprintf("policy violation: %s - operation: %s - subject: %s - %s", v.Rule, v.Operation, v.Subject, v.Message)
+}
+
+// Decision is the outcome of evaluating one request. A request is allowed
+// when there are no violations.
+type Decision struct {
+	Time        time.Time          `json:"time"`
+	Operation   string             `json:"operation"`
+	PortfolioId string             `json:"portfolio_id"`
+	Subject     string             `json:"subject"`
+	Amount      string             `json:"amount"`
+	Violations  []*PolicyViolation `json:"violations"`
+}
+
+func (d Decision) Allowed() bool {
+	return len(d.Violations) == 0
+}
+
+// Err returns the first violation or nil if the request is allowed.
+func (d Decision) Err() error {
+	if d.Allowed() {
+		return nil
+	}
+	return d.Violations[0]
+}
+
+func (d *Decision) violate(rule, message string) {
+	d.Violations = append(d.Violations, &PolicyViolation{
+		Rule:      rule,
+		Operation: d.Operation,
+		Subject:   d.Subject,
+		Message:   message,
+	})
+}
+
+// Engine evaluates requests against the rules. The address book service is
+// only needed when RequireAddressBookDestination is set and the price source
+// only for the price band and for orders without a limit price or quote
+// value. Checks that cannot be completed, e.g., because the reference price
+// is unavailable, are reported as violations.
+type Engine struct {
+	rules       *Rules
+	addressBook addressbook.AddressBookService
+	prices      PriceSource
+
+	// Called with every decision. Defaults to the standard logger.
+	OnDecision func(d *Decision)
+
+	// Keeps the daily withdrawal totals per portfolio and symbol. Defaults
+	// to NewMemoryWithdrawalCounter, which is reset by a restart; use
+	// NewFileWithdrawalCounter to persist the totals.
+	Withdrawals WithdrawalCounter
+
+	// Looks up the working order of an edit, so the edited order is checked
+	// like a new one. Edits are blocked while order rules are set and it is
+	// not.
+	Orders orders.OrdersService
+
+	now      func() time.Time
+	mu       sync.Mutex
+	releases bool
+	reserved map[*transactions.CreateWalletWithdrawalRequest]*reservation
+}
+
+// reservation is an amount counted towards the daily limit of a withdrawal
+// that has not completed yet.
+type reservation struct {
+	day         string
+	portfolioId string
+	symbol      string
+	amount      decimal.Decimal
+}
+
+func NewEngine(rules *Rules, addressBook addressbook.AddressBookService, prices PriceSource) (*Engine, error) {
+
+	if rules == nil {
+		return nil, errors.New("rules not set")
+	}
+
+	if err := rules.Validate(); err != nil {
+		return nil, err
+	}
+
+	if rules.RequireAddressBookDestination && addressBook == nil {
+		return nil, errors.New("address book service not set - required by require_address_book_destination")
+	}
+
+	return &Engine{
+		rules:       rules,
+		addressBook: addressBook,
+		prices:      prices,
+		Withdrawals: NewMemoryWithdrawalCounter(),
+		now:         time.Now,
+		reserved:    make(map[*transactions.CreateWalletWithdrawalRequest]*reservation),
+	}, nil
+}
+
+// RequestHook returns a client hook that blocks orders, edits, quotes,
+// conversions, withdrawals and onchain transactions that violate the rules. Add the ResponseHook too, so the
+// amount of a withdrawal that fails is released from the daily limit:
+//
+//	client.AddRequestHook(c, engine.RequestHook())
+//	client.AddResponseHook(c, engine.ResponseHook())
+func (e *Engine) RequestHook() client.RequestHook {
+	return func(ctx context.Context, request interface{}) error {
+		decision := e.Evaluate(ctx, request)
+		if decision == nil {
+			return nil
+		}
+		return decision.Err()
+	}
+}
+
+// ResponseHook returns a client hook that releases the amount of a
+// withdrawal whose call failed, including one blocked by a later request
+// hook, e.g., held for approval.
+func (e *Engine) ResponseHook() client.ResponseHook {
+
+	e.mu.Lock()
+	e.releases = true
+	e.mu.Unlock()
+
+	return func(ctx context.Context, request interface{}, err error) {
+		r, ok := request.(*transactions.CreateWalletWithdrawalRequest)
+		if !ok {
+			return
+		}
+
+		e.mu.Lock()
+		res, ok := e.reserved[r]
+		delete(e.reserved, r)
+		e.mu.Unlock()
+
+		if !ok || err == nil {
+			return
+		}
+
+		if err := e.Withdrawals.Release(res.day, res.portfolioId, res.symbol, res.amount); err != nil {
+			log.Printf("unable to release withdrawal - portfolio: %s - symbol: %s - amount: %s - err: %v",
+				res.portfolioId, res.symbol, res.amount, err)
+		}
+	}
+}
+
+// Evaluate checks a request and logs the decision. It returns nil for
+// request types that are not covered by the policy. An allowed withdrawal
+// is reserved against the daily limit of its portfolio and symbol; the
+// ResponseHook releases it if the call fails. Without the ResponseHook, the
+// amount counts even if the call fails.
+func (e *Engine) Evaluate(ctx context.Context, request interface{}) *Decision {
+
+	var decision *Decision
+
+	switch r := request.(type) {
+	case *orders.CreateOrderRequest:
+		decision = e.evaluateOrder(ctx, r)
+	case *orders.EditOrderRequest:
+		decision = e.evaluateEdit(ctx, r)
+	case *quotes.CreateQuoteRequest:
+		decision = e.evaluateQuote(ctx, r)
+	case *quotes.AcceptQuoteRequest:
+		decision = e.evaluateAcceptQuote(r)
+	case *transactions.CreateWalletWithdrawalRequest:
+		decision = e.evaluateWithdrawal(ctx, r)
+	case *transactions.CreateConversionRequest:
+		decision = e.evaluateConversion(r)
+	case *transactions.CreateOnchainTransactionRequest:
+		decision = e.evaluateOnchainTransaction(r)
+	default:
+		return nil
+	}
+
+	e.logDecision(decision)
+
+	return decision
+}
+
+func (e *Engine) evaluateOrder(ctx context.Context, request *orders.CreateOrderRequest) *Decision {
+
+	d := &Decision{Time: e.now().UTC(), Operation: OperationCreateOrder}
+
+	if request.Order == nil {
+		d.violate(RuleAllowedProducts, "order not set on request")
+		return d
+	}
+
+	e.checkOrder(ctx, d, request.Order)
+
+	return d
+}
+
+// Edits are checked as the working order with the edited size and price.
+func (e *Engine) evaluateEdit(ctx context.Context, request *orders.EditOrderRequest) *Decision {
+
+	d := &Decision{
+		Time:        e.now().UTC(),
+		Operation:   OperationEditOrder,
+		PortfolioId: request.PortfolioId,
+		Subject:     request.OrderId,
+	}
+
+	if len(e.rules.AllowedProducts) == 0 && len(e.rules.MaxOrderNotional) == 0 && e.rules.PriceBand.IsZero() {
+		return d
+	}
+
+	if e.Orders == nil {
+		d.violate(RuleMaxOrderNotional, "unable to look up the edited order - orders service not set")
+		return d
+	}
+
+	response, err := e.Orders.GetOrder(ctx, &orders.GetOrderRequest{PortfolioId: request.PortfolioId, OrderId: request.OrderId})
+	if err != nil || response.Order == nil {
+		d.violate(RuleMaxOrderNotional, fmt.Sprintf("unable to look up the edited order - err: %v", err))
+		return d
+	}
+
+	edited := *response.Order
+	edited.PortfolioId = request.PortfolioId
+
+	switch {
+	case len(request.BaseQuantity) > 0:
+		edited.BaseQuantity, edited.QuoteValue = request.BaseQuantity, ""
+	case len(request.QuoteValue) > 0:
+		edited.BaseQuantity, edited.QuoteValue = "", request.QuoteValue
+	}
+
+	if len(request.LimitPrice) > 0 {
+		edited.LimitPrice = request.LimitPrice
+	}
+
+	e.checkOrder(ctx, d, &edited)
+
+	return d
+}
+
+// Quote requests are checked as limit orders. The accept only carries the
+// product, so the size and price of an RFQ are checked on the request.
+func (e *Engine) evaluateQuote(ctx context.Context, request *quotes.CreateQuoteRequest) *Decision {
+
+	d := &Decision{Time: e.now().UTC(), Operation: OperationCreateQuoteRequest}
+
+	e.checkOrder(ctx, d, &model.Order{
+		PortfolioId:  request.PortfolioId,
+		ProductId:    request.ProductId,
+		Side:         request.Side,
+		Type:         model.OrderTypeLimit,
+		BaseQuantity: request.BaseQuantity,
+		QuoteValue:   request.QuoteValue,
+		LimitPrice:   request.LimitPrice,
+	})
+
+	return d
+}
+
+func (e *Engine) evaluateAcceptQuote(request *quotes.AcceptQuoteRequest) *Decision {
+
+	d := &Decision{
+		Time:        e.now().UTC(),
+		Operation:   OperationAcceptQuote,
+		PortfolioId: request.PortfolioId,
+		Subject:     strings.ToUpper(request.ProductId),
+	}
+
+	e.checkProduct(d)
+
+	return d
+}
+
+// Raw transactions are not decoded, so their destination and amount are
+// unknown. They are blocked while withdrawal rules are set, unless allowed.
+func (e *Engine) evaluateOnchainTransaction(request *transactions.CreateOnchainTransactionRequest) *Decision {
+
+	d := &Decision{
+		Time:        e.now().UTC(),
+		Operation:   OperationCreateOnchainTransaction,
+		PortfolioId: request.PortfolioId,
+		Subject:     request.WalletId,
+	}
+
+	withdrawalRules := e.rules.RequireAddressBookDestination || len(e.rules.MaxDailyWithdrawal) > 0
+
+	if withdrawalRules && !e.rules.AllowOnchainTransactions {
+		d.violate(RuleOnchainTransaction, "raw transactions cannot be checked against the withdrawal rules")
+	}
+
+	return d
+}
+
+// checkOrder sets the subject and amount of the decision and checks the
+// order against the product, notional and price band rules.
+func (e *Engine) checkOrder(ctx context.Context, d *Decision, o *model.Order) {
+
+	d.PortfolioId = o.PortfolioId
+	d.Subject = strings.ToUpper(o.ProductId)
+
+	e.checkProduct(d)
+
+	limit, hasLimit := e.rules.MaxOrderNotional[d.Subject]
+
+	if !hasLimit && (len(o.LimitPrice) == 0 || e.rules.PriceBand.IsZero()) {
+		return
+	}
+
+	var reference decimal.Decimal
+	var referenceErr error
+
+	needsReference := (len(o.LimitPrice) > 0 && e.rules.PriceBand.IsPositive()) ||
+		(hasLimit && len(o.QuoteValue) == 0 && len(o.LimitPrice) == 0)
+
+	if needsReference {
+		reference, referenceErr = e.referencePrice(ctx, d.Subject)
+	}
+
+	if len(o.LimitPrice) > 0 && e.rules.PriceBand.IsPositive() {
+		e.checkPriceBand(d, o.LimitPrice, reference, referenceErr)
+	}
+
+	if hasLimit {
+		notional, err := orderNotional(o, reference, referenceErr)
+		if err != nil {
+			d.violate(RuleMaxOrderNotional, fmt.Sprintf("unable to determine notional - err: %v", err))
+			return
+		}
+
+		d.Amount = notional.String()
+
+		if notional.GreaterThan(limit) {
+			d.violate(RuleMaxOrderNotional, fmt.Sprintf("notional: %s - limit: %s", notional, limit))
+		}
+	}
+}
+
+func (e *Engine) evaluateWithdrawal(ctx context.Context, request *transactions.CreateWalletWithdrawalRequest) *Decision {
+
+	d := &Decision{
+		Time:        e.now().UTC(),
+		Operation:   OperationCreateWalletWithdrawal,
+		PortfolioId: request.PortfolioId,
+		Subject:     strings.ToUpper(request.Symbol),
+		Amount:      request.Amount,
+	}
+
+	if e.rules.RequireAddressBookDestination && request.BlockchainAddress != nil {
+		e.checkAddressBook(ctx, d, request)
+	}
+
+	limit, hasLimit := e.rules.MaxDailyWithdrawal[d.Subject]
+	if !hasLimit {
+		return d
+	}
+
+	amount, err := core.StrToNum(request.Amount)
+	if err != nil {
+		d.violate(RuleMaxDailyWithdrawal, fmt.Sprintf("invalid amount: %s", request.Amount))
+		return d
+	}
+
+	// Other violations block the call, so nothing is reserved
+	if !d.Allowed() {
+		return d
+	}
+
+	day := d.Time.Format("2006-01-02")
+
+	withdrawn, ok, err := e.Withdrawals.Reserve(day, request.PortfolioId, d.Subject, amount, limit)
+	switch {
+	case err != nil:
+		d.violate(RuleMaxDailyWithdrawal, fmt.Sprintf("unable to reserve amount - err: %v", err))
+	case !ok:
+		d.violate(RuleMaxDailyWithdrawal, fmt.Sprintf("withdrawn today: %s - amount: %s - limit: %s", withdrawn, amount, limit))
+	default:
+		// Only tracked when a ResponseHook removes the reservations again
+		e.mu.Lock()
+		if e.releases {
+			e.reserved[request] = &reservation{day: day, portfolioId: request.PortfolioId, symbol: d.Subject, amount: amount}
+		}
+		e.mu.Unlock()
+	}
+
+	return d
+}
+
+// Conversions are checked as a product of the source and destination
+// symbols, e.g., USD-USDC, with the amount as the notional.
+func (e *Engine) evaluateConversion(request *transactions.CreateConversionRequest) *Decision {
+
+	d := &Decision{
+		Time:        e.now().UTC(),
+		Operation:   OperationCreateConversion,
+		PortfolioId: request.PortfolioId,
+		Subject:     strings.ToUpper(request.SourceSymbol + "-" + request.DestinationSymbol),
+		Amount:      request.Amount,
+	}
+
+	e.checkProduct(d)
+
+	limit, hasLimit := e.rules.MaxOrderNotional[d.Subject]
+	if !hasLimit {
+		return d
+	}
+
+	amount, err := core.StrToNum(request.Amount)
+	if err != nil {
+		d.violate(RuleMaxOrderNotional, fmt.Sprintf("invalid amount: %s", request.Amount))
+		return d
+	}
+
+	if amount.GreaterThan(limit) {
+		d.violate(RuleMaxOrderNotional, fmt.Sprintf("notional: %s - limit: %s", amount, limit))
+	}
+
+	return d
+}
+
+func (e *Engine) checkProduct(d *Decision) {
+
+	if len(e.rules.AllowedProducts) == 0 {
+		return
+	}
+
+	for _, p := range e.rules.AllowedProducts {
+		if p == d.Subject {
+			return
+		}
+	}
+
+	d.violate(RuleAllowedProducts, "product not allowed")
+}
+
+func (e *Engine) checkPriceBand(d *Decision, limitPrice string, reference decimal.Decimal, referenceErr error) {
+
+	if referenceErr != nil {
+		d.violate(RulePriceBand, fmt.Sprintf("reference price unavailable - err: %v", referenceErr))
+		return
+	}
+
+	price, err := core.StrToNum(limitPrice)
+	if err != nil {
+		d.violate(RulePriceBand, fmt.Sprintf("invalid limit price: %s", limitPrice))
+		return
+	}
+
+	deviation := price.Sub(reference).Abs().Div(reference)
+
+	if deviation.GreaterThan(e.rules.PriceBand) {
+		d.violate(RulePriceBand, fmt.Sprintf("limit price: %s - reference price: %s - deviation: %s - band: %s",
+			price, reference, deviation.StringFixed(4), e.rules.PriceBand))
+	}
+}
+
+func (e *Engine) checkAddressBook(ctx context.Context, d *Decision, request *transactions.CreateWalletWithdrawalRequest) {
+
+	destination := request.BlockchainAddress
+
+	// Not filtered by the address, since a server side search may not match
+	// the same address in another case
+	response, err := e.addressBook.ListAllAddressBookEntries(ctx, &addressbook.ListAllAddressBookEntriesRequest{
+		PortfolioId: request.PortfolioId,
+		Symbol:      request.Symbol,
+	})
+	if err != nil {
+		d.violate(RuleAddressBook, fmt.Sprintf("unable to list address book - err: %v", err))
+		return
+	}
+
+	address := addressvalidation.CanonicalAddress(destination.Address)
+
+	for _, entry := range response.Addresses {
+		if entry.State == model.AddressBookStateActive &&
+			addressvalidation.CanonicalAddress(entry.Address) == address &&
+			entry.AccountIdentifier == destination.AccountIdentifier {
+			return
+		}
+	}
+
+	d.violate(RuleAddressBook, fmt.Sprintf("destination not in address book: %s", destination.Address))
+}
+
+func (e *Engine) referencePrice(ctx context.Context, productId string) (decimal.Decimal, error) {
+
+	if e.prices == nil {
+		return decimal.Zero, errors.New("price source not set")
+	}
+
+	price, err := e.prices.Price(ctx, productId)
+	if err != nil {
+		return decimal.Zero, err
+	}
+
+	if !price.IsPositive() {
+		return decimal.Zero, fmt.Errorf("invalid reference price: %s", price)
+	}
+
+	return price, nil
+}
+
+func (e *Engine) logDecision(d *Decision) {
+
+	if e.OnDecision != nil {
+		e.OnDecision(d)
+		return
+	}
+
+	if d.Allowed() {
+		log.Printf("policy allowed - operation: %s - portfolio: %s - subject: %s - amount: %s",
+			d.Operation, d.PortfolioId, d.Subject, d.Amount)
+		return
+	}
+
+	for _, v := range d.Violations {
+		log.Printf("policy blocked - operation: %s - portfolio: %s - subject: %s - amount: %s - rule: %s - %s",
+			d.Operation, d.PortfolioId, d.Subject, d.Amount, v.Rule, v.Message)
+	}
+}
+
+// orderNotional values an order in the quote currency: the quote value if
+// set, otherwise the base quantity at the limit price or, for orders
+// without one, the reference price.
+func orderNotional(o *model.Order, reference decimal.Decimal, referenceErr error) (decimal.Decimal, error) {
+
+	if len(o.QuoteValue) > 0 {
+		return core.StrToNum(o.QuoteValue)
+	}
+
+	quantity, err := core.StrToNum(o.BaseQuantity)
+	if err != nil {
+		return decimal.Zero, fmt.Errorf("invalid base quantity: %s", o.BaseQuantity)
+	}
+
+	if len(o.LimitPrice) > 0 {
+		price, err := core.StrToNum(o.LimitPrice)
+		if err != nil {
+			return decimal.Zero, fmt.Errorf("invalid limit price: %s", o.LimitPrice)
+		}
+		return quantity.Mul(price), nil
+	}
+
+	if referenceErr != nil {
+		return decimal.Zero, referenceErr
+	}
+
+	return quantity.Mul(reference), nil
+}
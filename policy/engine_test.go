@@ -0,0 +1,635 @@
+/**
+ * Copyright 2024-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package policy
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/coinbase-samples/prime-sdk-go/addressbook"
+	"github.com/coinbase-samples/prime-sdk-go/client"
+	"github.com/coinbase-samples/prime-sdk-go/credentials"
+	"github.com/coinbase-samples/prime-sdk-go/model"
+	"github.com/coinbase-samples/prime-sdk-go/orders"
+	"github.com/coinbase-samples/prime-sdk-go/quotes"
+	"github.com/coinbase-samples/prime-sdk-go/transactions"
+	"github.com/shopspring/decimal"
+)
+
+const testRules = `
+allowed_products: [btc-usd, ETH-USD, USD-USDC]
+max_order_notional:
+  BTC-USD: "100000"
+  USD-USDC: "1000000"
+max_daily_withdrawal:
+  USDC: "50000"
+require_address_book_destination: true
+price_band: "0.05"
+`
+
+func TestRequestHook(t *testing.T) {
+
+	rules, err := LoadRules(strings.NewReader(testRules))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var sent int
+
+	addressBook := []*model.AddressBookEntry{
+		{Id: "a1", Symbol: "USDC", Address: "0xabc", State: model.AddressBookStateActive},
+		{Id: "a2", Symbol: "USDC", Address: "0xdef", State: "PENDING_APPROVAL"},
+		{Id: "a3", Symbol: "USDC", Address: "0x5aaeb6053f3e94c9b9a09f33669435e7ef1beaed", State: model.AddressBookStateActive},
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/portfolios/p1/address_book":
+			// Searches are case sensitive
+			var entries []*model.AddressBookEntry
+			for _, e := range addressBook {
+				if strings.Contains(e.Address, r.URL.Query().Get("search")) {
+					entries = append(entries, e)
+				}
+			}
+			json.NewEncoder(w).Encode(map[string]interface{}{"addresses": entries})
+		case r.Method == http.MethodGet && r.URL.Path == "/portfolios/p1/orders/o1":
+			w.Write([]byte(`{"order":{"id":"o1","product_id":"BTC-USD","side":"BUY","type":"LIMIT","base_quantity":"1","limit_price":"50000"}}`))
+		case r.Method == http.MethodPost:
+			sent++
+			w.Write([]byte(`{}`))
+		default:
+			t.Errorf("unexpected request: %s %s", r.Method, r.URL)
+		}
+	}))
+	defer server.Close()
+
+	c := client.NewRestClient(&credentials.Credentials{}, http.Client{}).SetBaseUrl(server.URL)
+
+	prices := PriceSourceFunc(func(ctx context.Context, productId string) (decimal.Decimal, error) {
+		if productId == "BTC-USD" {
+			return decimal.NewFromInt(50000), nil
+		}
+		return decimal.Zero, errors.New("no price")
+	})
+
+	engine, err := NewEngine(rules, addressbook.NewAddressBookService(c), prices)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	engine.Orders = orders.NewOrdersService(c)
+
+	var decisions []*Decision
+	engine.OnDecision = func(d *Decision) { decisions = append(decisions, d) }
+
+	if err := client.AddRequestHook(c, engine.RequestHook()); err != nil {
+		t.Fatal(err)
+	}
+
+	ordersSvc := orders.NewOrdersService(c)
+	transactionsSvc := transactions.NewTransactionsService(c)
+	quotesSvc := quotes.NewQuotesService(c)
+
+	order := func(product, quantity, quoteValue, limitPrice string) func(ctx context.Context) error {
+		return func(ctx context.Context) error {
+			_, err := ordersSvc.CreateOrder(ctx, &orders.CreateOrderRequest{Order: &model.Order{
+				PortfolioId:  "p1",
+				ProductId:    product,
+				Side:         model.OrderSideBuy,
+				Type:         model.OrderTypeLimit,
+				BaseQuantity: quantity,
+				QuoteValue:   quoteValue,
+				LimitPrice:   limitPrice,
+			}})
+			return err
+		}
+	}
+
+	withdrawal := func(amount, address string) func(ctx context.Context) error {
+		return func(ctx context.Context) error {
+			_, err := transactionsSvc.CreateWalletWithdrawal(ctx, &transactions.CreateWalletWithdrawalRequest{
+				PortfolioId:       "p1",
+				SourceWalletId:    "w1",
+				Symbol:            "USDC",
+				Amount:            amount,
+				DestinationType:   "DESTINATION_BLOCKCHAIN",
+				BlockchainAddress: &model.BlockchainAddress{Address: address},
+			})
+			return err
+		}
+	}
+
+	edit := func(quantity, limitPrice string) func(ctx context.Context) error {
+		return func(ctx context.Context) error {
+			_, err := ordersSvc.EditOrder(ctx, &orders.EditOrderRequest{
+				PortfolioId:  "p1",
+				OrderId:      "o1",
+				BaseQuantity: quantity,
+				LimitPrice:   limitPrice,
+			})
+			return err
+		}
+	}
+
+	quote := func(product, quantity, limitPrice string) func(ctx context.Context) error {
+		return func(ctx context.Context) error {
+			_, err := quotesSvc.CreateQuoteRequest(ctx, &quotes.CreateQuoteRequest{
+				PortfolioId:  "p1",
+				ProductId:    product,
+				Side:         model.OrderSideBuy,
+				BaseQuantity: quantity,
+				LimitPrice:   limitPrice,
+			})
+			return err
+		}
+	}
+
+	accept := func(product string) func(ctx context.Context) error {
+		return func(ctx context.Context) error {
+			_, err := quotesSvc.AcceptQuote(ctx, &quotes.AcceptQuoteRequest{
+				PortfolioId: "p1",
+				ProductId:   product,
+				Side:        model.OrderSideBuy,
+				QuoteId:     "q1",
+			})
+			return err
+		}
+	}
+
+	onchain := func(ctx context.Context) error {
+		_, err := transactionsSvc.CreateOnchainTransaction(ctx, &transactions.CreateOnchainTransactionRequest{
+			PortfolioId:            "p1",
+			WalletId:               "w1",
+			RawUnsignedTransaction: "02f8",
+		})
+		return err
+	}
+
+	conversion := func(amount string) func(ctx context.Context) error {
+		return func(ctx context.Context) error {
+			_, err := transactionsSvc.CreateConversion(ctx, &transactions.CreateConversionRequest{
+				PortfolioId:         "p1",
+				SourceWalletId:      "w1",
+				SourceSymbol:        "USD",
+				DestinationWalletId: "w2",
+				DestinationSymbol:   "USDC",
+				Amount:              amount,
+			})
+			return err
+		}
+	}
+
+	cases := []struct {
+		description string
+		call        func(ctx context.Context) error
+		rule        string
+	}{
+		{
+			description: "TestRequestHook0",
+			call:        order("BTC-USD", "1", "", "51000"),
+		},
+		{
+			description: "TestRequestHook1",
+			call:        order("SOL-USD", "1", "", "150"),
+			rule:        RuleAllowedProducts,
+		},
+		{
+			description: "TestRequestHook2",
+			call:        order("BTC-USD", "3", "", "50000"),
+			rule:        RuleMaxOrderNotional,
+		},
+		{
+			description: "TestRequestHook3",
+			call:        order("BTC-USD", "", "150000", ""),
+			rule:        RuleMaxOrderNotional,
+		},
+		{
+			description: "TestRequestHook4",
+			call:        order("BTC-USD", "1", "", "44000"),
+			rule:        RulePriceBand,
+		},
+		{
+			description: "TestRequestHook5",
+			call:        order("ETH-USD", "1", "", "3000"),
+			rule:        RulePriceBand,
+		},
+		{
+			description: "TestRequestHook6",
+			call:        withdrawal("40000", "0xabc"),
+		},
+		{
+			description: "TestRequestHook7",
+			call:        withdrawal("20000", "0xabc"),
+			rule:        RuleMaxDailyWithdrawal,
+		},
+		{
+			description: "TestRequestHook8",
+			call:        withdrawal("100", "0xdef"),
+			rule:        RuleAddressBook,
+		},
+		{
+			description: "TestRequestHook11",
+			call:        withdrawal("100", "0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed"),
+		},
+		{
+			description: "TestRequestHook12",
+			call:        edit("1.5", ""),
+		},
+		{
+			description: "TestRequestHook13",
+			call:        edit("3", ""),
+			rule:        RuleMaxOrderNotional,
+		},
+		{
+			description: "TestRequestHook14",
+			call:        edit("", "44000"),
+			rule:        RulePriceBand,
+		},
+		{
+			description: "TestRequestHook15",
+			call:        quote("BTC-USD", "1", "50500"),
+		},
+		{
+			description: "TestRequestHook16",
+			call:        quote("BTC-USD", "3", "50000"),
+			rule:        RuleMaxOrderNotional,
+		},
+		{
+			description: "TestRequestHook17",
+			call:        accept("BTC-USD"),
+		},
+		{
+			description: "TestRequestHook18",
+			call:        accept("SOL-USD"),
+			rule:        RuleAllowedProducts,
+		},
+		{
+			description: "TestRequestHook19",
+			call:        onchain,
+			rule:        RuleOnchainTransaction,
+		},
+		{
+			description: "TestRequestHook9",
+			call:        conversion("250000"),
+		},
+		{
+			description: "TestRequestHook10",
+			call:        conversion("2500000"),
+			rule:        RuleMaxOrderNotional,
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.description, func(t *testing.T) {
+
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+
+			before := sent
+
+			err := tt.call(ctx)
+
+			if len(tt.rule) == 0 {
+				if err != nil {
+					t.Errorf("test: %s - expected: nil - received: %v", tt.description, err)
+				}
+				if sent != before+1 {
+					t.Errorf("test: %s - expected the request to be sent", tt.description)
+				}
+				return
+			}
+
+			var violation *PolicyViolation
+			if !errors.As(err, &violation) {
+				t.Fatalf("test: %s - expected: %s - received: %v", tt.description, tt.rule, err)
+			}
+
+			if violation.Rule != tt.rule {
+				t.Errorf("test: %s - expected: %s - received: %s", tt.description, tt.rule, violation.Rule)
+			}
+
+			if sent != before {
+				t.Errorf("test: %s - expected the request to be blocked", tt.description)
+			}
+		})
+	}
+
+	if len(decisions) != len(cases) {
+		t.Errorf("test: TestRequestHook - expected: %d decisions - received: %d", len(cases), len(decisions))
+	}
+}
+
+func TestDailyWithdrawalReset(t *testing.T) {
+
+	engine, err := NewEngine(&Rules{MaxDailyWithdrawal: map[string]decimal.Decimal{"btc": decimal.NewFromInt(1)}}, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	now := time.Date(2024, 6, 1, 23, 0, 0, 0, time.UTC)
+	engine.now = func() time.Time { return now }
+	engine.OnDecision = func(d *Decision) {}
+
+	request := &transactions.CreateWalletWithdrawalRequest{PortfolioId: "p1", Symbol: "BTC", Amount: "0.75"}
+
+	cases := []struct {
+		description string
+		advance     time.Duration
+		allowed     bool
+	}{
+		{
+			description: "TestDailyWithdrawalReset0",
+			allowed:     true,
+		},
+		{
+			description: "TestDailyWithdrawalReset1",
+			advance:     30 * time.Minute,
+		},
+		{
+			description: "TestDailyWithdrawalReset2",
+			advance:     time.Hour,
+			allowed:     true,
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.description, func(t *testing.T) {
+			now = now.Add(tt.advance)
+
+			decision := engine.Evaluate(context.Background(), request)
+
+			if decision.Allowed() != tt.allowed {
+				t.Errorf("test: %s - expected: %v - received: %v", tt.description, tt.allowed, decision.Allowed())
+			}
+		})
+	}
+}
+
+func TestEvaluateUncheckedRequests(t *testing.T) {
+
+	cases := []struct {
+		description string
+		rules       *Rules
+		request     interface{}
+		rule        string
+	}{
+		{
+			description: "TestEvaluateUncheckedRequests0",
+			rules:       &Rules{MaxOrderNotional: map[string]decimal.Decimal{"BTC-USD": decimal.NewFromInt(1)}},
+			request:     &orders.EditOrderRequest{PortfolioId: "p1", OrderId: "o1", BaseQuantity: "1"},
+			rule:        RuleMaxOrderNotional,
+		},
+		{
+			description: "TestEvaluateUncheckedRequests1",
+			rules:       &Rules{},
+			request:     &orders.EditOrderRequest{PortfolioId: "p1", OrderId: "o1", BaseQuantity: "1"},
+		},
+		{
+			description: "TestEvaluateUncheckedRequests2",
+			rules:       &Rules{MaxDailyWithdrawal: map[string]decimal.Decimal{"ETH": decimal.NewFromInt(1)}},
+			request:     &transactions.CreateOnchainTransactionRequest{PortfolioId: "p1", WalletId: "w1"},
+			rule:        RuleOnchainTransaction,
+		},
+		{
+			description: "TestEvaluateUncheckedRequests3",
+			rules:       &Rules{MaxDailyWithdrawal: map[string]decimal.Decimal{"ETH": decimal.NewFromInt(1)}, AllowOnchainTransactions: true},
+			request:     &transactions.CreateOnchainTransactionRequest{PortfolioId: "p1", WalletId: "w1"},
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.description, func(t *testing.T) {
+
+			engine, err := NewEngine(tt.rules, nil, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			engine.OnDecision = func(d *Decision) {}
+
+			err = engine.Evaluate(context.Background(), tt.request).Err()
+
+			if len(tt.rule) == 0 {
+				if err != nil {
+					t.Errorf("test: %s - expected: nil - received: %v", tt.description, err)
+				}
+				return
+			}
+
+			var violation *PolicyViolation
+			if !errors.As(err, &violation) || violation.Rule != tt.rule {
+				t.Errorf("test: %s - expected: %s - received: %v", tt.description, tt.rule, err)
+			}
+		})
+	}
+}
+
+func TestReleaseWithdrawal(t *testing.T) {
+
+	var fail bool
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if fail {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	c := client.NewRestClient(&credentials.Credentials{}, http.Client{}).SetBaseUrl(server.URL)
+
+	engine, err := NewEngine(&Rules{MaxDailyWithdrawal: map[string]decimal.Decimal{"USDC": decimal.NewFromInt(10)}}, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	engine.OnDecision = func(d *Decision) {}
+
+	if err := client.AddRequestHook(c, engine.RequestHook()); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := client.AddResponseHook(c, engine.ResponseHook()); err != nil {
+		t.Fatal(err)
+	}
+
+	svc := transactions.NewTransactionsService(c)
+
+	cases := []struct {
+		description string
+		portfolioId string
+		fail        bool
+		rule        string
+	}{
+		{
+			description: "TestReleaseWithdrawal0",
+			portfolioId: "p1",
+			fail:        true,
+		},
+		{
+			description: "TestReleaseWithdrawal1",
+			portfolioId: "p1",
+		},
+		{
+			description: "TestReleaseWithdrawal2",
+			portfolioId: "p1",
+			rule:        RuleMaxDailyWithdrawal,
+		},
+		{
+			description: "TestReleaseWithdrawal3",
+			portfolioId: "p2",
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.description, func(t *testing.T) {
+
+			fail = tt.fail
+
+			_, err := svc.CreateWalletWithdrawal(context.Background(), &transactions.CreateWalletWithdrawalRequest{
+				PortfolioId:    tt.portfolioId,
+				SourceWalletId: "w1",
+				Symbol:         "USDC",
+				Amount:         "6",
+			})
+
+			var violation *PolicyViolation
+			isViolation := errors.As(err, &violation)
+
+			switch {
+			case tt.fail:
+				if err == nil || isViolation {
+					t.Errorf("test: %s - expected: api error - received: %v", tt.description, err)
+				}
+			case len(tt.rule) > 0:
+				if !isViolation || violation.Rule != tt.rule {
+					t.Errorf("test: %s - expected: %s - received: %v", tt.description, tt.rule, err)
+				}
+			case err != nil:
+				t.Errorf("test: %s - expected: nil - received: %v", tt.description, err)
+			}
+		})
+	}
+
+	if len(engine.reserved) != 0 {
+		t.Errorf("test: TestReleaseWithdrawal - expected: 0 reservations - received: %d", len(engine.reserved))
+	}
+}
+
+func TestFileWithdrawalCounter(t *testing.T) {
+
+	path := filepath.Join(t.TempDir(), "withdrawals.json")
+
+	limit := decimal.NewFromInt(10)
+
+	counter, err := NewFileWithdrawalCounter(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, ok, err := counter.Reserve("2024-06-01", "p1", "USDC", decimal.NewFromInt(6), limit); err != nil || !ok {
+		t.Fatalf("test: TestFileWithdrawalCounter - expected: reserved - received: %v - %v", ok, err)
+	}
+
+	counter, err = NewFileWithdrawalCounter(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		description string
+		day         string
+		portfolioId string
+		withdrawn   string
+		ok          bool
+	}{
+		{
+			description: "TestFileWithdrawalCounter0",
+			day:         "2024-06-01",
+			portfolioId: "p1",
+			withdrawn:   "6",
+		},
+		{
+			description: "TestFileWithdrawalCounter1",
+			day:         "2024-06-01",
+			portfolioId: "p2",
+			withdrawn:   "0",
+			ok:          true,
+		},
+		{
+			description: "TestFileWithdrawalCounter2",
+			day:         "2024-06-02",
+			portfolioId: "p1",
+			withdrawn:   "0",
+			ok:          true,
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.description, func(t *testing.T) {
+
+			withdrawn, ok, err := counter.Reserve(tt.day, tt.portfolioId, "USDC", decimal.NewFromInt(6), limit)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if ok != tt.ok {
+				t.Errorf("test: %s - expected: %v - received: %v", tt.description, tt.ok, ok)
+			}
+
+			if !withdrawn.Equal(decimal.RequireFromString(tt.withdrawn)) {
+				t.Errorf("test: %s - expected: %s - received: %s", tt.description, tt.withdrawn, withdrawn)
+			}
+		})
+	}
+}
+
+func TestLoadRules(t *testing.T) {
+
+	cases := []struct {
+		description string
+		rules       string
+	}{
+		{
+			description: "TestLoadRules0",
+			rules:       "price_band: \"-0.1\"",
+		},
+		{
+			description: "TestLoadRules1",
+			rules:       "max_order_notional:\n  BTC-USD: \"1\"\n  btc-usd: \"2\"",
+		},
+		{
+			description: "TestLoadRules2",
+			rules:       "max_notional:\n  BTC-USD: \"1\"",
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.description, func(t *testing.T) {
+			if _, err := LoadRules(strings.NewReader(tt.rules)); err == nil {
+				t.Errorf("test: %s - expected an error", tt.description)
+			}
+		})
+	}
+}
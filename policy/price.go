@@ -0,0 +1,70 @@
+/**
+ * Copyright 2024-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package policy
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/coinbase-samples/prime-sdk-go/orderbook"
+	"github.com/shopspring/decimal"
+)
+
+// PriceSource returns the reference price of a product, used to check limit
+// prices against the price band and to value orders without a price.
+type PriceSource interface {
+	Price(ctx context.Context, productId string) (decimal.Decimal, error)
+}
+
+// PriceSourceFunc adapts a function to a PriceSource.
+type PriceSourceFunc func(ctx context.Context, productId string) (decimal.Decimal, error)
+
+func (f PriceSourceFunc) Price(ctx context.Context, productId string) (decimal.Decimal, error) {
+	return f(ctx, productId)
+}
+
+// BookPriceSource returns the mid price of local order books, e.g., books
+// maintained from the WebSocket l2_data feed. Books that are not synced
+// return an error, so the checks that need a price fail closed.
+func BookPriceSource(books ...*orderbook.Book) PriceSource {
+
+	byProduct := make(map[string]*orderbook.Book, len(books))
+	for _, b := range books {
+		byProduct[strings.ToUpper(b.ProductId())] = b
+	}
+
+	return PriceSourceFunc(func(ctx context.Context, productId string) (decimal.Decimal, error) {
+
+		book, ok := byProduct[strings.ToUpper(productId)]
+		if !ok {
+			return decimal.Zero, fmt.Errorf("no order book - product: %s", productId)
+		}
+
+		bid, err := book.BestBid()
+		if err != nil {
+			return decimal.Zero, err
+		}
+
+		ask, err := book.BestAsk()
+		if err != nil {
+			return decimal.Zero, err
+		}
+
+		return bid.Price.Add(ask.Price).Div(decimal.NewFromInt(2)), nil
+	})
+}
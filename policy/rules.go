@@ -0,0 +1,143 @@
+/**
+ * Copyright 2024-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package policy
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/shopspring/decimal"
+	"gopkg.in/yaml.v3"
+)
+
+// Rules are the local guardrails checked before a request is sent. A zero
+// value disables the rule.
+type Rules struct {
+
+	// The products orders may be placed on. Empty allows all products.
+	AllowedProducts []string `json:"allowed_products" yaml:"allowed_products"`
+
+	// The max order notional in the quote currency, keyed by product id
+	MaxOrderNotional map[string]decimal.Decimal `json:"max_order_notional" yaml:"max_order_notional"`
+
+	// The max amount withdrawn per UTC day, keyed by symbol
+	MaxDailyWithdrawal map[string]decimal.Decimal `json:"max_daily_withdrawal" yaml:"max_daily_withdrawal"`
+
+	// Blockchain withdrawals must go to an active address book entry
+	RequireAddressBookDestination bool `json:"require_address_book_destination" yaml:"require_address_book_destination"`
+
+	// Raw onchain transactions cannot be checked against the withdrawal
+	// rules, so they are blocked while those are set, unless allowed
+	AllowOnchainTransactions bool `json:"allow_onchain_transactions" yaml:"allow_onchain_transactions"`
+
+	// The max relative deviation of a limit price from the reference price,
+	// e.g., 0.05 allows limit prices within 5% of the reference
+	PriceBand decimal.Decimal `json:"price_band" yaml:"price_band"`
+}
+
+// Validate checks that the limits are not negative and normalizes the
+// product ids and symbols to upper case.
+func (r *Rules) Validate() error {
+
+	for i, p := range r.AllowedProducts {
+		if len(strings.TrimSpace(p)) == 0 {
+			return errors.New("empty allowed product")
+		}
+		r.AllowedProducts[i] = strings.ToUpper(p)
+	}
+
+	var err error
+
+	if r.MaxOrderNotional, err = normalizeLimits("max_order_notional", r.MaxOrderNotional); err != nil {
+		return err
+	}
+
+	if r.MaxDailyWithdrawal, err = normalizeLimits("max_daily_withdrawal", r.MaxDailyWithdrawal); err != nil {
+		return err
+	}
+
+	if r.PriceBand.IsNegative() {
+		return fmt.Errorf("negative price_band: %s", r.PriceBand)
+	}
+
+	return nil
+}
+
+// LoadRules reads rules from YAML, e.g.:
+//
+//	allowed_products: [BTC-USD, ETH-USD]
+//	max_order_notional:
+//	  BTC-USD: "2500000"
+//	max_daily_withdrawal:
+//	  USDC: "10000000"
+//	require_address_book_destination: true
+//	price_band: "0.05"
+func LoadRules(r io.Reader) (*Rules, error) {
+
+	decoder := yaml.NewDecoder(r)
+	decoder.KnownFields(true)
+
+	rules := &Rules{}
+	if err := decoder.Decode(rules); err != nil {
+		return nil, fmt.Errorf("unable to deserialize policy rules: %w", err)
+	}
+
+	if err := rules.Validate(); err != nil {
+		return nil, err
+	}
+
+	return rules, nil
+}
+
+// LoadRulesFile reads rules from a YAML file.
+func LoadRulesFile(filename string) (*Rules, error) {
+
+	f, err := os.Open(filename)
+	if err != nil {
+		return nil, fmt.Errorf("unable to open policy rules: %s - err: %w", filename, err)
+	}
+	defer f.Close()
+
+	return LoadRules(f)
+}
+
+func normalizeLimits(name string, limits map[string]decimal.Decimal) (map[string]decimal.Decimal, error) {
+
+	if len(limits) == 0 {
+		return limits, nil
+	}
+
+	normalized := make(map[string]decimal.Decimal, len(limits))
+
+	for k, v := range limits {
+
+		if v.IsNegative() {
+			return nil, fmt.Errorf("negative %s: %s - key: %s", name, v, k)
+		}
+
+		key := strings.ToUpper(k)
+		if _, ok := normalized[key]; ok {
+			return nil, fmt.Errorf("duplicate %s - key: %s", name, k)
+		}
+		normalized[key] = v
+	}
+
+	return normalized, nil
+}
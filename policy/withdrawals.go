@@ -0,0 +1,177 @@
+/**
+ * Copyright 2024-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package policy
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/shopspring/decimal"
+)
+
+// WithdrawalCounter keeps the amounts withdrawn per portfolio and symbol on
+// a UTC day, e.g., 2024-06-01. The engine reserves the amount of a
+// withdrawal before it is sent and releases it if the call fails.
+type WithdrawalCounter interface {
+	// Reserve adds the amount to the total of the day, unless the total
+	// would exceed the limit. It returns the total before the amount and
+	// whether the amount was added.
+	Reserve(day, portfolioId, symbol string, amount, limit decimal.Decimal) (decimal.Decimal, bool, error)
+
+	// Release subtracts an amount that was reserved on the day.
+	Release(day, portfolioId, symbol string, amount decimal.Decimal) error
+}
+
+// NewMemoryWithdrawalCounter returns a counter that keeps the totals in
+// memory, so they are reset when the process restarts.
+func NewMemoryWithdrawalCounter() WithdrawalCounter {
+	return &withdrawalCounter{totals: make(map[string]decimal.Decimal)}
+}
+
+// NewFileWithdrawalCounter returns a counter that persists the totals of the
+// current day as JSON at path, so they survive a restart. The file must not
+// be shared by processes that run at the same time.
+func NewFileWithdrawalCounter(path string) (WithdrawalCounter, error) {
+
+	c := &withdrawalCounter{path: path, totals: make(map[string]decimal.Decimal)}
+
+	b, err := os.ReadFile(path)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("unable to read withdrawal counter: %s - err: %w", path, err)
+	}
+
+	if len(b) > 0 {
+		state := &withdrawalCounterState{}
+		if err := json.Unmarshal(b, state); err != nil {
+			return nil, fmt.Errorf("unable to deserialize withdrawal counter: %s - err: %w", path, err)
+		}
+		c.day = state.Day
+		for k, v := range state.Totals {
+			c.totals[k] = v
+		}
+	}
+
+	return c, nil
+}
+
+type withdrawalCounterState struct {
+	Day    string                     `json:"day"`
+	Totals map[string]decimal.Decimal `json:"totals"`
+}
+
+type withdrawalCounter struct {
+	path   string
+	day    string
+	totals map[string]decimal.Decimal
+	mu     sync.Mutex
+}
+
+func (c *withdrawalCounter) Reserve(day, portfolioId, symbol string, amount, limit decimal.Decimal) (decimal.Decimal, bool, error) {
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if day != c.day {
+		c.day = day
+		c.totals = make(map[string]decimal.Decimal)
+	}
+
+	k := portfolioId + "/" + symbol
+	withdrawn := c.totals[k]
+
+	if withdrawn.Add(amount).GreaterThan(limit) {
+		return withdrawn, false, nil
+	}
+
+	c.totals[k] = withdrawn.Add(amount)
+
+	if err := c.save(); err != nil {
+		c.totals[k] = withdrawn
+		return withdrawn, false, err
+	}
+
+	return withdrawn, true, nil
+}
+
+func (c *withdrawalCounter) Release(day, portfolioId, symbol string, amount decimal.Decimal) error {
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if day != c.day {
+		return nil
+	}
+
+	k := portfolioId + "/" + symbol
+	withdrawn := c.totals[k]
+
+	c.totals[k] = decimal.Max(withdrawn.Sub(amount), decimal.Zero)
+
+	if err := c.save(); err != nil {
+		c.totals[k] = withdrawn
+		return err
+	}
+
+	return nil
+}
+
+// save writes to a temporary file and renames it, so a crash never leaves
+// a partially written counter behind.
+func (c *withdrawalCounter) save() error {
+
+	if len(c.path) == 0 {
+		return nil
+	}
+
+	b, err := json.MarshalIndent(&withdrawalCounterState{Day: c.day, Totals: c.totals}, "", "  ")
+	if err != nil {
+		return fmt.Errorf("unable to serialize withdrawal counter: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(c.path), filepath.Base(c.path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("unable to create withdrawal counter file: %w", err)
+	}
+
+	if _, err := tmp.Write(b); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return fmt.Errorf("unable to write withdrawal counter: %w", err)
+	}
+
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return fmt.Errorf("unable to sync withdrawal counter: %w", err)
+	}
+
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return fmt.Errorf("unable to close withdrawal counter file: %w", err)
+	}
+
+	if err := os.Rename(tmp.Name(), c.path); err != nil {
+		os.Remove(tmp.Name())
+		return fmt.Errorf("unable to replace withdrawal counter: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,131 @@
+/**
+ * Copyright 2024-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package portfolios
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/coinbase-samples/prime-sdk-go/client"
+	"github.com/coinbase-samples/prime-sdk-go/model"
+)
+
+const defaultFanOutConcurrency = 5
+
+// PortfolioItem is a result of a portfolio scoped call, tagged with the
+// portfolio it came from.
+type PortfolioItem[T any] struct {
+	PortfolioId string
+	Item        T
+}
+
+type PortfolioError struct {
+	PortfolioId string
+	Err         error
+}
+
+func (e *PortfolioError) Error() string {
+	return fmt.Sprintf("portfolio: %s - err: %v", e.PortfolioId, e.Err)
+}
+
+func (e *PortfolioError) Unwrap() error {
+	return e.Err
+}
+
+type FanOutResult[T any] struct {
+	// Items are ordered by portfolio, in the order returned by ListPortfolios,
+	// and then in the order returned by the call.
+	Items []*PortfolioItem[T]
+
+	// Failed holds an error for each portfolio where the call failed
+	Failed []*PortfolioError
+}
+
+// FanOut lists the portfolios of the client and runs a portfolio scoped call,
+// e.g., balances.ListPortfolioBalances, across all of them. At most
+// concurrency calls are in flight; it defaults to 5. Calls are also subject
+// to the client rate limiter, if set. A failed call does not stop the others.
+// An error is only returned if the portfolios cannot be listed.
+func FanOut[T any](
+	ctx context.Context,
+	c client.RestClient,
+	concurrency int,
+	call func(ctx context.Context, portfolio *model.Portfolio) ([]T, error),
+) (*FanOutResult[T], error) {
+
+	response, err := NewPortfoliosService(c).ListPortfolios(ctx, &ListPortfoliosRequest{})
+	if err != nil {
+		return nil, fmt.Errorf("unable to list portfolios: %w", err)
+	}
+
+	return FanOutPortfolios(ctx, response.Portfolios, concurrency, call), nil
+}
+
+// FanOutPortfolios runs the call across the portfolios passed in. See FanOut.
+func FanOutPortfolios[T any](
+	ctx context.Context,
+	portfolios []*model.Portfolio,
+	concurrency int,
+	call func(ctx context.Context, portfolio *model.Portfolio) ([]T, error),
+) *FanOutResult[T] {
+
+	if concurrency <= 0 {
+		concurrency = defaultFanOutConcurrency
+	}
+
+	items := make([][]T, len(portfolios))
+	errs := make([]error, len(portfolios))
+
+	sem := make(chan struct{}, concurrency)
+
+	var wg sync.WaitGroup
+
+	for i, p := range portfolios {
+
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			errs[i] = ctx.Err()
+			continue
+		}
+
+		wg.Add(1)
+		go func(i int, p *model.Portfolio) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			items[i], errs[i] = call(ctx, p)
+		}(i, p)
+	}
+
+	wg.Wait()
+
+	result := &FanOutResult[T]{}
+
+	for i, p := range portfolios {
+		if errs[i] != nil {
+			result.Failed = append(result.Failed, &PortfolioError{PortfolioId: p.Id, Err: errs[i]})
+			continue
+		}
+
+		for _, item := range items[i] {
+			result.Items = append(result.Items, &PortfolioItem[T]{PortfolioId: p.Id, Item: item})
+		}
+	}
+
+	return result
+}
@@ -0,0 +1,101 @@
+/**
+ * Copyright 2024-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package portfolios
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/coinbase-samples/prime-sdk-go/balances"
+	"github.com/coinbase-samples/prime-sdk-go/client"
+	"github.com/coinbase-samples/prime-sdk-go/credentials"
+	"github.com/coinbase-samples/prime-sdk-go/model"
+)
+
+func TestFanOut(t *testing.T) {
+
+	var inFlight, maxInFlight int32
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/portfolios":
+			w.Write([]byte(`{"portfolios":[{"id":"p1"},{"id":"p2"},{"id":"p3"},{"id":"p4"}]}`))
+		case "/portfolios/p3/balances":
+			w.WriteHeader(http.StatusInternalServerError)
+			w.Write([]byte(`{"message":"internal error"}`))
+		default:
+			n := atomic.AddInt32(&inFlight, 1)
+			for {
+				m := atomic.LoadInt32(&maxInFlight)
+				if n <= m || atomic.CompareAndSwapInt32(&maxInFlight, m, n) {
+					break
+				}
+			}
+			time.Sleep(20 * time.Millisecond)
+			atomic.AddInt32(&inFlight, -1)
+			w.Write([]byte(`{"balances":[{"symbol":"BTC","amount":"1"},{"symbol":"ETH","amount":"2"}]}`))
+		}
+	}))
+	defer server.Close()
+
+	c := client.NewRestClient(&credentials.Credentials{}, http.Client{}).SetBaseUrl(server.URL)
+
+	svc := balances.NewBalancesService(c)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	result, err := FanOut(ctx, c, 2, func(ctx context.Context, p *model.Portfolio) ([]*model.Balance, error) {
+		response, err := svc.ListPortfolioBalances(ctx, &balances.ListPortfolioBalancesRequest{PortfolioId: p.Id})
+		if err != nil {
+			return nil, err
+		}
+		return response.Balances, nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []string{"p1", "p1", "p2", "p2", "p4", "p4"}
+	if len(result.Items) != len(expected) {
+		t.Fatalf("expected: %d items - received: %d", len(expected), len(result.Items))
+	}
+
+	for i, item := range result.Items {
+		if item.PortfolioId != expected[i] {
+			t.Errorf("item: %d - expected: %s - received: %s", i, expected[i], item.PortfolioId)
+		}
+	}
+
+	if len(result.Failed) != 1 || result.Failed[0].PortfolioId != "p3" {
+		t.Errorf("expected p3 to fail - received: %v", result.Failed)
+	}
+
+	var apiErr error = result.Failed[0]
+	if errors.Unwrap(apiErr) == nil {
+		t.Error("expected the call error to be wrapped")
+	}
+
+	if m := atomic.LoadInt32(&maxInFlight); m > 2 {
+		t.Errorf("expected at most 2 calls in flight - received: %d", m)
+	}
+}
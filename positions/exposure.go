@@ -0,0 +1,125 @@
+/**
+ * Copyright 2024-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package positions
+
+import (
+	"sort"
+
+	"github.com/coinbase-samples/prime-sdk-go/model"
+	"github.com/shopspring/decimal"
+)
+
+// Exposure is the combined view of an asset across portfolios: the balance
+// held and the long and short positions. A portfolio's long position already
+// includes its balance, so Long is the long position of the portfolios with
+// positions in the asset plus the balance of the others.
+type Exposure struct {
+	Symbol  string
+	Balance decimal.Decimal
+	Long    decimal.Decimal
+	Short   decimal.Decimal
+}
+
+// Net returns the long quantity less the short quantity.
+func (e Exposure) Net() decimal.Decimal {
+	return e.Long.Sub(e.Short)
+}
+
+// Gross returns the long plus the short quantity.
+func (e Exposure) Gross() decimal.Decimal {
+	return e.Long.Add(e.Short)
+}
+
+// Holdings are the balances and positions listed for one portfolio.
+type Holdings struct {
+	Balances  []*model.Balance
+	Positions []*model.Position
+}
+
+// CombineExposures merges the holdings of portfolios, keyed by portfolio id,
+// into one exposure per asset, sorted by symbol. Within a portfolio, the
+// balance of an asset counts as long only when the portfolio has no position
+// in it, so leave Positions empty for portfolios without positions rather
+// than omitting the portfolio.
+func CombineExposures(portfolios map[string]*Holdings) ([]*Exposure, error) {
+
+	bySymbol := make(map[string]*Exposure)
+
+	exposure := func(symbol string) *Exposure {
+		e, ok := bySymbol[symbol]
+		if !ok {
+			e = &Exposure{Symbol: symbol}
+			bySymbol[symbol] = e
+		}
+		return e
+	}
+
+	for _, h := range portfolios {
+		if h == nil {
+			continue
+		}
+
+		balances := make(map[string]decimal.Decimal)
+		positioned := make(map[string]bool)
+
+		for _, b := range h.Balances {
+			amount, err := b.AmountNum()
+			if err != nil {
+				return nil, err
+			}
+			balances[b.Symbol] = balances[b.Symbol].Add(amount)
+
+			e := exposure(b.Symbol)
+			e.Balance = e.Balance.Add(amount)
+		}
+
+		for _, p := range h.Positions {
+			long, err := p.LongNum()
+			if err != nil {
+				return nil, err
+			}
+
+			short, err := p.ShortNum()
+			if err != nil {
+				return nil, err
+			}
+
+			e := exposure(p.Symbol)
+			e.Long = e.Long.Add(long)
+			e.Short = e.Short.Add(short)
+			positioned[p.Symbol] = true
+		}
+
+		for symbol, amount := range balances {
+			if !positioned[symbol] {
+				e := exposure(symbol)
+				e.Long = e.Long.Add(amount)
+			}
+		}
+	}
+
+	exposures := make([]*Exposure, 0, len(bySymbol))
+	for _, e := range bySymbol {
+		exposures = append(exposures, e)
+	}
+
+	sort.Slice(exposures, func(i, j int) bool {
+		return exposures[i].Symbol < exposures[j].Symbol
+	})
+
+	return exposures, nil
+}
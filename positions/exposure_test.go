@@ -0,0 +1,110 @@
+/**
+ * Copyright 2024-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package positions
+
+import (
+	"testing"
+
+	"github.com/coinbase-samples/prime-sdk-go/model"
+)
+
+func TestCombineExposures(t *testing.T) {
+
+	portfolios := map[string]*Holdings{
+		"p1": {
+			Balances: []*model.Balance{
+				{Symbol: "BTC", Amount: "2"},
+				{Symbol: "ETH", Amount: "10"},
+			},
+			Positions: []*model.Position{
+				{Symbol: "BTC", Long: "3.5", Short: "4"},
+				{Symbol: "SOL", Long: "", Short: "100"},
+			},
+		},
+		// Positions not listed, so the balances count as long
+		"p2": {
+			Balances: []*model.Balance{
+				{Symbol: "BTC", Amount: "1"},
+			},
+		},
+	}
+
+	exposures, err := CombineExposures(portfolios)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		description string
+		symbol      string
+		long        string
+		net         string
+		gross       string
+	}{
+		{
+			description: "TestCombineExposures0",
+			symbol:      "BTC",
+			long:        "4.5",
+			net:         "0.5",
+			gross:       "8.5",
+		},
+		{
+			description: "TestCombineExposures1",
+			symbol:      "ETH",
+			long:        "10",
+			net:         "10",
+			gross:       "10",
+		},
+		{
+			description: "TestCombineExposures2",
+			symbol:      "SOL",
+			long:        "0",
+			net:         "-100",
+			gross:       "100",
+		},
+	}
+
+	if len(exposures) != len(cases) {
+		t.Fatalf("expected: %d exposures - received: %d", len(cases), len(exposures))
+	}
+
+	for idx, tt := range cases {
+		t.Run(tt.description, func(t *testing.T) {
+			e := exposures[idx]
+
+			if e.Symbol != tt.symbol {
+				t.Fatalf("test: %s - expected: %s - received: %s", tt.description, tt.symbol, e.Symbol)
+			}
+
+			if e.Long.String() != tt.long {
+				t.Errorf("test: %s - expected: %s - received: %s", tt.description, tt.long, e.Long)
+			}
+
+			if e.Net().String() != tt.net || e.Gross().String() != tt.gross {
+				t.Errorf("test: %s - expected: %s / %s - received: %s / %s", tt.description, tt.net, tt.gross, e.Net(), e.Gross())
+			}
+		})
+	}
+
+	if _, err := CombineExposures(map[string]*Holdings{"p1": {Balances: []*model.Balance{{Symbol: "BTC", Amount: "x"}}}}); err == nil {
+		t.Error("expected an error for an invalid balance")
+	}
+
+	if _, err := CombineExposures(map[string]*Holdings{"p1": {Positions: []*model.Position{{Symbol: "BTC", Long: "x"}}}}); err == nil {
+		t.Error("expected an error for an invalid position")
+	}
+}
@@ -0,0 +1,37 @@
+/**
+ * Copyright 2024-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package positions
+
+import (
+	"context"
+
+	"github.com/coinbase-samples/prime-sdk-go/client"
+)
+
+type PositionsService interface {
+	ListEntityPositions(ctx context.Context, request *ListEntityPositionsRequest) (*ListEntityPositionsResponse, error)
+	ListAggregateEntityPositions(ctx context.Context, request *ListAggregateEntityPositionsRequest) (*ListAggregateEntityPositionsResponse, error)
+	ListPortfolioPositions(ctx context.Context, request *ListPortfolioPositionsRequest) (*ListPortfolioPositionsResponse, error)
+}
+
+func NewPositionsService(c client.RestClient) PositionsService {
+	return &positionsServiceImpl{client: c}
+}
+
+type positionsServiceImpl struct {
+	client client.RestClient
+}
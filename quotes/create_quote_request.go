@@ -0,0 +1,80 @@
+/**
+ * Copyright 2024-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package quotes
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/coinbase-samples/core-go"
+	"github.com/coinbase-samples/prime-sdk-go/client"
+	"github.com/coinbase-samples/prime-sdk-go/model"
+)
+
+type CreateQuoteRequest struct {
+	PortfolioId   string `json:"portfolio_id"`
+	ProductId     string `json:"product_id"`
+	Side          string `json:"side"`
+	ClientQuoteId string `json:"client_quote_id"`
+
+	// The worst price the quote may be executed at
+	LimitPrice string `json:"limit_price"`
+
+	// Quote size in base asset units (either `base_quantity` or `quote_value` is required)
+	BaseQuantity string `json:"base_quantity,omitempty"`
+	QuoteValue   string `json:"quote_value,omitempty"`
+}
+
+type CreateQuoteResponse struct {
+	model.Quote
+	Request *CreateQuoteRequest `json:"request"`
+}
+
+// CreateQuoteRequest requests a quote for a product, side and size. The
+// returned quote must be accepted with AcceptQuote before it expires.
+// https://docs.cdp.coinbase.com/prime/reference/primerestapi_createquoterequest
+func (s *quotesServiceImpl) CreateQuoteRequest(
+	ctx context.Context,
+	request *CreateQuoteRequest,
+) (*CreateQuoteResponse, error) {
+
+	path := fmt.Sprintf("/portfolios/%s/rfq", request.PortfolioId)
+
+	if err := client.RunRequestHooks(ctx, s.client, request); err != nil {
+		return nil, err
+	}
+
+	response := &CreateQuoteResponse{Request: request}
+
+	if err := core.HttpPost(
+		ctx,
+		s.client,
+		path,
+		core.EmptyQueryParams,
+		client.DefaultSuccessHttpStatusCodes,
+		request,
+		response,
+		s.client.HeadersFunc(),
+	); err != nil {
+		client.RunResponseHooks(ctx, s.client, request, err)
+		return nil, err
+	}
+
+	client.RunResponseHooks(ctx, s.client, request, nil)
+
+	return response, nil
+}
@@ -0,0 +1,159 @@
+/**
+ * Copyright 2024-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package quotes
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/coinbase-samples/prime-sdk-go/model"
+)
+
+const (
+	defaultQuoteSafetyMargin = 250 * time.Millisecond
+	defaultQuoteClockSkew    = 500 * time.Millisecond
+)
+
+var (
+	// ErrQuoteRejected is returned by RequestAndAcceptQuote when the price
+	// check does not approve the quote.
+	ErrQuoteRejected = errors.New("quote rejected by price check")
+
+	// ErrQuoteExpired is returned by RequestAndAcceptQuote when the quote
+	// expires, or is about to, before it can be accepted.
+	ErrQuoteExpired = errors.New("quote expired before accept")
+)
+
+// QuoteCheck evaluates a quote before it is accepted. Return false to let
+// the quote expire. An error stops the request and is returned to the
+// caller.
+type QuoteCheck func(ctx context.Context, quote *model.Quote) (bool, error)
+
+type RequestAndAcceptQuoteRequest struct {
+	Quote *CreateQuoteRequest `json:"quote"`
+
+	// The client order id of the order created when the quote is accepted
+	ClientOrderId string `json:"client_order_id"`
+
+	// Required. Called with the quote returned by Prime.
+	Check QuoteCheck `json:"-"`
+
+	// The quote is not accepted if less than the margin remains before it
+	// expires. Defaults to 250ms.
+	SafetyMargin time.Duration `json:"safety_margin"`
+
+	// The expiration time is set by Prime's clock and compared to the local
+	// clock, so the window is also shortened by the max expected skew
+	// between the two. Defaults to 500ms. Set to a negative value if the
+	// local clock is known to be in sync.
+	MaxClockSkew time.Duration `json:"max_clock_skew"`
+}
+
+type RequestAndAcceptQuoteResponse struct {
+	Quote   *model.Quote                  `json:"quote"`
+	OrderId string                        `json:"order_id"`
+	Request *RequestAndAcceptQuoteRequest `json:"request"`
+}
+
+// Accepted returns true if the quote was accepted and an order created.
+func (r RequestAndAcceptQuoteResponse) Accepted() bool {
+	return len(r.OrderId) > 0
+}
+
+// RequestAndAcceptQuote requests a quote, evaluates it with the caller's
+// price check and accepts it, all within the validity window of the quote.
+// The window ends at the expiration time less the safety margin and the max
+// clock skew. The check gets a context that is cancelled at the end of the
+// window and the window is checked again right before the accept is sent,
+// so a slow check never results in an attempt to accept a stale quote. The
+// accept itself is sent with the caller's context, so the window closing
+// while it is in flight does not abandon an accept that Prime may already
+// have executed. If the quote is rejected or expires, the response is
+// returned with ErrQuoteRejected or ErrQuoteExpired. If the accept call
+// itself fails, Prime may still have executed the quote; look up the order
+// by client order id before retrying.
+func (s *quotesServiceImpl) RequestAndAcceptQuote(
+	ctx context.Context,
+	request *RequestAndAcceptQuoteRequest,
+) (*RequestAndAcceptQuoteResponse, error) {
+
+	if request.Quote == nil {
+		return nil, errors.New("quote not set on request")
+	}
+
+	if request.Check == nil {
+		return nil, errors.New("check not set on request")
+	}
+
+	margin := request.SafetyMargin
+	if margin <= 0 {
+		margin = defaultQuoteSafetyMargin
+	}
+
+	if request.MaxClockSkew == 0 {
+		margin += defaultQuoteClockSkew
+	} else if request.MaxClockSkew > 0 {
+		margin += request.MaxClockSkew
+	}
+
+	created, err := s.CreateQuoteRequest(ctx, request.Quote)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create quote request: %w", err)
+	}
+
+	quote := created.Quote
+
+	response := &RequestAndAcceptQuoteResponse{Quote: &quote, Request: request}
+
+	deadline := quote.ExpirationTime.Add(-margin)
+	if !time.Now().Before(deadline) {
+		return response, ErrQuoteExpired
+	}
+
+	windowCtx, cancel := context.WithDeadline(ctx, deadline)
+	defer cancel()
+
+	ok, err := request.Check(windowCtx, &quote)
+	if err != nil {
+		return response, fmt.Errorf("unable to check quote: %s - err: %w", quote.QuoteId, err)
+	}
+
+	if !ok {
+		return response, ErrQuoteRejected
+	}
+
+	if windowCtx.Err() != nil || !time.Now().Before(deadline) {
+		return response, ErrQuoteExpired
+	}
+
+	accepted, err := s.AcceptQuote(ctx, &AcceptQuoteRequest{
+		PortfolioId:   request.Quote.PortfolioId,
+		ProductId:     request.Quote.ProductId,
+		Side:          request.Quote.Side,
+		ClientOrderId: request.ClientOrderId,
+		QuoteId:       quote.QuoteId,
+	})
+	if err != nil {
+		return response, fmt.Errorf("unable to accept quote: %s - err: %w", quote.QuoteId, err)
+	}
+
+	response.OrderId = accepted.OrderId
+
+	return response, nil
+}
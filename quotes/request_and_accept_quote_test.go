@@ -0,0 +1,126 @@
+/**
+ * Copyright 2024-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package quotes
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/coinbase-samples/prime-sdk-go/client"
+	"github.com/coinbase-samples/prime-sdk-go/credentials"
+	"github.com/coinbase-samples/prime-sdk-go/model"
+)
+
+func TestRequestAndAcceptQuote(t *testing.T) {
+
+	cases := []struct {
+		description string
+		validFor    time.Duration
+		check       QuoteCheck
+		acceptDelay time.Duration
+		expectedErr error
+	}{
+		{
+			description: "TestRequestAndAcceptQuote0",
+			validFor:    5 * time.Second,
+			check:       func(ctx context.Context, q *model.Quote) (bool, error) { return true, nil },
+		},
+		{
+			description: "TestRequestAndAcceptQuote1",
+			validFor:    5 * time.Second,
+			check:       func(ctx context.Context, q *model.Quote) (bool, error) { return false, nil },
+			expectedErr: ErrQuoteRejected,
+		},
+		{
+			description: "TestRequestAndAcceptQuote2",
+			validFor:    100 * time.Millisecond,
+			check:       func(ctx context.Context, q *model.Quote) (bool, error) { return true, nil },
+			expectedErr: ErrQuoteExpired,
+		},
+		{
+			description: "TestRequestAndAcceptQuote3",
+			validFor:    time.Second,
+			check: func(ctx context.Context, q *model.Quote) (bool, error) {
+				<-ctx.Done()
+				return true, nil
+			},
+			expectedErr: ErrQuoteExpired,
+		},
+		{
+			description: "TestRequestAndAcceptQuote4",
+			validFor:    time.Second,
+			check:       func(ctx context.Context, q *model.Quote) (bool, error) { return true, nil },
+			acceptDelay: 500 * time.Millisecond,
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.description, func(t *testing.T) {
+
+			var accepted bool
+
+			mux := http.NewServeMux()
+
+			mux.HandleFunc("/portfolios/p1/rfq", func(w http.ResponseWriter, r *http.Request) {
+				json.NewEncoder(w).Encode(model.Quote{
+					QuoteId:        "q1",
+					BestPrice:      "100",
+					ExpirationTime: time.Now().Add(tt.validFor),
+				})
+			})
+
+			mux.HandleFunc("/portfolios/p1/accept_quote", func(w http.ResponseWriter, r *http.Request) {
+				time.Sleep(tt.acceptDelay)
+				accepted = true
+				json.NewEncoder(w).Encode(map[string]string{"order_id": "o1"})
+			})
+
+			server := httptest.NewServer(mux)
+			defer server.Close()
+
+			svc := NewQuotesService(client.NewRestClient(&credentials.Credentials{}, http.Client{}).SetBaseUrl(server.URL))
+
+			response, err := svc.RequestAndAcceptQuote(context.Background(), &RequestAndAcceptQuoteRequest{
+				Quote: &CreateQuoteRequest{
+					PortfolioId:  "p1",
+					ProductId:    "BTC-USD",
+					Side:         model.OrderSideBuy,
+					LimitPrice:   "101",
+					BaseQuantity: "1",
+				},
+				Check: tt.check,
+			})
+
+			if !errors.Is(err, tt.expectedErr) {
+				t.Fatalf("test: %s - expected err: %v - received: %v", tt.description, tt.expectedErr, err)
+			}
+
+			if response.Quote.QuoteId != "q1" {
+				t.Errorf("test: %s - expected the quote in the response", tt.description)
+			}
+
+			if accepted != (tt.expectedErr == nil) || response.Accepted() != accepted {
+				t.Errorf("test: %s - unexpected accept: %v", tt.description, accepted)
+			}
+		})
+	}
+}
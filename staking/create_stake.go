@@ -0,0 +1,89 @@
+/**
+ * Copyright 2024-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package staking
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/coinbase-samples/core-go"
+	"github.com/coinbase-samples/prime-sdk-go/client"
+	"github.com/coinbase-samples/prime-sdk-go/idempotency"
+)
+
+type StakingInputs struct {
+	// The amount to stake or unstake. If empty, the whole bondable amount is
+	// staked or the whole bonded amount is unstaked.
+	Amount string `json:"amount,omitempty"`
+}
+
+type CreateStakeRequest struct {
+	PortfolioId    string         `json:"portfolio_id"`
+	WalletId       string         `json:"wallet_id"`
+	IdempotencyKey string         `json:"idempotency_key"`
+	Inputs         *StakingInputs `json:"inputs,omitempty"`
+
+	// Reference is an optional business reference. When IdempotencyKey is
+	// empty, the key is derived from the reference so a replayed request is
+	// not executed twice. If neither is set, a new key is generated.
+	Reference string `json:"-"`
+}
+
+// The activity id tracks the approval of the request through
+// activities.GetActivity.
+type CreateStakeResponse struct {
+	WalletId       string              `json:"wallet_id"`
+	TransactionId  string              `json:"transaction_id"`
+	ActivityId     string              `json:"activity_id"`
+	IdempotencyKey string              `json:"idempotency_key"`
+	Request        *CreateStakeRequest `json:"request"`
+}
+
+// CreateStake initiates a stake of a vault wallet's bondable balance.
+func (s *stakingServiceImpl) CreateStake(
+	ctx context.Context,
+	request *CreateStakeRequest,
+) (*CreateStakeResponse, error) {
+
+	path := fmt.Sprintf("/portfolios/%s/wallets/%s/staking/initiate", request.PortfolioId, request.WalletId)
+
+	key, err := idempotency.ResolveKey(s.keyStore, operationStake, request.IdempotencyKey, request.Reference)
+	if err != nil {
+		return nil, fmt.Errorf("unable to resolve idempotency key: %w", err)
+	}
+
+	keyed := *request
+	keyed.IdempotencyKey = key
+	request = &keyed
+
+	response := &CreateStakeResponse{IdempotencyKey: key, Request: request}
+
+	if err := core.HttpPost(
+		ctx,
+		s.client,
+		path,
+		core.EmptyQueryParams,
+		client.DefaultSuccessHttpStatusCodes,
+		request,
+		response,
+		s.client.HeadersFunc(),
+	); err != nil {
+		return nil, err
+	}
+
+	return response, nil
+}
@@ -0,0 +1,107 @@
+/**
+ * Copyright 2024-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package staking
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/coinbase-samples/prime-sdk-go/client"
+	"github.com/coinbase-samples/prime-sdk-go/credentials"
+)
+
+func TestCreateStake(t *testing.T) {
+
+	var keys []string
+
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("/portfolios/p1/wallets/w1/staking/initiate", func(w http.ResponseWriter, r *http.Request) {
+		body := make(map[string]interface{})
+		json.NewDecoder(r.Body).Decode(&body)
+
+		keys = append(keys, body["idempotency_key"].(string))
+
+		inputs, _ := body["inputs"].(map[string]interface{})
+		if inputs["amount"] != "32" {
+			t.Errorf("expected amount: 32 - received: %v", inputs["amount"])
+		}
+
+		json.NewEncoder(w).Encode(map[string]interface{}{"wallet_id": "w1", "transaction_id": "t1", "activity_id": "a1"})
+	})
+
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	svc := NewStakingServiceWithKeyStore(client.NewRestClient(&credentials.Credentials{}, http.Client{}).SetBaseUrl(server.URL), nil)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	cases := []struct {
+		description string
+		reference   string
+	}{
+		{
+			description: "TestCreateStake0",
+			reference:   "stake-2024-01",
+		},
+		{
+			description: "TestCreateStake1",
+			reference:   "stake-2024-01",
+		},
+		{
+			description: "TestCreateStake2",
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.description, func(t *testing.T) {
+			request := &CreateStakeRequest{
+				PortfolioId: "p1",
+				WalletId:    "w1",
+				Inputs:      &StakingInputs{Amount: "32"},
+				Reference:   tt.reference,
+			}
+
+			response, err := svc.CreateStake(ctx, request)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if len(request.IdempotencyKey) > 0 {
+				t.Errorf("test: %s - expected the request not to be modified - received: %s", tt.description, request.IdempotencyKey)
+			}
+
+			if response.ActivityId != "a1" || response.IdempotencyKey != keys[len(keys)-1] {
+				t.Errorf("test: %s - unexpected response: %+v", tt.description, response)
+			}
+		})
+	}
+
+	if keys[0] != keys[1] {
+		t.Errorf("expected the same key for the same reference - received: %s, %s", keys[0], keys[1])
+	}
+
+	if keys[2] == keys[0] {
+		t.Errorf("expected a new key without a reference")
+	}
+}
@@ -0,0 +1,66 @@
+/**
+ * Copyright 2024-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package staking
+
+import (
+	"context"
+
+	"github.com/coinbase-samples/prime-sdk-go/balances"
+	"github.com/coinbase-samples/prime-sdk-go/model"
+)
+
+type GetStakingStatusRequest struct {
+	PortfolioId string `json:"portfolio_id"`
+	WalletId    string `json:"wallet_id"`
+}
+
+// GetStakingStatusResponse holds the staking amounts of the wallet balance:
+// bonded, unbonding, bondable and rewards.
+type GetStakingStatusResponse struct {
+	Balance *model.Balance           `json:"balance"`
+	Request *GetStakingStatusRequest `json:"request"`
+}
+
+// Unbonding returns true if an unstake is in progress.
+func (r GetStakingStatusResponse) Unbonding() (bool, error) {
+	if r.Balance == nil || len(r.Balance.UnbondingAmount) == 0 {
+		return false, nil
+	}
+	amount, err := r.Balance.UnbondingAmountNum()
+	if err != nil {
+		return false, err
+	}
+	return amount.IsPositive(), nil
+}
+
+// GetStakingStatus reads the wallet balance through
+// balances.GetWalletBalance, which carries the staking amounts.
+func (s *stakingServiceImpl) GetStakingStatus(
+	ctx context.Context,
+	request *GetStakingStatusRequest,
+) (*GetStakingStatusResponse, error) {
+
+	balance, err := s.balances.GetWalletBalance(ctx, &balances.GetWalletBalanceRequest{
+		PortfolioId: request.PortfolioId,
+		Id:          request.WalletId,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &GetStakingStatusResponse{Balance: balance.Balance, Request: request}, nil
+}
@@ -0,0 +1,56 @@
+/**
+ * Copyright 2024-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package staking
+
+import (
+	"context"
+
+	"github.com/coinbase-samples/prime-sdk-go/balances"
+	"github.com/coinbase-samples/prime-sdk-go/client"
+	"github.com/coinbase-samples/prime-sdk-go/idempotency"
+)
+
+type StakingService interface {
+	CreateStake(ctx context.Context, request *CreateStakeRequest) (*CreateStakeResponse, error)
+	CreateUnstake(ctx context.Context, request *CreateUnstakeRequest) (*CreateUnstakeResponse, error)
+	GetStakingStatus(ctx context.Context, request *GetStakingStatusRequest) (*GetStakingStatusResponse, error)
+}
+
+func NewStakingService(c client.RestClient) StakingService {
+	return NewStakingServiceWithKeyStore(c, nil)
+}
+
+// NewStakingServiceWithKeyStore returns a service that resolves the
+// idempotency keys of requests with a Reference through the key store. A nil
+// store defaults to idempotency.NewDerivedKeyStore.
+func NewStakingServiceWithKeyStore(c client.RestClient, keyStore idempotency.KeyStore) StakingService {
+	if keyStore == nil {
+		keyStore = idempotency.NewDerivedKeyStore(idempotency.DefaultNamespace)
+	}
+	return &stakingServiceImpl{client: c, keyStore: keyStore, balances: balances.NewBalancesService(c)}
+}
+
+type stakingServiceImpl struct {
+	client   client.RestClient
+	keyStore idempotency.KeyStore
+	balances balances.BalancesService
+}
+
+const (
+	operationStake   = "stake"
+	operationUnstake = "unstake"
+)
@@ -0,0 +1,385 @@
+/**
+ * Copyright 2024-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package sweep
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/coinbase-samples/prime-sdk-go/balances"
+	"github.com/coinbase-samples/prime-sdk-go/model"
+	"github.com/coinbase-samples/prime-sdk-go/transactions"
+	"github.com/coinbase-samples/prime-sdk-go/wallets"
+	"github.com/shopspring/decimal"
+)
+
+type Mode int
+
+const (
+	// DryRun plans the transfers without sending any request
+	DryRun Mode = iota
+
+	// Apply creates the planned transfers
+	Apply
+)
+
+const (
+	DirectionToVault   = "TO_VAULT"
+	DirectionToTrading = "TO_TRADING"
+)
+
+type Plan struct {
+	PortfolioId string `json:"portfolio_id"`
+
+	// Identifies the sweep run. Together with the portfolio, symbol,
+	// direction and amount, it is the reference idempotency keys are derived
+	// from, so replaying a run does not move funds twice. Use a new run id
+	// for each sweep and reuse it only to replay that sweep.
+	RunId string `json:"run_id"`
+
+	Transfers []*Transfer `json:"transfers"`
+
+	// Rules that did not result in a transfer, and why
+	Skipped []*Skipped `json:"skipped"`
+
+	Created time.Time `json:"created_at"`
+}
+
+// Transfer is a planned movement between the trading and vault wallets of an
+// asset.
+type Transfer struct {
+	Symbol              string          `json:"symbol"`
+	Direction           string          `json:"direction"`
+	SourceWalletId      string          `json:"source_wallet_id"`
+	DestinationWalletId string          `json:"destination_wallet_id"`
+	Amount              decimal.Decimal `json:"amount"`
+
+	// The balances the transfer was planned from
+	TradingBalance decimal.Decimal `json:"trading_balance"`
+	VaultBalance   decimal.Decimal `json:"vault_balance"`
+
+	Reason    string `json:"reason"`
+	Reference string `json:"reference"`
+}
+
+type Skipped struct {
+	Symbol string `json:"symbol"`
+	Reason string `json:"reason"`
+}
+
+type Report struct {
+	Plan     *Plan             `json:"plan"`
+	Mode     Mode              `json:"mode"`
+	Results  []*TransferResult `json:"results"`
+	Started  time.Time         `json:"started_at"`
+	Finished time.Time         `json:"finished_at"`
+}
+
+type TransferResult struct {
+	Transfer       *Transfer `json:"transfer"`
+	ActivityId     string    `json:"activity_id"`
+	TransactionId  string    `json:"transaction_id"`
+	IdempotencyKey string    `json:"idempotency_key"`
+	Error          string    `json:"error,omitempty"`
+	Err            error     `json:"-"`
+}
+
+// Failed returns the results of transfers that could not be created.
+func (r Report) Failed() []*TransferResult {
+	var failed []*TransferResult
+	for _, res := range r.Results {
+		if res.Err != nil {
+			failed = append(failed, res)
+		}
+	}
+	return failed
+}
+
+// WriteAudit writes the report, including the plan and the balances it was
+// based on, as indented JSON.
+func (r Report) WriteAudit(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(r)
+}
+
+// Engine plans and executes sweeps between the trading and vault wallets of a
+// portfolio.
+type Engine struct {
+	balances     balances.BalancesService
+	wallets      wallets.WalletsService
+	transactions transactions.TransactionsService
+}
+
+// NewEngine returns a sweep engine. Transfer idempotency keys are resolved
+// from the transfer references by the key store of the transactions service;
+// the default store derives them, so a run replayed after a restart is sent
+// with the same keys.
+func NewEngine(
+	b balances.BalancesService,
+	w wallets.WalletsService,
+	t transactions.TransactionsService,
+) *Engine {
+	return &Engine{balances: b, wallets: w, transactions: t}
+}
+
+// Plan evaluates the rules against the current trading and vault balances.
+// Top ups are capped by the vault balance. The run id is required, see
+// Plan.RunId.
+func (e *Engine) Plan(ctx context.Context, portfolioId, runId string, rules []*Rule) (*Plan, error) {
+
+	if len(portfolioId) == 0 {
+		return nil, errors.New("portfolio id not set")
+	}
+
+	if len(runId) == 0 {
+		return nil, errors.New("run id not set")
+	}
+
+	if err := validateRules(rules); err != nil {
+		return nil, err
+	}
+
+	plan := &Plan{PortfolioId: portfolioId, RunId: runId, Created: time.Now().UTC()}
+
+	if len(rules) == 0 {
+		return plan, nil
+	}
+
+	symbols := make([]string, 0, len(rules))
+	for _, r := range rules {
+		symbols = append(symbols, r.Symbol)
+	}
+
+	trading, err := e.listBalances(ctx, portfolioId, model.BalanceTypeTrading, symbols)
+	if err != nil {
+		return nil, err
+	}
+
+	vault, err := e.listBalances(ctx, portfolioId, model.BalanceTypeVault, symbols)
+	if err != nil {
+		return nil, err
+	}
+
+	tradingWallets, err := e.listWallets(ctx, portfolioId, model.WalletTypeTrading, symbols)
+	if err != nil {
+		return nil, err
+	}
+
+	vaultWallets, err := e.listWallets(ctx, portfolioId, model.WalletTypeVault, symbols)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, r := range rules {
+
+		symbol := strings.ToUpper(r.Symbol)
+
+		t := &Transfer{
+			Symbol:         symbol,
+			TradingBalance: trading[symbol],
+			VaultBalance:   vault[symbol],
+		}
+
+		switch {
+		case t.TradingBalance.GreaterThan(r.sweepAbove()):
+			t.Direction = DirectionToVault
+			t.Amount = t.TradingBalance.Sub(r.Keep)
+			t.Reason = fmt.Sprintf("trading balance %s above %s", t.TradingBalance, r.sweepAbove())
+		case t.TradingBalance.LessThan(r.TopUpBelow):
+			t.Direction = DirectionToTrading
+			t.Amount = decimal.Min(r.Keep.Sub(t.TradingBalance), t.VaultBalance)
+			t.Reason = fmt.Sprintf("trading balance %s below %s", t.TradingBalance, r.TopUpBelow)
+		default:
+			plan.Skipped = append(plan.Skipped, &Skipped{Symbol: symbol, Reason: "within thresholds"})
+			continue
+		}
+
+		if !t.Amount.IsPositive() || t.Amount.LessThan(r.MinTransfer) {
+			plan.Skipped = append(plan.Skipped, &Skipped{
+				Symbol: symbol,
+				Reason: fmt.Sprintf("%s - transfer of %s below the minimum of %s", t.Reason, t.Amount, r.MinTransfer),
+			})
+			continue
+		}
+
+		tradingWalletId, err := resolveWallet(r.TradingWalletId, tradingWallets[symbol], model.WalletTypeTrading, symbol)
+		if err != nil {
+			plan.Skipped = append(plan.Skipped, &Skipped{Symbol: symbol, Reason: err.Error()})
+			continue
+		}
+
+		vaultWalletId, err := resolveWallet(r.VaultWalletId, vaultWallets[symbol], model.WalletTypeVault, symbol)
+		if err != nil {
+			plan.Skipped = append(plan.Skipped, &Skipped{Symbol: symbol, Reason: err.Error()})
+			continue
+		}
+
+		if t.Direction == DirectionToVault {
+			t.SourceWalletId, t.DestinationWalletId = tradingWalletId, vaultWalletId
+		} else {
+			t.SourceWalletId, t.DestinationWalletId = vaultWalletId, tradingWalletId
+		}
+
+		t.Reference = fmt.Sprintf("sweep/%s/%s/%s/%s/%s", portfolioId, runId, symbol, t.Direction, t.Amount)
+
+		plan.Transfers = append(plan.Transfers, t)
+	}
+
+	return plan, nil
+}
+
+// Apply creates the transfers in the plan, unless the mode is DryRun. A
+// failure to create one transfer does not stop the others.
+func (e *Engine) Apply(ctx context.Context, plan *Plan, mode Mode) *Report {
+
+	report := &Report{Plan: plan, Mode: mode, Started: time.Now().UTC()}
+
+	if mode == Apply {
+		for _, t := range plan.Transfers {
+
+			result := &TransferResult{Transfer: t}
+
+			response, err := e.transactions.CreateWalletTransfer(ctx, &transactions.CreateWalletTransferRequest{
+				PortfolioId:         plan.PortfolioId,
+				SourceWalletId:      t.SourceWalletId,
+				DestinationWalletId: t.DestinationWalletId,
+				Symbol:              t.Symbol,
+				Amount:              t.Amount.String(),
+				Reference:           t.Reference,
+			})
+			if err != nil {
+				result.Err = err
+				result.Error = err.Error()
+			} else {
+				result.ActivityId = response.ActivityId
+				result.TransactionId = response.TransactionId
+				result.IdempotencyKey = response.IdempotencyKey
+			}
+
+			report.Results = append(report.Results, result)
+		}
+	}
+
+	report.Finished = time.Now().UTC()
+
+	return report
+}
+
+// Run plans and applies the rules.
+func (e *Engine) Run(ctx context.Context, portfolioId, runId string, rules []*Rule, mode Mode) (*Report, error) {
+
+	plan, err := e.Plan(ctx, portfolioId, runId, rules)
+	if err != nil {
+		return nil, err
+	}
+
+	return e.Apply(ctx, plan, mode), nil
+}
+
+// listBalances returns the available balance, i.e., the amount less holds,
+// per symbol.
+func (e *Engine) listBalances(ctx context.Context, portfolioId, balanceType string, symbols []string) (map[string]decimal.Decimal, error) {
+
+	response, err := e.balances.ListPortfolioBalances(ctx, &balances.ListPortfolioBalancesRequest{
+		PortfolioId: portfolioId,
+		Type:        balanceType,
+		Symbols:     symbols,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("unable to list balances - type: %s - err: %w", balanceType, err)
+	}
+
+	available := make(map[string]decimal.Decimal)
+
+	for _, b := range response.Balances {
+
+		amount, err := b.AmountNum()
+		if err != nil {
+			return nil, err
+		}
+
+		if len(b.Holds) > 0 {
+			holds, err := b.HoldsNum()
+			if err != nil {
+				return nil, err
+			}
+			amount = amount.Sub(holds)
+		}
+
+		symbol := strings.ToUpper(b.Symbol)
+		available[symbol] = available[symbol].Add(amount)
+	}
+
+	return available, nil
+}
+
+func (e *Engine) listWallets(ctx context.Context, portfolioId, walletType string, symbols []string) (map[string][]*model.Wallet, error) {
+
+	bySymbol := make(map[string][]*model.Wallet)
+
+	pagination := &model.PaginationParams{}
+
+	for {
+		response, err := e.wallets.ListWallets(ctx, &wallets.ListWalletsRequest{
+			PortfolioId: portfolioId,
+			Type:        walletType,
+			Symbols:     symbols,
+			Pagination:  pagination,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("unable to list wallets - type: %s - err: %w", walletType, err)
+		}
+
+		for _, w := range response.Wallets {
+			symbol := strings.ToUpper(w.Symbol)
+			bySymbol[symbol] = append(bySymbol[symbol], w)
+		}
+
+		if !response.HasNext() {
+			return bySymbol, nil
+		}
+
+		pagination = &model.PaginationParams{Cursor: response.Pagination.NextCursor}
+	}
+}
+
+func resolveWallet(walletId string, candidates []*model.Wallet, walletType, symbol string) (string, error) {
+
+	if len(walletId) > 0 {
+		for _, w := range candidates {
+			if w.Id == walletId {
+				return walletId, nil
+			}
+		}
+		return "", fmt.Errorf("%s wallet not found: %s", strings.ToLower(walletType), walletId)
+	}
+
+	switch len(candidates) {
+	case 0:
+		return "", fmt.Errorf("no %s wallet", strings.ToLower(walletType))
+	case 1:
+		return candidates[0].Id, nil
+	}
+
+	return "", fmt.Errorf("%d %s wallets - set the wallet id on the rule", len(candidates), strings.ToLower(walletType))
+}
@@ -0,0 +1,322 @@
+/**
+ * Copyright 2024-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package sweep
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/coinbase-samples/prime-sdk-go/balances"
+	"github.com/coinbase-samples/prime-sdk-go/client"
+	"github.com/coinbase-samples/prime-sdk-go/credentials"
+	"github.com/coinbase-samples/prime-sdk-go/transactions"
+	"github.com/coinbase-samples/prime-sdk-go/wallets"
+)
+
+const testRules = `
+- symbol: USDC
+  keep: "250000"
+  sweep_above: "300000"
+  top_up_below: "100000"
+- symbol: ETH
+  keep: "50"
+  top_up_below: "20"
+- symbol: BTC
+  keep: "5"
+  min_transfer: "1"
+- symbol: SOL
+  keep: "1000"
+  sweep_above: "1500"
+  top_up_below: "500"
+`
+
+func TestRun(t *testing.T) {
+
+	rules, err := LoadRules(strings.NewReader(testRules))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		description string
+		mode        Mode
+		sent        int
+	}{
+		{
+			description: "TestRun0",
+			mode:        DryRun,
+		},
+		{
+			description: "TestRun1",
+			mode:        Apply,
+			sent:        2,
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.description, func(t *testing.T) {
+
+			var transfers []*transactions.CreateWalletTransferRequest
+
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				q := r.URL.Query()
+				switch {
+				case r.Method == http.MethodPost:
+					request := &transactions.CreateWalletTransferRequest{}
+					if err := json.NewDecoder(r.Body).Decode(request); err != nil {
+						t.Error(err)
+					}
+					transfers = append(transfers, request)
+					w.Write([]byte(`{"activity_id":"a1","transaction_id":"t1"}`))
+				case r.URL.Path == "/portfolios/p1/balances" && q.Get("balance_type") == "TRADING_BALANCES":
+					w.Write([]byte(`{"balances":[
+						{"symbol":"USDC","amount":"420000","holds":"20000"},
+						{"symbol":"ETH","amount":"12","holds":"0"},
+						{"symbol":"BTC","amount":"5.4","holds":"0"},
+						{"symbol":"SOL","amount":"1200","holds":"0"}
+					]}`))
+				case r.URL.Path == "/portfolios/p1/balances":
+					w.Write([]byte(`{"balances":[
+						{"symbol":"USDC","amount":"5000000","holds":"0"},
+						{"symbol":"ETH","amount":"30","holds":"0"},
+						{"symbol":"BTC","amount":"100","holds":"0"}
+					]}`))
+				case r.URL.Path == "/portfolios/p1/wallets" && q.Get("type") == "TRADING":
+					w.Write([]byte(`{"wallets":[
+						{"id":"tw-usdc","type":"TRADING","symbol":"USDC"},
+						{"id":"tw-eth","type":"TRADING","symbol":"ETH"},
+						{"id":"tw-btc","type":"TRADING","symbol":"BTC"}
+					]}`))
+				case r.URL.Path == "/portfolios/p1/wallets":
+					w.Write([]byte(`{"wallets":[
+						{"id":"vw-usdc","type":"VAULT","symbol":"USDC"},
+						{"id":"vw-eth","type":"VAULT","symbol":"ETH"},
+						{"id":"vw-btc-1","type":"VAULT","symbol":"BTC"},
+						{"id":"vw-btc-2","type":"VAULT","symbol":"BTC"}
+					]}`))
+				default:
+					t.Errorf("test: %s - unexpected request: %s %s", tt.description, r.Method, r.URL)
+				}
+			}))
+			defer server.Close()
+
+			c := client.NewRestClient(&credentials.Credentials{}, http.Client{}).SetBaseUrl(server.URL)
+
+			engine := NewEngine(
+				balances.NewBalancesService(c),
+				wallets.NewWalletsService(c),
+				transactions.NewTransactionsService(c),
+			)
+
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+
+			report, err := engine.Run(ctx, "p1", "2024-06-01", rules, tt.mode)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			plan := report.Plan
+
+			if len(plan.Transfers) != 2 {
+				t.Fatalf("test: %s - expected: 2 transfers - received: %d", tt.description, len(plan.Transfers))
+			}
+
+			usdc, eth := plan.Transfers[0], plan.Transfers[1]
+
+			if usdc.Direction != DirectionToVault || usdc.Amount.String() != "150000" || usdc.SourceWalletId != "tw-usdc" || usdc.DestinationWalletId != "vw-usdc" {
+				t.Errorf("test: %s - unexpected USDC transfer: %+v", tt.description, usdc)
+			}
+
+			if eth.Direction != DirectionToTrading || eth.Amount.String() != "30" || eth.SourceWalletId != "vw-eth" {
+				t.Errorf("test: %s - unexpected ETH transfer: %+v", tt.description, eth)
+			}
+
+			if usdc.Reference != "sweep/p1/2024-06-01/USDC/TO_VAULT/150000" {
+				t.Errorf("test: %s - unexpected reference: %s", tt.description, usdc.Reference)
+			}
+
+			// BTC needs more than one vault wallet resolved and SOL has no
+			// balances or wallets
+			if len(plan.Skipped) != 2 {
+				t.Errorf("test: %s - expected: 2 skipped - received: %d", tt.description, len(plan.Skipped))
+			}
+
+			if len(transfers) != tt.sent || len(report.Results) != tt.sent || len(report.Failed()) != 0 {
+				t.Errorf("test: %s - expected: %d transfers sent - received: %d", tt.description, tt.sent, len(transfers))
+			}
+
+			if tt.sent > 0 && (len(transfers[0].IdempotencyKey) == 0 || report.Results[0].IdempotencyKey != transfers[0].IdempotencyKey) {
+				t.Errorf("test: %s - expected the idempotency key in the audit report", tt.description)
+			}
+
+			var audit bytes.Buffer
+			if err := report.WriteAudit(&audit); err != nil {
+				t.Fatal(err)
+			}
+
+			if !strings.Contains(audit.String(), `"trading_balance": "400000"`) {
+				t.Errorf("test: %s - expected the planned balances in the audit report", tt.description)
+			}
+		})
+	}
+}
+
+func TestReplan(t *testing.T) {
+
+	rules, err := LoadRules(strings.NewReader("- {symbol: USDC, keep: '250000', sweep_above: '300000'}"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var keys []string
+	trading := "420000"
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodPost:
+			request := &transactions.CreateWalletTransferRequest{}
+			if err := json.NewDecoder(r.Body).Decode(request); err != nil {
+				t.Error(err)
+			}
+			keys = append(keys, request.IdempotencyKey)
+			w.Write([]byte(`{"activity_id":"a1","transaction_id":"t1"}`))
+		case r.URL.Path == "/portfolios/p1/balances" && r.URL.Query().Get("balance_type") == "TRADING_BALANCES":
+			w.Write([]byte(`{"balances":[{"symbol":"USDC","amount":"` + trading + `","holds":"0"}]}`))
+		case r.URL.Path == "/portfolios/p1/balances":
+			w.Write([]byte(`{"balances":[{"symbol":"USDC","amount":"0","holds":"0"}]}`))
+		case r.URL.Path == "/portfolios/p1/wallets" && r.URL.Query().Get("type") == "TRADING":
+			w.Write([]byte(`{"wallets":[{"id":"tw-usdc","type":"TRADING","symbol":"USDC"}]}`))
+		case r.URL.Path == "/portfolios/p1/wallets":
+			w.Write([]byte(`{"wallets":[{"id":"vw-usdc","type":"VAULT","symbol":"USDC"}]}`))
+		}
+	}))
+	defer server.Close()
+
+	c := client.NewRestClient(&credentials.Credentials{}, http.Client{}).SetBaseUrl(server.URL)
+
+	engine := NewEngine(
+		balances.NewBalancesService(c),
+		wallets.NewWalletsService(c),
+		transactions.NewTransactionsService(c),
+	)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	cases := []struct {
+		description string
+		runId       string
+		trading     string
+		reused      bool
+	}{
+		{
+			description: "TestReplan0",
+			runId:       "2024-06-01T09",
+			trading:     "420000",
+		},
+		{
+			description: "TestReplan1",
+			runId:       "2024-06-01T09",
+			trading:     "420000",
+			reused:      true,
+		},
+		{
+			description: "TestReplan2",
+			runId:       "2024-06-01T15",
+			trading:     "420000",
+		},
+		{
+			description: "TestReplan3",
+			runId:       "2024-06-01T15",
+			trading:     "380000",
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.description, func(t *testing.T) {
+
+			trading = tt.trading
+
+			if _, err := engine.Run(ctx, "p1", tt.runId, rules, Apply); err != nil {
+				t.Fatal(err)
+			}
+
+			last := keys[len(keys)-1]
+
+			var reused bool
+			for _, k := range keys[:len(keys)-1] {
+				reused = reused || k == last
+			}
+
+			if reused != tt.reused {
+				t.Errorf("test: %s - expected key reused: %v - received: %v", tt.description, tt.reused, reused)
+			}
+		})
+	}
+
+	if _, err := engine.Plan(ctx, "p1", "", rules); err == nil {
+		t.Error("expected an error without a run id")
+	}
+
+	if _, err := engine.Plan(ctx, "p1", "run", []*Rule{nil}); err == nil {
+		t.Error("expected an error for a nil rule")
+	}
+}
+
+func TestLoadRules(t *testing.T) {
+
+	cases := []struct {
+		description string
+		rules       string
+	}{
+		{
+			description: "TestLoadRules0",
+			rules:       "- {symbol: ETH, keep: '10', sweep_above: '5'}",
+		},
+		{
+			description: "TestLoadRules1",
+			rules:       "- {symbol: ETH, keep: '10', top_up_below: '20'}",
+		},
+		{
+			description: "TestLoadRules2",
+			rules:       "- {symbol: ETH, keep: '10'}\n- {symbol: eth, keep: '20'}",
+		},
+		{
+			description: "TestLoadRules3",
+			rules:       "- {symbol: ETH, keep: ten}",
+		},
+		{
+			description: "TestLoadRules4",
+			rules:       "- {symbol: ETH, keep: '10'}\n-",
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.description, func(t *testing.T) {
+			if _, err := LoadRules(strings.NewReader(tt.rules)); err == nil {
+				t.Errorf("test: %s - expected an error", tt.description)
+			}
+		})
+	}
+}
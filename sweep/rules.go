@@ -0,0 +1,133 @@
+/**
+ * Copyright 2024-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package sweep
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/shopspring/decimal"
+	"gopkg.in/yaml.v3"
+)
+
+// Rule keeps the trading wallet balance of an asset around a target: the
+// excess above SweepAbove is moved to the vault and, when the balance falls
+// below TopUpBelow, the vault tops it up. Both bring the balance back to
+// Keep.
+type Rule struct {
+	Symbol string `json:"symbol" yaml:"symbol"`
+
+	// The trading balance to leave after a sweep or top up
+	Keep decimal.Decimal `json:"keep" yaml:"keep"`
+
+	// Sweep to the vault when the trading balance is above this. Defaults
+	// to Keep.
+	SweepAbove decimal.Decimal `json:"sweep_above" yaml:"sweep_above"`
+
+	// Top up from the vault when the trading balance is below this. Zero
+	// disables top ups.
+	TopUpBelow decimal.Decimal `json:"top_up_below" yaml:"top_up_below"`
+
+	// Transfers smaller than this are skipped
+	MinTransfer decimal.Decimal `json:"min_transfer" yaml:"min_transfer"`
+
+	// The wallets to use. If empty, the portfolio must have exactly one
+	// wallet of the type for the symbol.
+	TradingWalletId string `json:"trading_wallet_id" yaml:"trading_wallet_id"`
+	VaultWalletId   string `json:"vault_wallet_id" yaml:"vault_wallet_id"`
+}
+
+func (r Rule) sweepAbove() decimal.Decimal {
+	if r.SweepAbove.IsZero() {
+		return r.Keep
+	}
+	return r.SweepAbove
+}
+
+// Validate checks that the thresholds are consistent: TopUpBelow <= Keep <=
+// SweepAbove.
+func (r Rule) Validate() error {
+
+	if len(r.Symbol) == 0 {
+		return errors.New("symbol not set")
+	}
+
+	if r.Keep.IsNegative() || r.TopUpBelow.IsNegative() || r.MinTransfer.IsNegative() {
+		return fmt.Errorf("negative threshold - symbol: %s", r.Symbol)
+	}
+
+	if r.sweepAbove().LessThan(r.Keep) {
+		return fmt.Errorf("sweep_above is below keep - symbol: %s", r.Symbol)
+	}
+
+	if r.TopUpBelow.GreaterThan(r.Keep) {
+		return fmt.Errorf("top_up_below is above keep - symbol: %s", r.Symbol)
+	}
+
+	return nil
+}
+
+// LoadRules reads a list of rules from YAML, e.g.:
+//
+//	# keep 250k USDC for trading, between 100k and 300k
+//	- symbol: USDC
+//	  keep: "250000"
+//	  sweep_above: "300000"
+//	  top_up_below: "100000"
+//	  min_transfer: "1000"
+func LoadRules(r io.Reader) ([]*Rule, error) {
+
+	decoder := yaml.NewDecoder(r)
+	decoder.KnownFields(true)
+
+	var rules []*Rule
+	if err := decoder.Decode(&rules); err != nil {
+		return nil, fmt.Errorf("unable to deserialize sweep rules: %w", err)
+	}
+
+	if err := validateRules(rules); err != nil {
+		return nil, err
+	}
+
+	return rules, nil
+}
+
+func validateRules(rules []*Rule) error {
+
+	seen := make(map[string]bool)
+
+	for i, r := range rules {
+
+		if r == nil {
+			return fmt.Errorf("rule %d not set", i)
+		}
+
+		if err := r.Validate(); err != nil {
+			return err
+		}
+
+		symbol := strings.ToUpper(r.Symbol)
+		if seen[symbol] {
+			return fmt.Errorf("duplicate rule - symbol: %s", r.Symbol)
+		}
+		seen[symbol] = true
+	}
+
+	return nil
+}
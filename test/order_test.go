@@ -77,6 +77,8 @@ func TestOrders(t *testing.T) {
 
 	testListOpenOrders(t, service, c.Credentials().PortfolioId, testProductId, orderId)
 
+	testListAllOpenOrders(t, service, c.Credentials().PortfolioId, orderId)
+
 	testCancelOrder(t, service, c.Credentials().PortfolioId, orderId)
 }
 
@@ -116,6 +118,36 @@ func testListOpenOrders(t *testing.T, svc orders.OrdersService, portfolioId, pro
 
 }
 
+func testListAllOpenOrders(t *testing.T, svc orders.OrdersService, portfolioId, orderId string) {
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	response, err := svc.ListAllOpenOrders(
+		ctx,
+		&orders.ListAllOpenOrdersRequest{
+			PortfolioId: portfolioId,
+		},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var found bool
+
+	for _, o := range response.Orders {
+
+		if o.Id == orderId {
+			found = true
+			break
+		}
+	}
+
+	if !found {
+		t.Error("expected to find an existing open order across all products")
+	}
+}
+
 func testCreateOrder(t *testing.T, svc orders.OrdersService, order *model.Order) string {
 
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
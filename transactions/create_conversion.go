@@ -22,6 +22,7 @@ import (
 
 	"github.com/coinbase-samples/core-go"
 	"github.com/coinbase-samples/prime-sdk-go/client"
+	"github.com/coinbase-samples/prime-sdk-go/idempotency"
 )
 
 type CreateConversionRequest struct {
@@ -32,6 +33,12 @@ type CreateConversionRequest struct {
 	DestinationSymbol   string `json:"destination_symbol"`
 	IdempotencyKey      string `json:"idempotency_key"`
 	Amount              string `json:"amount"`
+
+	// Reference is an optional business reference, e.g., a payout id. When
+	// IdempotencyKey is empty, the key is derived from the reference so a
+	// replayed request is not executed twice. If neither is set, a new key is
+	// generated.
+	Reference string `json:"-"`
 }
 
 type CreateConversionResponse struct {
@@ -41,6 +48,7 @@ type CreateConversionResponse struct {
 	Amount              string                   `json:"amount"`
 	DestinationWalletId string                   `json:"destination"`
 	SourceWalletId      string                   `json:"source"`
+	IdempotencyKey      string                   `json:"idempotency_key"`
 	Request             *CreateConversionRequest `json:"request"`
 }
 
@@ -54,7 +62,20 @@ func (s *transactionsServiceImpl) CreateConversion(
 		request.SourceWalletId,
 	)
 
-	response := &CreateConversionResponse{Request: request}
+	key, err := idempotency.ResolveKey(s.keyStore, operationConversion, request.IdempotencyKey, request.Reference)
+	if err != nil {
+		return nil, fmt.Errorf("unable to resolve idempotency key: %w", err)
+	}
+
+	keyed := *request
+	keyed.IdempotencyKey = key
+	request = &keyed
+
+	if err := client.RunRequestHooks(ctx, s.client, request); err != nil {
+		return nil, err
+	}
+
+	response := &CreateConversionResponse{IdempotencyKey: key, Request: request}
 
 	if err := core.HttpPost(
 		ctx,
@@ -66,8 +87,11 @@ func (s *transactionsServiceImpl) CreateConversion(
 		response,
 		s.client.HeadersFunc(),
 	); err != nil {
+		client.RunResponseHooks(ctx, s.client, request, err)
 		return nil, err
 	}
 
+	client.RunResponseHooks(ctx, s.client, request, nil)
+
 	return response, nil
 }
@@ -0,0 +1,79 @@
+/**
+ * Copyright 2024-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package transactions
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/coinbase-samples/core-go"
+	"github.com/coinbase-samples/prime-sdk-go/client"
+	"github.com/coinbase-samples/prime-sdk-go/model"
+)
+
+type CreateOnchainTransactionRequest struct {
+	PortfolioId string `json:"portfolio_id"`
+	WalletId    string `json:"wallet_id"`
+
+	// The unsigned transaction: hex encoded for EVM networks, base64 encoded
+	// for Solana
+	RawUnsignedTransaction string `json:"raw_unsigned_txn"`
+
+	Rpc *model.OnchainRpc `json:"rpc,omitempty"`
+
+	// Only for EVM networks
+	EvmParams *model.EvmParams `json:"evm_params,omitempty"`
+}
+
+type CreateOnchainTransactionResponse struct {
+	TransactionId string                           `json:"transaction_id"`
+	Request       *CreateOnchainTransactionRequest `json:"request"`
+}
+
+// CreateOnchainTransaction submits a raw transaction from a web3 wallet to
+// be signed by Prime and, unless Rpc.SkipBroadcast is set, broadcast.
+func (s *transactionsServiceImpl) CreateOnchainTransaction(
+	ctx context.Context,
+	request *CreateOnchainTransactionRequest,
+) (*CreateOnchainTransactionResponse, error) {
+
+	path := fmt.Sprintf("/portfolios/%s/wallets/%s/onchain_transaction", request.PortfolioId, request.WalletId)
+
+	if err := client.RunRequestHooks(ctx, s.client, request); err != nil {
+		return nil, err
+	}
+
+	response := &CreateOnchainTransactionResponse{Request: request}
+
+	if err := core.HttpPost(
+		ctx,
+		s.client,
+		path,
+		core.EmptyQueryParams,
+		client.DefaultSuccessHttpStatusCodes,
+		request,
+		response,
+		s.client.HeadersFunc(),
+	); err != nil {
+		client.RunResponseHooks(ctx, s.client, request, err)
+		return nil, err
+	}
+
+	client.RunResponseHooks(ctx, s.client, request, nil)
+
+	return response, nil
+}
@@ -22,6 +22,7 @@ import (
 
 	"github.com/coinbase-samples/core-go"
 	"github.com/coinbase-samples/prime-sdk-go/client"
+	"github.com/coinbase-samples/prime-sdk-go/idempotency"
 )
 
 type CreateWalletTransferRequest struct {
@@ -31,6 +32,12 @@ type CreateWalletTransferRequest struct {
 	DestinationWalletId string `json:"destination"`
 	IdempotencyKey      string `json:"idempotency_key"`
 	Amount              string `json:"amount"`
+
+	// Reference is an optional business reference, e.g., a payout id. When
+	// IdempotencyKey is empty, the key is derived from the reference so a
+	// replayed request is not executed twice. If neither is set, a new key is
+	// generated.
+	Reference string `json:"-"`
 }
 
 type CreateWalletTransferResponse struct {
@@ -44,6 +51,7 @@ type CreateWalletTransferResponse struct {
 	SourceAddress      string                       `json:"source_address"`
 	SourceType         string                       `json:"source_type"`
 	TransactionId      string                       `json:"transaction_id"`
+	IdempotencyKey     string                       `json:"idempotency_key"`
 	Request            *CreateWalletTransferRequest `json:"request"`
 }
 
@@ -57,7 +65,20 @@ func (s *transactionsServiceImpl) CreateWalletTransfer(
 		request.SourceWalletId,
 	)
 
-	response := &CreateWalletTransferResponse{Request: request}
+	key, err := idempotency.ResolveKey(s.keyStore, operationTransfer, request.IdempotencyKey, request.Reference)
+	if err != nil {
+		return nil, fmt.Errorf("unable to resolve idempotency key: %w", err)
+	}
+
+	keyed := *request
+	keyed.IdempotencyKey = key
+	request = &keyed
+
+	if err := client.RunRequestHooks(ctx, s.client, request); err != nil {
+		return nil, err
+	}
+
+	response := &CreateWalletTransferResponse{IdempotencyKey: key, Request: request}
 
 	if err := core.HttpPost(
 		ctx,
@@ -69,8 +90,11 @@ func (s *transactionsServiceImpl) CreateWalletTransfer(
 		response,
 		s.client.HeadersFunc(),
 	); err != nil {
+		client.RunResponseHooks(ctx, s.client, request, err)
 		return nil, err
 	}
 
+	client.RunResponseHooks(ctx, s.client, request, nil)
+
 	return response, nil
 }
@@ -22,6 +22,7 @@ import (
 
 	"github.com/coinbase-samples/core-go"
 	"github.com/coinbase-samples/prime-sdk-go/client"
+	"github.com/coinbase-samples/prime-sdk-go/idempotency"
 	"github.com/coinbase-samples/prime-sdk-go/model"
 )
 
@@ -34,6 +35,12 @@ type CreateWalletWithdrawalRequest struct {
 	Symbol            string                               `json:"currency_symbol"`
 	PaymentMethod     *CreateWalletWithdrawalPaymentMethod `json:"payment_method"`
 	BlockchainAddress *model.BlockchainAddress             `json:"blockchain_address"`
+
+	// Reference is an optional business reference, e.g., a payout id. When
+	// IdempotencyKey is empty, the key is derived from the reference so a
+	// replayed request is not executed twice. If neither is set, a new key is
+	// generated.
+	Reference string `json:"-"`
 }
 
 type CreateWalletWithdrawalPaymentMethod struct {
@@ -51,6 +58,7 @@ type CreateWalletWithdrawalResponse struct {
 	Destination     *model.BlockchainAddress       `json:"blockchain_destination"`
 	Source          *model.BlockchainAddress       `json:"blockchain_source"`
 	TransactionId   string                         `json:"transaction_id"`
+	IdempotencyKey  string                         `json:"idempotency_key"`
 	Request         *CreateWalletWithdrawalRequest `json:"request"`
 }
 
@@ -64,7 +72,20 @@ func (s *transactionsServiceImpl) CreateWalletWithdrawal(
 		request.SourceWalletId,
 	)
 
-	response := &CreateWalletWithdrawalResponse{Request: request}
+	key, err := idempotency.ResolveKey(s.keyStore, operationWithdrawal, request.IdempotencyKey, request.Reference)
+	if err != nil {
+		return nil, fmt.Errorf("unable to resolve idempotency key: %w", err)
+	}
+
+	keyed := *request
+	keyed.IdempotencyKey = key
+	request = &keyed
+
+	if err := client.RunRequestHooks(ctx, s.client, request); err != nil {
+		return nil, err
+	}
+
+	response := &CreateWalletWithdrawalResponse{IdempotencyKey: key, Request: request}
 
 	if err := core.HttpPost(
 		ctx,
@@ -76,8 +97,11 @@ func (s *transactionsServiceImpl) CreateWalletWithdrawal(
 		response,
 		s.client.HeadersFunc(),
 	); err != nil {
+		client.RunResponseHooks(ctx, s.client, request, err)
 		return nil, err
 	}
 
+	client.RunResponseHooks(ctx, s.client, request, nil)
+
 	return response, nil
 }
@@ -0,0 +1,67 @@
+/**
+ * Copyright 2024-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package transactions
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/coinbase-samples/core-go"
+	"github.com/coinbase-samples/prime-sdk-go/client"
+	"github.com/coinbase-samples/prime-sdk-go/model"
+)
+
+type GetOnchainTransactionRequest struct {
+	PortfolioId   string `json:"portfolio_id"`
+	WalletId      string `json:"wallet_id"`
+	TransactionId string `json:"transaction_id"`
+}
+
+type GetOnchainTransactionResponse struct {
+	Transaction *model.OnchainTransaction     `json:"transaction"`
+	Request     *GetOnchainTransactionRequest `json:"request"`
+}
+
+func (s *transactionsServiceImpl) GetOnchainTransaction(
+	ctx context.Context,
+	request *GetOnchainTransactionRequest,
+) (*GetOnchainTransactionResponse, error) {
+
+	path := fmt.Sprintf(
+		"/portfolios/%s/wallets/%s/onchain_transactions/%s",
+		request.PortfolioId,
+		request.WalletId,
+		request.TransactionId,
+	)
+
+	response := &GetOnchainTransactionResponse{Request: request}
+
+	if err := core.HttpGet(
+		ctx,
+		s.client,
+		path,
+		core.EmptyQueryParams,
+		client.DefaultSuccessHttpStatusCodes,
+		request,
+		response,
+		s.client.HeadersFunc(),
+	); err != nil {
+		return nil, err
+	}
+
+	return response, nil
+}
@@ -0,0 +1,124 @@
+/**
+ * Copyright 2024-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package transactions
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/coinbase-samples/prime-sdk-go/client"
+	"github.com/coinbase-samples/prime-sdk-go/credentials"
+)
+
+func TestIdempotencyKey(t *testing.T) {
+
+	var keys []string
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body := make(map[string]interface{})
+		json.NewDecoder(r.Body).Decode(&body)
+		key, _ := body["idempotency_key"].(string)
+		keys = append(keys, key)
+		json.NewEncoder(w).Encode(map[string]interface{}{"activity_id": "a1"})
+	}))
+	defer server.Close()
+
+	c := client.NewRestClient(&credentials.Credentials{}, http.Client{}).SetBaseUrl(server.URL)
+
+	// A nil key store defaults to the derived store
+	svc := NewTransactionsServiceWithKeyStore(c, nil)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	template := &CreateWalletTransferRequest{PortfolioId: "p1", SourceWalletId: "w1", Symbol: "ETH", Amount: "1"}
+
+	cases := []struct {
+		description string
+		call        func() (string, error)
+	}{
+		{
+			description: "TestIdempotencyKey0",
+			call: func() (string, error) {
+				response, err := svc.CreateWalletTransfer(ctx, template)
+				if err != nil {
+					return "", err
+				}
+				return response.IdempotencyKey, nil
+			},
+		},
+		{
+			description: "TestIdempotencyKey1",
+			call: func() (string, error) {
+				response, err := svc.CreateWalletTransfer(ctx, template)
+				if err != nil {
+					return "", err
+				}
+				return response.IdempotencyKey, nil
+			},
+		},
+		{
+			description: "TestIdempotencyKey2",
+			call: func() (string, error) {
+				response, err := svc.CreateWalletTransfer(ctx, &CreateWalletTransferRequest{PortfolioId: "p1", SourceWalletId: "w1", Reference: "payout-1"})
+				if err != nil {
+					return "", err
+				}
+				return response.IdempotencyKey, nil
+			},
+		},
+		{
+			description: "TestIdempotencyKey3",
+			call: func() (string, error) {
+				response, err := svc.CreateWalletWithdrawal(ctx, &CreateWalletWithdrawalRequest{PortfolioId: "p1", SourceWalletId: "w1", Reference: "payout-1"})
+				if err != nil {
+					return "", err
+				}
+				return response.IdempotencyKey, nil
+			},
+		},
+	}
+
+	seen := make(map[string]string)
+
+	for _, tt := range cases {
+		t.Run(tt.description, func(t *testing.T) {
+
+			key, err := tt.call()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if len(key) == 0 || keys[len(keys)-1] != key {
+				t.Errorf("test: %s - expected: %s - received: %s", tt.description, keys[len(keys)-1], key)
+			}
+
+			if previous, ok := seen[key]; ok {
+				t.Errorf("test: %s - key reused from: %s", tt.description, previous)
+			}
+			seen[key] = tt.description
+		})
+	}
+
+	if len(template.IdempotencyKey) > 0 {
+		t.Errorf("test: TestIdempotencyKey - expected the request template to be unchanged - received: %s", template.IdempotencyKey)
+	}
+}
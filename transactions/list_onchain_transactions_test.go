@@ -0,0 +1,113 @@
+/**
+ * Copyright 2024-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package transactions
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/coinbase-samples/prime-sdk-go/client"
+	"github.com/coinbase-samples/prime-sdk-go/credentials"
+	"github.com/coinbase-samples/prime-sdk-go/model"
+)
+
+func TestOnchainTransactions(t *testing.T) {
+
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("/portfolios/p1/wallets/w1/onchain_transaction", func(w http.ResponseWriter, r *http.Request) {
+		request := &CreateOnchainTransactionRequest{}
+		json.NewDecoder(r.Body).Decode(request)
+
+		if request.RawUnsignedTransaction != "0x02ef" || request.EvmParams == nil || request.EvmParams.ChainId != "1" || !request.Rpc.SkipBroadcast {
+			t.Errorf("unexpected create request: %+v", request)
+		}
+
+		json.NewEncoder(w).Encode(map[string]interface{}{"transaction_id": "t1"})
+	})
+
+	mux.HandleFunc("/portfolios/p1/wallets/w1/onchain_transactions", func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+
+		if q.Get("statuses") != model.OnchainTransactionStatusSigned || q.Get("limit") != "1" {
+			t.Errorf("unexpected query: %s", r.URL.RawQuery)
+		}
+
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"transactions": []*model.OnchainTransaction{{
+				Id:     "t1",
+				Status: model.OnchainTransactionStatusSigned,
+				EvmParams: &model.EvmParams{
+					ChainId:      "1",
+					Nonce:        "42",
+					MaxFeePerGas: "30000000000",
+				},
+			}},
+			"pagination": &model.Pagination{NextCursor: "c2", HasNext: true},
+		})
+	})
+
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	svc := NewTransactionsService(client.NewRestClient(&credentials.Credentials{}, http.Client{}).SetBaseUrl(server.URL))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	created, err := svc.CreateOnchainTransaction(ctx, &CreateOnchainTransactionRequest{
+		PortfolioId:            "p1",
+		WalletId:               "w1",
+		RawUnsignedTransaction: "0x02ef",
+		Rpc:                    &model.OnchainRpc{SkipBroadcast: true},
+		EvmParams:              &model.EvmParams{ChainId: "1"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if created.TransactionId != "t1" {
+		t.Errorf("expected transaction id: t1 - received: %s", created.TransactionId)
+	}
+
+	listed, err := svc.ListOnchainTransactions(ctx, &ListOnchainTransactionsRequest{
+		PortfolioId: "p1",
+		WalletId:    "w1",
+		Statuses:    []string{model.OnchainTransactionStatusSigned},
+		Pagination:  &model.PaginationParams{Limit: "1"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(listed.Transactions) != 1 || !listed.Pagination.HasNext || listed.Pagination.NextCursor != "c2" {
+		t.Fatalf("unexpected list response: %+v", listed)
+	}
+
+	nonce, err := listed.Transactions[0].EvmParams.NonceNum()
+	if err != nil || nonce != 42 {
+		t.Errorf("expected nonce: 42 - received: %d - err: %v", nonce, err)
+	}
+
+	if listed.Transactions[0].IsTerminal() {
+		t.Error("expected a signed transaction not to be terminal")
+	}
+}
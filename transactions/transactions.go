@@ -20,6 +20,7 @@ import (
 	"context"
 
 	"github.com/coinbase-samples/prime-sdk-go/client"
+	"github.com/coinbase-samples/prime-sdk-go/idempotency"
 )
 
 type TransactionsService interface {
@@ -29,12 +30,34 @@ type TransactionsService interface {
 	ListWalletTransactions(ctx context.Context, request *ListWalletTransactionsRequest) (*ListWalletTransactionsResponse, error)
 	CreateWalletTransfer(ctx context.Context, request *CreateWalletTransferRequest) (*CreateWalletTransferResponse, error)
 	CreateWalletWithdrawal(ctx context.Context, request *CreateWalletWithdrawalRequest) (*CreateWalletWithdrawalResponse, error)
+	RunWithdrawalWorkflow(ctx context.Context, request *WithdrawalWorkflowRequest) (*WithdrawalWorkflowResponse, error)
+	CreateOnchainTransaction(ctx context.Context, request *CreateOnchainTransactionRequest) (*CreateOnchainTransactionResponse, error)
+	ListOnchainTransactions(ctx context.Context, request *ListOnchainTransactionsRequest) (*ListOnchainTransactionsResponse, error)
+	GetOnchainTransaction(ctx context.Context, request *GetOnchainTransactionRequest) (*GetOnchainTransactionResponse, error)
 }
 
 func NewTransactionsService(c client.RestClient) TransactionsService {
-	return &transactionsServiceImpl{client: c}
+	return NewTransactionsServiceWithKeyStore(c, nil)
+}
+
+// NewTransactionsServiceWithKeyStore returns a service that resolves the
+// idempotency keys of requests with a Reference through the key store. A nil
+// store defaults to idempotency.NewDerivedKeyStore, which gives a replayed
+// request the same key, including after a process restart.
+func NewTransactionsServiceWithKeyStore(c client.RestClient, keyStore idempotency.KeyStore) TransactionsService {
+	if keyStore == nil {
+		keyStore = idempotency.NewDerivedKeyStore(idempotency.DefaultNamespace)
+	}
+	return &transactionsServiceImpl{client: c, keyStore: keyStore}
 }
 
 type transactionsServiceImpl struct {
-	client client.RestClient
+	client   client.RestClient
+	keyStore idempotency.KeyStore
 }
+
+const (
+	operationTransfer   = "transfer"
+	operationWithdrawal = "withdrawal"
+	operationConversion = "conversion"
+)
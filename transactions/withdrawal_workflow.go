@@ -0,0 +1,353 @@
+/**
+ * Copyright 2024-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package transactions
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/coinbase-samples/prime-sdk-go/activities"
+	"github.com/coinbase-samples/prime-sdk-go/client"
+	"github.com/coinbase-samples/prime-sdk-go/model"
+)
+
+const defaultWithdrawalPollInterval = 5 * time.Second
+
+const (
+	WithdrawalEventSubmitted         = "SUBMITTED"
+	WithdrawalEventAwaitingApproval  = "AWAITING_APPROVAL"
+	WithdrawalEventApproved          = "APPROVED"
+	WithdrawalEventRejected          = "REJECTED"
+	WithdrawalEventTransactionStatus = "TRANSACTION_STATUS"
+	WithdrawalEventBroadcast         = "BROADCAST"
+	WithdrawalEventCompleted         = "COMPLETED"
+	WithdrawalEventFailed            = "FAILED"
+	WithdrawalEventTimedOut          = "TIMED_OUT"
+	WithdrawalEventCancelled         = "CANCELLED"
+
+	// A status request failed. Transient errors are retried; the event is
+	// emitted again only when the error changes.
+	WithdrawalEventPollError = "POLL_ERROR"
+)
+
+var (
+	ErrWithdrawalRejected = errors.New("withdrawal rejected")
+	ErrWithdrawalFailed   = errors.New("withdrawal failed")
+	ErrApprovalTimeout    = errors.New("withdrawal not approved in time")
+	ErrSettlementTimeout  = errors.New("withdrawal not completed in time")
+)
+
+// WithdrawalEvent is a stage change of a withdrawal workflow. The activity
+// and transaction are the latest state seen, if any.
+type WithdrawalEvent struct {
+	Type          string             `json:"type"`
+	Time          time.Time          `json:"time"`
+	ActivityId    string             `json:"activity_id"`
+	TransactionId string             `json:"transaction_id"`
+	Activity      *model.Activity    `json:"activity"`
+	Transaction   *model.Transaction `json:"transaction"`
+	Err           error              `json:"-"`
+}
+
+type WithdrawalWorkflowRequest struct {
+	// The withdrawal to submit. To resume tracking a submitted withdrawal,
+	// leave it nil and set the PortfolioId and ActivityId instead.
+	Withdrawal *CreateWalletWithdrawalRequest `json:"withdrawal"`
+
+	PortfolioId   string `json:"portfolio_id"`
+	ActivityId    string `json:"activity_id"`
+	TransactionId string `json:"transaction_id"`
+
+	// How often the activity and transaction are polled. Defaults to 5s.
+	PollInterval time.Duration `json:"poll_interval"`
+
+	// The maximum time to wait for consensus approval and, once approved,
+	// for the transaction to complete. Zero waits until the context is done.
+	ApprovalTimeout   time.Duration `json:"approval_timeout"`
+	SettlementTimeout time.Duration `json:"settlement_timeout"`
+
+	// Called synchronously with each event, so a slow handler delays the
+	// polling.
+	OnEvent func(*WithdrawalEvent) `json:"-"`
+}
+
+type WithdrawalWorkflowResponse struct {
+	// Nil when resuming a submitted withdrawal
+	Withdrawal *CreateWalletWithdrawalResponse `json:"withdrawal"`
+
+	Activity    *model.Activity            `json:"activity"`
+	Transaction *model.Transaction         `json:"transaction"`
+	Events      []*WithdrawalEvent         `json:"events"`
+	Request     *WithdrawalWorkflowRequest `json:"request"`
+}
+
+// Completed returns true if the transaction is done.
+func (r WithdrawalWorkflowResponse) Completed() bool {
+	return r.Transaction != nil && r.Transaction.Status == model.TransactionStatusDone
+}
+
+// RunWithdrawalWorkflow submits a withdrawal and tracks it to completion: the
+// activity is polled until it passes consensus, then the transaction is
+// polled until it is done, when its blockchain ids are set. An event is
+// emitted at each stage. The response holds the state reached so far, also
+// when an error is returned, e.g., ErrWithdrawalRejected, ErrApprovalTimeout,
+// the API error of a status request that is not worth retrying, or the
+// context error when the parent context is cancelled or its deadline passes.
+func (s *transactionsServiceImpl) RunWithdrawalWorkflow(
+	ctx context.Context,
+	request *WithdrawalWorkflowRequest,
+) (*WithdrawalWorkflowResponse, error) {
+
+	w := &withdrawalWorkflow{
+		service:       s,
+		activities:    activities.NewActivitiesService(s.client),
+		request:       request,
+		response:      &WithdrawalWorkflowResponse{Request: request},
+		portfolioId:   request.PortfolioId,
+		activityId:    request.ActivityId,
+		transactionId: request.TransactionId,
+		interval:      request.PollInterval,
+	}
+
+	if w.interval <= 0 {
+		w.interval = defaultWithdrawalPollInterval
+	}
+
+	if request.Withdrawal != nil {
+
+		withdrawal, err := s.CreateWalletWithdrawal(ctx, request.Withdrawal)
+		if err != nil {
+			return w.response, fmt.Errorf("unable to create withdrawal: %w", err)
+		}
+
+		w.response.Withdrawal = withdrawal
+		w.portfolioId = request.Withdrawal.PortfolioId
+		w.activityId = withdrawal.ActivityId
+		w.transactionId = withdrawal.TransactionId
+
+		w.emit(WithdrawalEventSubmitted, nil)
+	}
+
+	if len(w.portfolioId) == 0 || len(w.activityId) == 0 {
+		return w.response, errors.New("portfolio id and activity id are required to track a withdrawal")
+	}
+
+	if err := w.awaitApproval(ctx); err != nil {
+		return w.response, err
+	}
+
+	if err := w.awaitSettlement(ctx); err != nil {
+		return w.response, err
+	}
+
+	return w.response, nil
+}
+
+type withdrawalWorkflow struct {
+	service    *transactionsServiceImpl
+	activities activities.ActivitiesService
+	request    *WithdrawalWorkflowRequest
+	response   *WithdrawalWorkflowResponse
+
+	portfolioId   string
+	activityId    string
+	transactionId string
+	interval      time.Duration
+}
+
+func (w *withdrawalWorkflow) emit(eventType string, err error) {
+
+	e := &WithdrawalEvent{
+		Type:          eventType,
+		Time:          time.Now(),
+		ActivityId:    w.activityId,
+		TransactionId: w.transactionId,
+		Activity:      w.response.Activity,
+		Transaction:   w.response.Transaction,
+		Err:           err,
+	}
+
+	w.response.Events = append(w.response.Events, e)
+
+	if w.request.OnEvent != nil {
+		w.request.OnEvent(e)
+	}
+}
+
+func (w *withdrawalWorkflow) awaitApproval(ctx context.Context) error {
+
+	var awaiting bool
+
+	return w.poll(ctx, w.request.ApprovalTimeout, ErrApprovalTimeout, func(ctx context.Context) (bool, error) {
+
+		response, err := w.activities.GetActivity(ctx, &activities.GetActivityRequest{PortfolioId: w.portfolioId, Id: w.activityId})
+		if err != nil {
+			return false, fmt.Errorf("unable to get activity: %s - err: %w", w.activityId, err)
+		}
+
+		activity := response.Activity
+		if activity == nil {
+			return false, nil
+		}
+
+		w.response.Activity = activity
+
+		if len(w.transactionId) == 0 {
+			w.transactionId = activity.ReferenceId
+		}
+
+		consensus := activity.Consensus()
+
+		switch {
+		case consensus != nil && consensus.PassedConsensus, activity.Status == model.ActivityStatusCompleted:
+			w.emit(WithdrawalEventApproved, nil)
+			return true, nil
+		case activity.IsTerminal():
+			err := fmt.Errorf("%w - activity: %s - status: %s", ErrWithdrawalRejected, w.activityId, activity.Status)
+			w.emit(WithdrawalEventRejected, err)
+			return true, err
+		}
+
+		if !awaiting {
+			awaiting = true
+			w.emit(WithdrawalEventAwaitingApproval, nil)
+		}
+
+		return false, nil
+	})
+}
+
+func (w *withdrawalWorkflow) awaitSettlement(ctx context.Context) error {
+
+	if len(w.transactionId) == 0 {
+		return fmt.Errorf("transaction id unknown - activity: %s", w.activityId)
+	}
+
+	var status string
+	var broadcast bool
+
+	return w.poll(ctx, w.request.SettlementTimeout, ErrSettlementTimeout, func(ctx context.Context) (bool, error) {
+
+		response, err := w.service.GetTransaction(ctx, &GetTransactionRequest{PortfolioId: w.portfolioId, TransactionId: w.transactionId})
+		if err != nil {
+			return false, fmt.Errorf("unable to get transaction: %s - err: %w", w.transactionId, err)
+		}
+
+		tx := response.Transaction
+		if tx == nil {
+			return false, nil
+		}
+
+		w.response.Transaction = tx
+
+		if tx.Status != status {
+			status = tx.Status
+			w.emit(WithdrawalEventTransactionStatus, nil)
+		}
+
+		if !broadcast && len(tx.BlockchainIds) > 0 {
+			broadcast = true
+			w.emit(WithdrawalEventBroadcast, nil)
+		}
+
+		if !tx.IsTerminal() {
+			return false, nil
+		}
+
+		if tx.Status == model.TransactionStatusDone {
+			w.emit(WithdrawalEventCompleted, nil)
+			return true, nil
+		}
+
+		err = fmt.Errorf("%w - transaction: %s - status: %s", ErrWithdrawalFailed, w.transactionId, tx.Status)
+		w.emit(WithdrawalEventFailed, err)
+		return true, err
+	})
+}
+
+// poll calls check until it is done or the stage times out. An error from a
+// check that is not done is a failed request: a poll error event is emitted
+// when it first occurs, transient errors are retried and any other error,
+// e.g., a 401 or 404, is returned. A stage timeout is reported as timeoutErr
+// with the last error seen; a done parent context as its own error.
+func (w *withdrawalWorkflow) poll(
+	ctx context.Context,
+	timeout time.Duration,
+	timeoutErr error,
+	check func(ctx context.Context) (bool, error),
+) error {
+
+	stageCtx, cancel := withOptionalTimeout(ctx, timeout)
+	defer cancel()
+
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	var lastErr error
+	var failing bool
+
+	for {
+		done, err := check(stageCtx)
+		switch {
+		case done:
+			return err
+		case err != nil && stageCtx.Err() == nil:
+			if !failing || err.Error() != lastErr.Error() {
+				w.emit(WithdrawalEventPollError, err)
+			}
+			lastErr, failing = err, true
+			if !client.IsRetryable(err) {
+				return err
+			}
+		case err == nil:
+			failing = false
+		}
+
+		select {
+		case <-stageCtx.Done():
+			return w.stopped(ctx, timeoutErr, lastErr)
+		case <-ticker.C:
+		}
+	}
+}
+
+func (w *withdrawalWorkflow) stopped(ctx context.Context, timeoutErr, lastErr error) error {
+
+	if err := ctx.Err(); err != nil {
+		if lastErr != nil {
+			err = fmt.Errorf("withdrawal tracking stopped - activity: %s - transaction: %s - last err: %v - err: %w", w.activityId, w.transactionId, lastErr, err)
+		}
+		w.emit(WithdrawalEventCancelled, err)
+		return err
+	}
+
+	err := fmt.Errorf("%w - activity: %s - transaction: %s", timeoutErr, w.activityId, w.transactionId)
+	if lastErr != nil {
+		err = fmt.Errorf("%w - last err: %v", err, lastErr)
+	}
+	w.emit(WithdrawalEventTimedOut, err)
+	return err
+}
+
+func withOptionalTimeout(ctx context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	if timeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, timeout)
+}
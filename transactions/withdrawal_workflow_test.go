@@ -0,0 +1,226 @@
+/**
+ * Copyright 2024-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package transactions
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/coinbase-samples/core-go"
+	"github.com/coinbase-samples/prime-sdk-go/client"
+	"github.com/coinbase-samples/prime-sdk-go/credentials"
+	"github.com/coinbase-samples/prime-sdk-go/model"
+)
+
+func TestRunWithdrawalWorkflow(t *testing.T) {
+
+	cases := []struct {
+		description     string
+		activities      []string
+		activityCodes   []int
+		transactions    []string
+		approvalTimeout time.Duration
+		deadline        time.Duration
+		events          []string
+		expected        error
+		apiCode         int
+	}{
+		{
+			description: "TestRunWithdrawalWorkflow0",
+			activities: []string{
+				`{"activity":{"id":"a1","status":"ACTIVITY_STATUS_PROCESSING","transactions_metadata":{"consensus":{"has_passed_consensus":false}}}}`,
+				`{"activity":{"id":"a1","status":"ACTIVITY_STATUS_PROCESSING","transactions_metadata":{"consensus":{"has_passed_consensus":true}}}}`,
+			},
+			transactions: []string{
+				`{"transaction":{"id":"t1","status":"TRANSACTION_PROCESSING"}}`,
+				`{"transaction":{"id":"t1","status":"TRANSACTION_BROADCASTING","blockchain_ids":["0xabc"]}}`,
+				`{"transaction":{"id":"t1","status":"TRANSACTION_DONE","blockchain_ids":["0xabc"]}}`,
+			},
+			events: []string{
+				WithdrawalEventSubmitted,
+				WithdrawalEventAwaitingApproval,
+				WithdrawalEventApproved,
+				WithdrawalEventTransactionStatus,
+				WithdrawalEventTransactionStatus,
+				WithdrawalEventBroadcast,
+				WithdrawalEventTransactionStatus,
+				WithdrawalEventCompleted,
+			},
+		},
+		{
+			description: "TestRunWithdrawalWorkflow1",
+			activities: []string{
+				`{"activity":{"id":"a1","status":"ACTIVITY_STATUS_CANCELLED"}}`,
+			},
+			events:   []string{WithdrawalEventSubmitted, WithdrawalEventRejected},
+			expected: ErrWithdrawalRejected,
+		},
+		{
+			description: "TestRunWithdrawalWorkflow2",
+			activities: []string{
+				`{"activity":{"id":"a1","status":"ACTIVITY_STATUS_PROCESSING"}}`,
+			},
+			approvalTimeout: 20 * time.Millisecond,
+			events:          []string{WithdrawalEventSubmitted, WithdrawalEventAwaitingApproval, WithdrawalEventTimedOut},
+			expected:        ErrApprovalTimeout,
+		},
+		{
+			description: "TestRunWithdrawalWorkflow3",
+			activities: []string{
+				`{"activity":{"id":"a1","status":"ACTIVITY_STATUS_COMPLETED"}}`,
+			},
+			transactions: []string{
+				`{"transaction":{"id":"t1","status":"TRANSACTION_FAILED"}}`,
+			},
+			events: []string{
+				WithdrawalEventSubmitted,
+				WithdrawalEventApproved,
+				WithdrawalEventTransactionStatus,
+				WithdrawalEventFailed,
+			},
+			expected: ErrWithdrawalFailed,
+		},
+		{
+			description: "TestRunWithdrawalWorkflow4",
+			activities: []string{
+				`{"message":"unavailable"}`,
+				`{"message":"unavailable"}`,
+				`{"activity":{"id":"a1","status":"ACTIVITY_STATUS_CANCELLED"}}`,
+			},
+			activityCodes: []int{http.StatusServiceUnavailable, http.StatusServiceUnavailable, http.StatusOK},
+			events:        []string{WithdrawalEventSubmitted, WithdrawalEventPollError, WithdrawalEventRejected},
+			expected:      ErrWithdrawalRejected,
+		},
+		{
+			description: "TestRunWithdrawalWorkflow5",
+			activities: []string{
+				`{"message":"unauthorized"}`,
+			},
+			activityCodes: []int{http.StatusUnauthorized},
+			events:        []string{WithdrawalEventSubmitted, WithdrawalEventPollError},
+			apiCode:       http.StatusUnauthorized,
+		},
+		{
+			description: "TestRunWithdrawalWorkflow6",
+			activities: []string{
+				`{"message":"unavailable"}`,
+			},
+			activityCodes:   []int{http.StatusServiceUnavailable},
+			approvalTimeout: 20 * time.Millisecond,
+			events:          []string{WithdrawalEventSubmitted, WithdrawalEventPollError, WithdrawalEventTimedOut},
+			expected:        ErrApprovalTimeout,
+		},
+		{
+			description: "TestRunWithdrawalWorkflow7",
+			activities: []string{
+				`{"activity":{"id":"a1","status":"ACTIVITY_STATUS_PROCESSING"}}`,
+			},
+			approvalTimeout: time.Hour,
+			deadline:        20 * time.Millisecond,
+			events:          []string{WithdrawalEventSubmitted, WithdrawalEventAwaitingApproval, WithdrawalEventCancelled},
+			expected:        context.DeadlineExceeded,
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.description, func(t *testing.T) {
+
+			var activityPolls, transactionPolls int
+
+			next := func(w http.ResponseWriter, responses []string, codes []int, polls *int) {
+				i := *polls
+				if i >= len(responses) {
+					i = len(responses) - 1
+				}
+				*polls++
+				if i < len(codes) {
+					w.WriteHeader(codes[i])
+				}
+				w.Write([]byte(responses[i]))
+			}
+
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				switch {
+				case strings.HasSuffix(r.URL.Path, "/withdrawals"):
+					w.Write([]byte(`{"activity_id":"a1","transaction_id":"t1"}`))
+				case r.URL.Path == "/portfolios/p1/activities/a1":
+					next(w, tt.activities, tt.activityCodes, &activityPolls)
+				case r.URL.Path == "/portfolios/p1/transactions/t1":
+					next(w, tt.transactions, nil, &transactionPolls)
+				default:
+					t.Errorf("test: %s - unexpected path: %s", tt.description, r.URL.Path)
+				}
+			}))
+			defer server.Close()
+
+			svc := NewTransactionsService(client.NewRestClient(&credentials.Credentials{}, http.Client{}).SetBaseUrl(server.URL))
+
+			deadline := tt.deadline
+			if deadline == 0 {
+				deadline = 5 * time.Second
+			}
+
+			ctx, cancel := context.WithTimeout(context.Background(), deadline)
+			defer cancel()
+
+			var events []string
+
+			response, err := svc.RunWithdrawalWorkflow(ctx, &WithdrawalWorkflowRequest{
+				Withdrawal: &CreateWalletWithdrawalRequest{
+					PortfolioId:       "p1",
+					SourceWalletId:    "w1",
+					Symbol:            "ETH",
+					Amount:            "1",
+					DestinationType:   "DESTINATION_BLOCKCHAIN",
+					BlockchainAddress: &model.BlockchainAddress{Address: "0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed"},
+				},
+				PollInterval:    time.Millisecond,
+				ApprovalTimeout: tt.approvalTimeout,
+				OnEvent:         func(e *WithdrawalEvent) { events = append(events, e.Type) },
+			})
+
+			var apiErr *core.ApiError
+			switch {
+			case tt.apiCode > 0:
+				if !errors.As(err, &apiErr) || apiErr.CodeReceived != tt.apiCode {
+					t.Fatalf("test: %s - expected: %d - received: %v", tt.description, tt.apiCode, err)
+				}
+			case !errors.Is(err, tt.expected) || (err == nil) != (tt.expected == nil):
+				t.Fatalf("test: %s - expected: %v - received: %v", tt.description, tt.expected, err)
+			case len(tt.activityCodes) > 0 && errors.Is(err, ErrApprovalTimeout) && !strings.Contains(err.Error(), "unavailable"):
+				t.Errorf("test: %s - expected the last error in: %v", tt.description, err)
+			}
+
+			if strings.Join(events, ",") != strings.Join(tt.events, ",") {
+				t.Errorf("test: %s - expected: %v - received: %v", tt.description, tt.events, events)
+			}
+
+			if len(response.Events) != len(events) {
+				t.Errorf("test: %s - expected: %d response events - received: %d", tt.description, len(events), len(response.Events))
+			}
+
+			if tt.expected == nil && tt.apiCode == 0 && (!response.Completed() || response.Transaction.BlockchainIds[0] != "0xabc") {
+				t.Errorf("test: %s - expected a completed transaction", tt.description)
+			}
+		})
+	}
+}
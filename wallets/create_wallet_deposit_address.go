@@ -0,0 +1,80 @@
+/**
+ * Copyright 2024-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package wallets
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/coinbase-samples/core-go"
+	"github.com/coinbase-samples/prime-sdk-go/client"
+	"github.com/coinbase-samples/prime-sdk-go/model"
+)
+
+type CreateWalletDepositAddressRequest struct {
+	PortfolioId string `json:"portfolio_id"`
+	WalletId    string `json:"wallet_id"`
+
+	// The network of the new address, e.g., base-mainnet. Required for assets
+	// supported on more than one network.
+	NetworkId string `json:"network_id"`
+}
+
+type CreateWalletDepositAddressResponse struct {
+	Id                string                             `json:"id"`
+	Address           string                             `json:"address"`
+	AccountIdentifier string                             `json:"account_identifier"`
+	Network           *model.Network                     `json:"network"`
+	Request           *CreateWalletDepositAddressRequest `json:"request"`
+}
+
+// DepositAddress returns the created address.
+func (r CreateWalletDepositAddressResponse) DepositAddress() *model.DepositAddress {
+	return &model.DepositAddress{
+		Id:                r.Id,
+		Address:           r.Address,
+		AccountIdentifier: r.AccountIdentifier,
+		Network:           r.Network,
+	}
+}
+
+// CreateWalletDepositAddress creates a new deposit address on a crypto
+// wallet. Previous addresses remain valid.
+func (s *walletsServiceImpl) CreateWalletDepositAddress(
+	ctx context.Context,
+	request *CreateWalletDepositAddressRequest,
+) (*CreateWalletDepositAddressResponse, error) {
+
+	path := fmt.Sprintf("/portfolios/%s/wallets/%s/addresses", request.PortfolioId, request.WalletId)
+
+	response := &CreateWalletDepositAddressResponse{Request: request}
+
+	if err := core.HttpPost(
+		ctx,
+		s.client,
+		path,
+		core.EmptyQueryParams,
+		client.DefaultSuccessHttpStatusCodes,
+		request,
+		response,
+		s.client.HeadersFunc(),
+	); err != nil {
+		return nil, err
+	}
+
+	return response, nil
+}
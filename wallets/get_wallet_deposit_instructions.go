@@ -29,6 +29,11 @@ type GetWalletDepositInstructionsRequest struct {
 	PortfolioId string `json:"portfolio_id"`
 	Id          string `json:"wallet_id"`
 	Type        string `json:"deposit_type"`
+
+	// The network of the crypto deposit address, e.g., base-mainnet. If
+	// empty, the default network of the asset is used.
+	NetworkId   string `json:"network_id"`
+	NetworkType string `json:"network_type"`
 }
 
 type GetWalletDepositInstructionsResponse struct {
@@ -46,6 +51,14 @@ func (s *walletsServiceImpl) GetWalletDepositInstructions(
 
 	queryParams := core.AppendHttpQueryParam(core.EmptyQueryParams, "deposit_type", request.Type)
 
+	if len(request.NetworkId) > 0 {
+		queryParams = core.AppendHttpQueryParam(queryParams, queryParamNetworkId, request.NetworkId)
+	}
+
+	if len(request.NetworkType) > 0 {
+		queryParams = core.AppendHttpQueryParam(queryParams, queryParamNetworkType, request.NetworkType)
+	}
+
 	response := &GetWalletDepositInstructionsResponse{Request: request}
 
 	if err := core.HttpGet(
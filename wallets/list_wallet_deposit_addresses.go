@@ -0,0 +1,73 @@
+/**
+ * Copyright 2024-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package wallets
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/coinbase-samples/core-go"
+	"github.com/coinbase-samples/prime-sdk-go/client"
+	"github.com/coinbase-samples/prime-sdk-go/model"
+	"github.com/coinbase-samples/prime-sdk-go/utils"
+)
+
+type ListWalletDepositAddressesRequest struct {
+	PortfolioId string                  `json:"portfolio_id"`
+	WalletId    string                  `json:"wallet_id"`
+	NetworkId   string                  `json:"network_id"`
+	Pagination  *model.PaginationParams `json:"pagination_params"`
+}
+
+type ListWalletDepositAddressesResponse struct {
+	Addresses  []*model.DepositAddress            `json:"addresses"`
+	Pagination *model.Pagination                  `json:"pagination"`
+	Request    *ListWalletDepositAddressesRequest `json:"request"`
+}
+
+func (s *walletsServiceImpl) ListWalletDepositAddresses(
+	ctx context.Context,
+	request *ListWalletDepositAddressesRequest,
+) (*ListWalletDepositAddressesResponse, error) {
+
+	path := fmt.Sprintf("/portfolios/%s/wallets/%s/addresses", request.PortfolioId, request.WalletId)
+
+	var queryParams string
+
+	if len(request.NetworkId) > 0 {
+		queryParams = core.AppendHttpQueryParam(queryParams, queryParamNetworkId, request.NetworkId)
+	}
+
+	queryParams = utils.AppendPaginationParams(queryParams, request.Pagination)
+
+	response := &ListWalletDepositAddressesResponse{Request: request}
+
+	if err := core.HttpGet(
+		ctx,
+		s.client,
+		path,
+		queryParams,
+		client.DefaultSuccessHttpStatusCodes,
+		request,
+		response,
+		s.client.HeadersFunc(),
+	); err != nil {
+		return nil, err
+	}
+
+	return response, nil
+}
@@ -0,0 +1,95 @@
+/**
+ * Copyright 2024-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package wallets
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/coinbase-samples/prime-sdk-go/client"
+	"github.com/coinbase-samples/prime-sdk-go/credentials"
+	"github.com/coinbase-samples/prime-sdk-go/model"
+)
+
+func TestWalletDepositAddresses(t *testing.T) {
+
+	var addresses []*model.DepositAddress
+
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("/portfolios/p1/wallets/w1/addresses", func(w http.ResponseWriter, r *http.Request) {
+
+		if r.Method == http.MethodPost {
+			request := &CreateWalletDepositAddressRequest{}
+			json.NewDecoder(r.Body).Decode(request)
+
+			a := &model.DepositAddress{
+				Id:                fmt.Sprintf("a%d", len(addresses)),
+				Address:           "rAddress",
+				AccountIdentifier: "1234",
+				Network:           &model.Network{Id: request.NetworkId, Type: "mainnet"},
+			}
+			addresses = append(addresses, a)
+			json.NewEncoder(w).Encode(a)
+			return
+		}
+
+		q := r.URL.Query()
+
+		var page []*model.DepositAddress
+		for _, a := range addresses {
+			if a.Network.Id == q.Get("network.id") {
+				page = append(page, a)
+			}
+		}
+
+		json.NewEncoder(w).Encode(map[string]interface{}{"addresses": page, "pagination": &model.Pagination{}})
+	})
+
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	svc := NewWalletsService(client.NewRestClient(&credentials.Credentials{}, http.Client{}).SetBaseUrl(server.URL))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	for _, network := range []string{"xrp-mainnet", "xrp-mainnet", "other-mainnet"} {
+		created, err := svc.CreateWalletDepositAddress(ctx, &CreateWalletDepositAddressRequest{PortfolioId: "p1", WalletId: "w1", NetworkId: network})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if a := created.DepositAddress(); a.AccountIdentifier != "1234" || a.Network.Id != network {
+			t.Errorf("unexpected address: %+v", a)
+		}
+	}
+
+	listed, err := svc.ListWalletDepositAddresses(ctx, &ListWalletDepositAddressesRequest{PortfolioId: "p1", WalletId: "w1", NetworkId: "xrp-mainnet"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(listed.Addresses) != 2 {
+		t.Errorf("expected: 2 addresses - received: %d", len(listed.Addresses))
+	}
+}
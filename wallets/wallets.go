@@ -27,6 +27,8 @@ type WalletsService interface {
 	CreateWallet(ctx context.Context, request *CreateWalletRequest) (*CreateWalletResponse, error)
 	GetWallet(ctx context.Context, request *GetWalletRequest) (*GetWalletResponse, error)
 	GetWalletDepositInstructions(ctx context.Context, request *GetWalletDepositInstructionsRequest) (*GetWalletDepositInstructionsResponse, error)
+	CreateWalletDepositAddress(ctx context.Context, request *CreateWalletDepositAddressRequest) (*CreateWalletDepositAddressResponse, error)
+	ListWalletDepositAddresses(ctx context.Context, request *ListWalletDepositAddressesRequest) (*ListWalletDepositAddressesResponse, error)
 }
 
 func NewWalletsService(c client.RestClient) WalletsService {
@@ -36,3 +38,10 @@ func NewWalletsService(c client.RestClient) WalletsService {
 type walletsServiceImpl struct {
 	client client.RestClient
 }
+
+// The network query parameters, as documented for the deposit instructions
+// and wallet addresses endpoints
+const (
+	queryParamNetworkId   = "network.id"
+	queryParamNetworkType = "network.type"
+)
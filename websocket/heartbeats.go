@@ -0,0 +1,44 @@
+/**
+ * Copyright 2024-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package websocket
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+type HeartbeatEvent struct {
+	CurrentTime      time.Time `json:"current_time"`
+	HeartbeatCounter int64     `json:"heartbeat_counter"`
+}
+
+// HeartbeatSubscription returns the subscription to the heartbeats channel.
+// Prime sends a heartbeat every second, which keeps the stale watchdog from
+// firing on quiet connections.
+func HeartbeatSubscription(portfolioId string) *Subscription {
+	return &Subscription{Channel: ChannelHeartbeats, PortfolioId: portfolioId}
+}
+
+// DecodeHeartbeats decodes the events of a heartbeats channel message.
+func DecodeHeartbeats(msg *Message) ([]*HeartbeatEvent, error) {
+	var events []*HeartbeatEvent
+	if err := json.Unmarshal(msg.Events, &events); err != nil {
+		return nil, fmt.Errorf("unable to deserialize heartbeat events: %w", err)
+	}
+	return events, nil
+}
@@ -0,0 +1,227 @@
+/**
+ * Copyright 2024-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package websocket
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+
+	"github.com/coinbase-samples/core-go"
+	"github.com/coinbase-samples/prime-sdk-go/orderbook"
+)
+
+const (
+	l2EventTypeSnapshot = "snapshot"
+	l2EventTypeUpdate   = "update"
+
+	l2SideBid   = "bid"
+	l2SideOffer = "offer"
+)
+
+type l2Event struct {
+	Type      string      `json:"type"`
+	ProductId string      `json:"product_id"`
+	Updates   []*l2Update `json:"updates"`
+}
+
+type l2Update struct {
+	Side      string `json:"side"`
+	EventTime string `json:"event_time"`
+	Price     string `json:"px"`
+	Quantity  string `json:"qty"`
+}
+
+// OrderBookFeed maintains an L2 order book per product from the l2_data
+// channel.
+type OrderBookFeed interface {
+	orderbook.Feed
+}
+
+// NewOrderBookFeed registers the l2_data handler on the client. When the
+// client detects a sequence gap, every book is invalidated and the products
+// are resubscribed to receive fresh snapshots. Books are also invalidated
+// when the connection is lost, so they do not report stale data as synced
+// until the snapshots that follow the reconnect arrive.
+func NewOrderBookFeed(c Client) OrderBookFeed {
+	f := &orderBookFeedImpl{
+		client:        c,
+		books:         make(map[string]*orderbook.Book),
+		subscriptions: make(map[string]*Subscription),
+	}
+	c.Handle(ChannelL2Data, f.handle)
+	c.OnSequenceGap(f.onSequenceGap)
+	c.OnStateChange(f.onStateChange)
+	return f
+}
+
+type orderBookFeedImpl struct {
+	client Client
+	books  map[string]*orderbook.Book
+
+	// The subscriptions sent by Subscribe, keyed like the client records
+	// them, so a resync replaces them rather than adding new ones
+	subscriptions map[string]*Subscription
+	err           error
+	mu            sync.Mutex
+}
+
+func (f *orderBookFeedImpl) Subscribe(ctx context.Context, productIds ...string) error {
+
+	sub := &Subscription{Channel: ChannelL2Data, ProductIds: productIds}
+
+	f.mu.Lock()
+	for _, id := range productIds {
+		if _, ok := f.books[id]; !ok {
+			f.books[id] = orderbook.NewBook(id)
+		}
+	}
+	f.subscriptions[sub.key()] = sub
+	f.mu.Unlock()
+
+	return f.client.Subscribe(ctx, sub)
+}
+
+func (f *orderBookFeedImpl) Book(productId string) (*orderbook.Book, bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	b, ok := f.books[productId]
+	return b, ok
+}
+
+func (f *orderBookFeedImpl) Err() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.err
+}
+
+func (f *orderBookFeedImpl) setErr(err error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.err = err
+}
+
+func (f *orderBookFeedImpl) handle(msg *Message) {
+
+	var events []*l2Event
+	if err := json.Unmarshal(msg.Events, &events); err != nil {
+		f.setErr(fmt.Errorf("unable to deserialize l2 events: %w", err))
+		return
+	}
+
+	for _, e := range events {
+
+		book, ok := f.Book(e.ProductId)
+		if !ok {
+			continue
+		}
+
+		updates, err := convertL2Updates(e.Updates)
+		if err != nil {
+			f.setErr(fmt.Errorf("invalid l2 update - product: %s - err: %w", e.ProductId, err))
+			book.Invalidate()
+			continue
+		}
+
+		switch e.Type {
+		case l2EventTypeSnapshot:
+			book.ApplySnapshot(updates)
+		case l2EventTypeUpdate:
+			book.Apply(updates)
+		}
+	}
+}
+
+func (f *orderBookFeedImpl) onSequenceGap(expected, received int64) {
+
+	f.invalidate()
+
+	f.mu.Lock()
+	subs := make([]*Subscription, 0, len(f.subscriptions))
+	for _, sub := range f.subscriptions {
+		subs = append(subs, sub)
+	}
+	f.mu.Unlock()
+
+	for _, sub := range subs {
+
+		if err := f.client.Unsubscribe(context.Background(), sub); err != nil {
+			f.setErr(fmt.Errorf("unable to resync books after sequence gap - expected: %d - received: %d - err: %w", expected, received, err))
+			return
+		}
+
+		if err := f.client.Subscribe(context.Background(), sub); err != nil {
+			f.setErr(fmt.Errorf("unable to resync books after sequence gap - expected: %d - received: %d - err: %w", expected, received, err))
+			return
+		}
+	}
+}
+
+// onStateChange invalidates the books when the connection is lost. Run
+// restores the subscriptions on reconnect and the books are rebuilt from
+// the snapshots.
+func (f *orderBookFeedImpl) onStateChange(state ConnectionState) {
+	if state != ConnectionStateConnected {
+		f.invalidate()
+	}
+}
+
+func (f *orderBookFeedImpl) invalidate() {
+	f.mu.Lock()
+	books := make([]*orderbook.Book, 0, len(f.books))
+	for _, b := range f.books {
+		books = append(books, b)
+	}
+	f.mu.Unlock()
+
+	for _, b := range books {
+		b.Invalidate()
+	}
+}
+
+func convertL2Updates(l2 []*l2Update) ([]orderbook.Update, error) {
+
+	updates := make([]orderbook.Update, 0, len(l2))
+
+	for _, u := range l2 {
+
+		var side orderbook.Side
+		switch u.Side {
+		case l2SideBid:
+			side = orderbook.Bid
+		case l2SideOffer:
+			side = orderbook.Ask
+		default:
+			return nil, fmt.Errorf("unknown side: %s", u.Side)
+		}
+
+		price, err := core.StrToNum(u.Price)
+		if err != nil {
+			return nil, fmt.Errorf("invalid price: %s - err: %w", u.Price, err)
+		}
+
+		qty, err := core.StrToNum(u.Quantity)
+		if err != nil {
+			return nil, fmt.Errorf("invalid quantity: %s - err: %w", u.Quantity, err)
+		}
+
+		updates = append(updates, orderbook.Update{Side: side, Price: price, Quantity: qty})
+	}
+
+	return updates, nil
+}
@@ -0,0 +1,194 @@
+/**
+ * Copyright 2024-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package websocket
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/coinbase-samples/prime-sdk-go/client"
+	"github.com/coinbase-samples/prime-sdk-go/credentials"
+	"github.com/shopspring/decimal"
+)
+
+func TestOrderBookFeed(t *testing.T) {
+
+	creds := &credentials.Credentials{AccessKey: "key", SvcAccountId: "svc", Passphrase: "pass", SigningKey: "secret"}
+
+	snapshot := func(bid string) []*l2Event {
+		return []*l2Event{{
+			Type:      l2EventTypeSnapshot,
+			ProductId: "BTC-USD",
+			Updates: []*l2Update{
+				{Side: l2SideBid, Price: bid, Quantity: "1"},
+				{Side: l2SideOffer, Price: "101", Quantity: "2"},
+			},
+		}}
+	}
+
+	resynced := make(chan struct{})
+
+	server := newTestServer(t, func(c *testConn) {
+
+		sub := c.readSubscribe()
+		if sub == nil || sub.Channel != ChannelL2Data || len(sub.ProductIds) != 1 {
+			t.Errorf("unexpected subscribe message: %+v", sub)
+			return
+		}
+
+		expected := client.SignMessage("secret", "l2_datakeysvc"+sub.Timestamp+"BTC-USD")
+		if sub.Signature != expected || sub.Passphrase != "pass" {
+			t.Errorf("unexpected subscribe signature: %s", sub.Signature)
+		}
+
+		c.write(ChannelL2Data, 0, snapshot("99"))
+		c.write(ChannelL2Data, 1, []*l2Event{{
+			Type:      l2EventTypeUpdate,
+			ProductId: "BTC-USD",
+			Updates:   []*l2Update{{Side: l2SideBid, Price: "100", Quantity: "1"}},
+		}})
+
+		// Skip sequence 2; the feed must resubscribe for a fresh snapshot
+		c.write(ChannelL2Data, 3, []*l2Event{{
+			Type:      l2EventTypeUpdate,
+			ProductId: "BTC-USD",
+			Updates:   []*l2Update{{Side: l2SideBid, Price: "50", Quantity: "1"}},
+		}})
+
+		if unsub := c.readSubscribe(); unsub == nil || unsub.Type != messageTypeUnsubscribe {
+			t.Errorf("expected an unsubscribe after the gap: %+v", unsub)
+			return
+		}
+
+		if resub := c.readSubscribe(); resub == nil || resub.Type != messageTypeSubscribe {
+			t.Errorf("expected a subscribe after the gap: %+v", resub)
+			return
+		}
+
+		c.write(ChannelL2Data, 4, snapshot("98"))
+		close(resynced)
+
+		c.readSubscribe()
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	c := NewClient(creds).SetUrl(server.url())
+
+	feed := NewOrderBookFeed(c)
+
+	if err := c.Connect(ctx); err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+
+	if err := feed.Subscribe(ctx, "BTC-USD"); err != nil {
+		t.Fatal(err)
+	}
+
+	go c.Listen(ctx)
+
+	select {
+	case <-resynced:
+	case <-ctx.Done():
+		t.Fatal("timed out waiting for the resync")
+	}
+
+	book, _ := feed.Book("BTC-USD")
+
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		bid, err := book.BestBid()
+		if err == nil && bid.Price.Equal(decimal.NewFromInt(98)) {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("expected the book to be rebuilt from the new snapshot - bid: %v - err: %v", bid.Price, err)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	if err := feed.Err(); err != nil {
+		t.Error(err)
+	}
+
+	if subs := c.Subscriptions(); len(subs) != 1 {
+		t.Errorf("expected the resync to replace the subscription - received: %d", len(subs))
+	}
+}
+
+func TestOrderBookFeedDisconnect(t *testing.T) {
+
+	c := NewClient(&credentials.Credentials{})
+
+	feed := NewOrderBookFeed(c)
+
+	if err := feed.Subscribe(context.Background(), "BTC-USD"); err != nil {
+		t.Fatal(err)
+	}
+
+	events, err := json.Marshal([]*l2Event{{
+		Type:      l2EventTypeSnapshot,
+		ProductId: "BTC-USD",
+		Updates: []*l2Update{
+			{Side: l2SideBid, Price: "99", Quantity: "1"},
+			{Side: l2SideOffer, Price: "101", Quantity: "2"},
+		},
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		description string
+		state       ConnectionState
+		synced      bool
+	}{
+		{
+			description: "TestOrderBookFeedDisconnect0",
+			state:       ConnectionStateConnected,
+			synced:      true,
+		},
+		{
+			description: "TestOrderBookFeedDisconnect1",
+			state:       ConnectionStateStale,
+		},
+		{
+			description: "TestOrderBookFeedDisconnect2",
+			state:       ConnectionStateReconnecting,
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.description, func(t *testing.T) {
+
+			c.(*clientImpl).setState(ConnectionStateConnected)
+
+			feed.(*orderBookFeedImpl).handle(&Message{Channel: ChannelL2Data, Events: events})
+
+			c.(*clientImpl).setState(tt.state)
+
+			book, _ := feed.Book("BTC-USD")
+			if book.Synced() != tt.synced {
+				t.Errorf("test: %s - expected: %v - received: %v", tt.description, tt.synced, book.Synced())
+			}
+		})
+	}
+}
@@ -0,0 +1,391 @@
+/**
+ * Copyright 2024-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package websocket
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/coinbase-samples/prime-sdk-go/client"
+	"github.com/coinbase-samples/prime-sdk-go/model"
+	"github.com/coinbase-samples/prime-sdk-go/orders"
+)
+
+const (
+	OrderEventTypeSnapshot = "snapshot"
+	OrderEventTypeUpdate   = "update"
+
+	// Emitted for state fetched through REST after a reconnect or a
+	// sequence gap
+	OrderEventTypeResync = "resync"
+
+	// Orders and fills are fetched from this long before the last event, to
+	// cover events in flight when the connection was lost
+	orderResyncOverlap = time.Minute
+
+	// Terminal orders are remembered for this long after the last event, so
+	// a resync does not report them again, and then evicted
+	terminalOrderRetention = time.Hour
+
+	orderResyncTimeout = 30 * time.Second
+)
+
+type orderEvent struct {
+	Type   string          `json:"type"`
+	Orders []*orderMessage `json:"orders"`
+}
+
+type orderMessage struct {
+	OrderId       string `json:"order_id"`
+	ClientOrderId string `json:"client_order_id"`
+	ProductId     string `json:"product_id"`
+	Side          string `json:"side"`
+	Type          string `json:"order_type"`
+	CumQty        string `json:"cum_qty"`
+	LeavesQty     string `json:"leaves_qty"`
+	AvgPx         string `json:"avg_px"`
+	Fees          string `json:"fees"`
+	Status        string `json:"status"`
+	Created       string `json:"created_at"`
+}
+
+func (m orderMessage) toOrder(portfolioId string) *model.Order {
+	return &model.Order{
+		Id:                 m.OrderId,
+		PortfolioId:        portfolioId,
+		ClientOrderId:      m.ClientOrderId,
+		ProductId:          m.ProductId,
+		Side:               m.Side,
+		Type:               m.Type,
+		Status:             m.Status,
+		FilledQuantity:     m.CumQty,
+		AverageFilledPrice: m.AvgPx,
+		Commission:         m.Fees,
+		Created:            m.Created,
+	}
+}
+
+// OrderEvent is a change to an order in the subscribed portfolio. Order
+// holds the status, filled quantity, average filled price and commission.
+type OrderEvent struct {
+	Type  string       `json:"type"`
+	Order *model.Order `json:"order"`
+
+	// The unfilled quantity, if known. Not set on resync events.
+	LeavesQuantity string `json:"leaves_quantity"`
+
+	Received time.Time `json:"received"`
+}
+
+// OrderEventHandler is called for each order event, in order, on the
+// listening goroutine. A slow handler delays the processing of later
+// messages but never drops events.
+type OrderEventHandler func(e *OrderEvent)
+
+// NewOrderEventChannel returns a handler that sends events to the returned
+// channel. When the channel buffer is full, the handler blocks until the
+// consumer catches up.
+func NewOrderEventChannel(size int) (OrderEventHandler, <-chan *OrderEvent) {
+	ch := make(chan *OrderEvent, size)
+	return func(e *OrderEvent) { ch <- e }, ch
+}
+
+// OrdersFeed delivers real time order events for a portfolio from the orders
+// channel.
+type OrdersFeed interface {
+	Subscribe(ctx context.Context, productIds ...string) error
+
+	// Err returns the last error encountered while processing messages or
+	// recovering state after a reconnect.
+	Err() error
+}
+
+// NewOrdersFeed registers the orders handler on the client. Events are only
+// emitted when an order's status or filled quantity changes. After the client
+// reconnects or detects a sequence gap, the feed resyncs through the REST
+// client and emits resync events: open orders, orders created and orders
+// filled since the last event, and every order not yet known to be
+// terminal. Fills that happen while disconnected are therefore not lost. A
+// resync on a sequence gap runs on the listening goroutine, so live events
+// are not interleaved with it.
+func NewOrdersFeed(c Client, rest client.RestClient, portfolioId string, handler OrderEventHandler) OrdersFeed {
+
+	f := &ordersFeedImpl{
+		client:      c,
+		orders:      orders.NewOrdersService(rest),
+		portfolioId: portfolioId,
+		handler:     handler,
+		known:       make(map[string]*model.Order),
+		terminal:    make(map[string]time.Time),
+		lastEvent:   time.Now(),
+	}
+
+	c.Handle(ChannelOrders, f.handle)
+	c.OnReconnect(f.resync)
+	c.OnSequenceGap(f.onSequenceGap)
+
+	return f
+}
+
+type ordersFeedImpl struct {
+	client      Client
+	orders      orders.OrdersService
+	portfolioId string
+	productIds  []string
+	handler     OrderEventHandler
+
+	// The last state of working orders
+	known map[string]*model.Order
+
+	// The ids of orders that reached a terminal state and when
+	terminal map[string]time.Time
+
+	// The timestamp of the last orders message, or the creation time of the
+	// feed if none was received
+	lastEvent time.Time
+
+	err error
+	mu  sync.Mutex
+}
+
+func (f *ordersFeedImpl) Subscribe(ctx context.Context, productIds ...string) error {
+
+	f.mu.Lock()
+	f.productIds = append(f.productIds, productIds...)
+	f.mu.Unlock()
+
+	return f.client.Subscribe(ctx, &Subscription{
+		Channel:     ChannelOrders,
+		PortfolioId: f.portfolioId,
+		ProductIds:  productIds,
+	})
+}
+
+func (f *ordersFeedImpl) Err() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.err
+}
+
+func (f *ordersFeedImpl) setErr(err error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.err = err
+}
+
+func (f *ordersFeedImpl) handle(msg *Message) {
+
+	var events []*orderEvent
+	if err := json.Unmarshal(msg.Events, &events); err != nil {
+		f.setErr(fmt.Errorf("unable to deserialize order events: %w", err))
+		return
+	}
+
+	received := time.Now()
+
+	f.mu.Lock()
+	if msg.Timestamp.IsZero() {
+		f.lastEvent = received
+	} else {
+		f.lastEvent = msg.Timestamp
+	}
+	f.mu.Unlock()
+
+	for _, e := range events {
+		for _, o := range e.Orders {
+			f.emit(&OrderEvent{
+				Type:           e.Type,
+				Order:          o.toOrder(f.portfolioId),
+				LeavesQuantity: o.LeavesQty,
+				Received:       received,
+			})
+		}
+	}
+}
+
+// emit calls the handler if the order is new or its status or filled
+// quantity changed since the last event. Terminal orders are moved out of
+// the known orders, so the state kept does not grow with every order.
+func (f *ordersFeedImpl) emit(e *OrderEvent) {
+
+	f.mu.Lock()
+
+	_, done := f.terminal[e.Order.Id]
+	last, ok := f.known[e.Order.Id]
+	changed := !done && (!ok || last.Status != e.Order.Status || last.FilledQuantity != e.Order.FilledQuantity)
+
+	if changed {
+		if e.Order.IsTerminal() {
+			delete(f.known, e.Order.Id)
+			f.terminal[e.Order.Id] = e.Received
+			f.evictTerminal()
+		} else {
+			f.known[e.Order.Id] = e.Order
+		}
+	}
+
+	f.mu.Unlock()
+
+	if changed {
+		f.handler(e)
+	}
+}
+
+// evictTerminal forgets terminal orders that are older than the retention
+// relative to the last event. The last event, not the local clock, is used,
+// so a long disconnect does not evict orders the resync lists again.
+func (f *ordersFeedImpl) evictTerminal() {
+	cutoff := f.lastEvent.Add(-terminalOrderRetention)
+	for id, t := range f.terminal {
+		if t.Before(cutoff) {
+			delete(f.terminal, id)
+		}
+	}
+}
+
+func (f *ordersFeedImpl) onSequenceGap(expected, received int64) {
+	ctx, cancel := context.WithTimeout(context.Background(), orderResyncTimeout)
+	defer cancel()
+	f.resync(ctx)
+}
+
+// resync fetches the orders that may have changed since the last event and
+// emits their current state.
+func (f *ordersFeedImpl) resync(ctx context.Context) {
+
+	f.mu.Lock()
+	productIds := append([]string(nil), f.productIds...)
+	since := f.lastEvent.Add(-orderResyncOverlap)
+	pending := make(map[string]bool, len(f.known))
+	for id := range f.known {
+		pending[id] = true
+	}
+	f.mu.Unlock()
+
+	emitted := make(map[string]bool)
+
+	open, err := f.orders.ListAllOpenOrders(ctx, &orders.ListAllOpenOrdersRequest{
+		PortfolioId: f.portfolioId,
+		ProductIds:  productIds,
+	})
+	if err != nil {
+		f.setErr(fmt.Errorf("unable to list open orders on resync: %w", err))
+		return
+	}
+
+	for _, o := range open.Orders {
+		emitted[o.Id] = true
+		f.emit(&OrderEvent{Type: OrderEventTypeResync, Order: o, Received: time.Now()})
+	}
+
+	// Orders created since the last event, including ones that were created
+	// and filled while disconnected
+	pagination := &model.PaginationParams{}
+	for {
+		response, err := f.orders.ListOrders(ctx, &orders.ListOrdersRequest{
+			PortfolioId: f.portfolioId,
+			ProductIds:  productIds,
+			Start:       since,
+			Pagination:  pagination,
+		})
+		if err != nil {
+			f.setErr(fmt.Errorf("unable to list orders on resync: %w", err))
+			break
+		}
+
+		for _, o := range response.Orders {
+			if !emitted[o.Id] {
+				emitted[o.Id] = true
+				f.emit(&OrderEvent{Type: OrderEventTypeResync, Order: o, Received: time.Now()})
+			}
+		}
+
+		if !response.HasNext() {
+			break
+		}
+
+		pagination = &model.PaginationParams{Cursor: response.Pagination.NextCursor}
+	}
+
+	// Orders filled since the last event, which may have been created long
+	// before it
+	pagination = &model.PaginationParams{}
+	for {
+		response, err := f.orders.ListPortfolioFills(ctx, &orders.ListPortfolioFillsRequest{
+			PortfolioId: f.portfolioId,
+			Start:       since,
+			Pagination:  pagination,
+		})
+		if err != nil {
+			f.setErr(fmt.Errorf("unable to list fills on resync: %w", err))
+			break
+		}
+
+		for _, fill := range response.Fills {
+			if len(fill.OrderId) > 0 && matchesProduct(productIds, fill.ProductId) {
+				pending[fill.OrderId] = true
+			}
+		}
+
+		if !response.HasNext() {
+			break
+		}
+
+		pagination = &model.PaginationParams{Cursor: response.Pagination.NextCursor}
+	}
+
+	for id := range pending {
+
+		if emitted[id] || f.isTerminal(id) {
+			continue
+		}
+
+		response, err := f.orders.GetOrder(ctx, &orders.GetOrderRequest{PortfolioId: f.portfolioId, OrderId: id})
+		if err != nil {
+			f.setErr(fmt.Errorf("unable to get order on resync: %s - err: %w", id, err))
+			continue
+		}
+
+		if response.Order == nil {
+			continue
+		}
+
+		f.emit(&OrderEvent{Type: OrderEventTypeResync, Order: response.Order, Received: time.Now()})
+	}
+}
+
+func (f *ordersFeedImpl) isTerminal(orderId string) bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	_, ok := f.terminal[orderId]
+	return ok
+}
+
+func matchesProduct(productIds []string, productId string) bool {
+	if len(productIds) == 0 {
+		return true
+	}
+	for _, p := range productIds {
+		if p == productId {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,338 @@
+/**
+ * Copyright 2024-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package websocket
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/coinbase-samples/prime-sdk-go/client"
+	"github.com/coinbase-samples/prime-sdk-go/credentials"
+	"github.com/coinbase-samples/prime-sdk-go/model"
+)
+
+// newOrdersRestServer serves the REST endpoints used by the resync. o1 was
+// open and filled while disconnected, o2 was created and filled while
+// disconnected and o3, created before the feed, was filled while
+// disconnected. Each request takes at least the delay.
+func newOrdersRestServer(t *testing.T, delay time.Duration) *httptest.Server {
+
+	filled := func(id string) *model.Order {
+		return &model.Order{Id: id, Status: model.OrderStatusFilled, FilledQuantity: "1"}
+	}
+
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("/portfolios/p1/products", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]interface{}{"products": []*model.Product{{Id: "BTC-USD"}}})
+	})
+
+	mux.HandleFunc("/portfolios/p1/open_orders", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]interface{}{"orders": []*model.Order{}})
+	})
+
+	mux.HandleFunc("/portfolios/p1/orders", func(w http.ResponseWriter, r *http.Request) {
+		if len(r.URL.Query().Get("start_date")) == 0 {
+			t.Errorf("expected a start date: %s", r.URL.RawQuery)
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"orders": []*model.Order{filled("o2")}})
+	})
+
+	mux.HandleFunc("/portfolios/p1/fills", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]interface{}{"fills": []*model.OrderFill{
+			{Id: "f1", OrderId: "o1", ProductId: "BTC-USD"},
+			{Id: "f2", OrderId: "o2", ProductId: "BTC-USD"},
+			{Id: "f3", OrderId: "o3", ProductId: "BTC-USD"},
+		}})
+	})
+
+	for _, id := range []string{"o1", "o3"} {
+		id := id
+		mux.HandleFunc("/portfolios/p1/orders/"+id, func(w http.ResponseWriter, r *http.Request) {
+			json.NewEncoder(w).Encode(map[string]interface{}{"order": filled(id)})
+		})
+	}
+
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(delay)
+		mux.ServeHTTP(w, r)
+	}))
+}
+
+func TestOrdersFeedResync(t *testing.T) {
+
+	creds := &credentials.Credentials{AccessKey: "key", SvcAccountId: "svc", Passphrase: "pass", SigningKey: "secret"}
+
+	cases := []struct {
+		description string
+		gap         bool
+		connections int
+	}{
+		{
+			description: "TestOrdersFeedResync0",
+			connections: 2,
+		},
+		{
+			description: "TestOrdersFeedResync1",
+			gap:         true,
+			connections: 1,
+		},
+	}
+
+	for _, tt := range cases {
+		tt := tt
+		t.Run(tt.description, func(t *testing.T) {
+
+			rest := newOrdersRestServer(t, 0)
+			defer rest.Close()
+
+			server := newTestServer(t, func(c *testConn) {
+
+				sub := c.readSubscribe()
+				if sub == nil || sub.Channel != ChannelOrders || sub.PortfolioId != "p1" {
+					t.Errorf("unexpected subscribe message: %+v", sub)
+					return
+				}
+
+				if sub.Signature != client.SignMessage("secret", "orderskeysvc"+sub.Timestamp+"p1") {
+					t.Errorf("unexpected subscribe signature: %s", sub.Signature)
+				}
+
+				c.write(ChannelOrders, 0, []*orderEvent{{
+					Type:   OrderEventTypeSnapshot,
+					Orders: []*orderMessage{{OrderId: "o1", Status: model.OrderStatusOpen, CumQty: "0", LeavesQty: "1"}},
+				}})
+
+				// Repeated state is not emitted twice
+				c.write(ChannelOrders, 1, []*orderEvent{{
+					Type:   OrderEventTypeUpdate,
+					Orders: []*orderMessage{{OrderId: "o1", Status: model.OrderStatusOpen, CumQty: "0", LeavesQty: "1"}},
+				}})
+
+				if tt.gap {
+					// Skip sequence 2 with a message for an unrelated order
+					c.write(ChannelOrders, 3, []*orderEvent{{
+						Type:   OrderEventTypeUpdate,
+						Orders: []*orderMessage{{OrderId: "o4", Status: model.OrderStatusOpen, CumQty: "0", LeavesQty: "1"}},
+					}})
+				}
+
+				c.readSubscribe()
+			})
+
+			restClient := client.NewRestClient(creds, http.Client{}).SetBaseUrl(rest.URL)
+
+			ws := NewClient(creds).SetUrl(server.url())
+
+			handler, events := NewOrderEventChannel(10)
+
+			feed := NewOrdersFeed(ws, restClient, "p1", handler)
+
+			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+			defer cancel()
+
+			if err := feed.Subscribe(ctx); err != nil {
+				t.Fatal(err)
+			}
+
+			go ws.Run(ctx)
+
+			first := <-events
+			if first.Type != OrderEventTypeSnapshot || first.Order.Status != model.OrderStatusOpen || first.LeavesQuantity != "1" {
+				t.Fatalf("test: %s - unexpected first event: %+v", tt.description, first)
+			}
+
+			if !tt.gap {
+				// Drop the connection from the client side; Run must reconnect
+				ws.(*clientImpl).connection().Close()
+			}
+
+			resynced := make(map[string]bool)
+
+			for len(resynced) < 3 {
+				select {
+				case e := <-events:
+					if e.Type != OrderEventTypeResync {
+						continue
+					}
+					if e.Order.Status != model.OrderStatusFilled || e.Order.FilledQuantity != "1" || resynced[e.Order.Id] {
+						t.Fatalf("test: %s - unexpected resync event: %+v", tt.description, e.Order)
+					}
+					resynced[e.Order.Id] = true
+				case <-ctx.Done():
+					t.Fatalf("test: %s - timed out waiting for the resync events - received: %v", tt.description, resynced)
+				}
+			}
+
+			if server.connectionCount() != tt.connections {
+				t.Errorf("test: %s - expected: %d connections - received: %d", tt.description, tt.connections, server.connectionCount())
+			}
+
+			if err := feed.Err(); err != nil {
+				t.Errorf("test: %s - unexpected error: %v", tt.description, err)
+			}
+
+			f := feed.(*ordersFeedImpl)
+			f.mu.Lock()
+			defer f.mu.Unlock()
+
+			if _, ok := f.known["o1"]; ok {
+				t.Errorf("test: %s - expected the filled order to be evicted from the known orders", tt.description)
+			}
+		})
+	}
+}
+
+// The resync after a sequence gap takes longer than the stale timeout. The
+// connection keeps receiving heartbeats, so it must not be declared stale.
+func TestOrdersFeedSlowResync(t *testing.T) {
+
+	creds := &credentials.Credentials{AccessKey: "key", SvcAccountId: "svc", Passphrase: "pass", SigningKey: "secret"}
+
+	rest := newOrdersRestServer(t, 100*time.Millisecond)
+	defer rest.Close()
+
+	server := newTestServer(t, func(c *testConn) {
+
+		if sub := c.readSubscribe(); sub == nil {
+			return
+		}
+
+		c.write(ChannelOrders, 0, []*orderEvent{{
+			Type:   OrderEventTypeSnapshot,
+			Orders: []*orderMessage{{OrderId: "o1", Status: model.OrderStatusOpen, CumQty: "0", LeavesQty: "1"}},
+		}})
+
+		// Skip sequence 1
+		c.write(ChannelOrders, 2, []*orderEvent{{
+			Type:   OrderEventTypeUpdate,
+			Orders: []*orderMessage{{OrderId: "o4", Status: model.OrderStatusOpen, CumQty: "0", LeavesQty: "1"}},
+		}})
+
+		// Wait, so the heartbeats are not read together with the gap
+		time.Sleep(50 * time.Millisecond)
+
+		for sequence := int64(3); c.write(ChannelHeartbeats, sequence, []*HeartbeatEvent{{HeartbeatCounter: sequence}}); sequence++ {
+			time.Sleep(20 * time.Millisecond)
+		}
+	})
+
+	restClient := client.NewRestClient(creds, http.Client{}).SetBaseUrl(rest.URL)
+
+	ws := NewClient(creds).SetUrl(server.url()).SetStaleAfter(150 * time.Millisecond)
+
+	recorder := newStateRecorder()
+	ws.OnStateChange(recorder.handle)
+
+	handler, events := NewOrderEventChannel(10)
+
+	feed := NewOrdersFeed(ws, restClient, "p1", handler)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	if err := feed.Subscribe(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	go ws.Run(ctx)
+
+	resynced := make(map[string]bool)
+
+	for len(resynced) < 3 {
+		select {
+		case e := <-events:
+			if e.Type == OrderEventTypeResync {
+				resynced[e.Order.Id] = true
+			}
+		case <-ctx.Done():
+			t.Fatalf("timed out waiting for the resync events - received: %v", resynced)
+		}
+	}
+
+	// Give a stale connection time to be detected
+	time.Sleep(300 * time.Millisecond)
+
+	recorder.mu.Lock()
+	defer recorder.mu.Unlock()
+
+	for _, state := range recorder.states {
+		if state == ConnectionStateStale {
+			t.Fatalf("expected the connection not to be stale - states: %v", recorder.states)
+		}
+	}
+
+	if server.connectionCount() != 1 {
+		t.Errorf("expected: 1 connection - received: %d", server.connectionCount())
+	}
+}
+
+func TestOrdersFeedEvictTerminal(t *testing.T) {
+
+	var emitted int
+
+	f := NewOrdersFeed(NewClient(&credentials.Credentials{}), nil, "p1", func(e *OrderEvent) { emitted++ }).(*ordersFeedImpl)
+
+	start := time.Now()
+
+	cases := []struct {
+		description string
+		order       *model.Order
+		lastEvent   time.Time
+		emitted     int
+		terminal    int
+	}{
+		{
+			description: "TestOrdersFeedEvictTerminal0",
+			order:       &model.Order{Id: "o1", Status: model.OrderStatusFilled, FilledQuantity: "1"},
+			lastEvent:   start,
+			emitted:     1,
+			terminal:    1,
+		},
+		{
+			description: "TestOrdersFeedEvictTerminal1",
+			order:       &model.Order{Id: "o1", Status: model.OrderStatusFilled, FilledQuantity: "1"},
+			lastEvent:   start.Add(time.Minute),
+			emitted:     1,
+			terminal:    1,
+		},
+		{
+			description: "TestOrdersFeedEvictTerminal2",
+			order:       &model.Order{Id: "o2", Status: model.OrderStatusCancelled},
+			lastEvent:   start.Add(2 * terminalOrderRetention),
+			emitted:     2,
+			terminal:    1,
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.description, func(t *testing.T) {
+
+			f.lastEvent = tt.lastEvent
+			f.emit(&OrderEvent{Type: OrderEventTypeUpdate, Order: tt.order, Received: tt.lastEvent})
+
+			if emitted != tt.emitted || len(f.terminal) != tt.terminal || len(f.known) != 0 {
+				t.Errorf("test: %s - expected: %d emitted %d terminal - received: %d emitted %d terminal %d known",
+					tt.description, tt.emitted, tt.terminal, emitted, len(f.terminal), len(f.known))
+			}
+		})
+	}
+}
@@ -0,0 +1,97 @@
+/**
+ * Copyright 2024-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package websocket
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	gorilla "github.com/gorilla/websocket"
+)
+
+// testServer is a local Prime WebSocket feed. Each accepted connection is
+// passed to the script, which reads subscribe messages and writes channel
+// messages.
+type testServer struct {
+	*httptest.Server
+	connections int
+	mu          sync.Mutex
+}
+
+func newTestServer(t *testing.T, script func(conn *testConn)) *testServer {
+	t.Helper()
+
+	s := &testServer{}
+
+	upgrader := gorilla.Upgrader{}
+
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		c, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			t.Errorf("unable to upgrade connection: %v", err)
+			return
+		}
+		defer c.Close()
+
+		s.mu.Lock()
+		s.connections++
+		s.mu.Unlock()
+
+		script(&testConn{t: t, conn: c})
+	}))
+
+	t.Cleanup(s.Close)
+
+	return s
+}
+
+func (s *testServer) url() string {
+	return "ws" + strings.TrimPrefix(s.URL, "http")
+}
+
+func (s *testServer) connectionCount() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.connections
+}
+
+type testConn struct {
+	t    *testing.T
+	conn *gorilla.Conn
+}
+
+func (c *testConn) readSubscribe() *subscribeMessage {
+	msg := &subscribeMessage{}
+	if err := c.conn.ReadJSON(msg); err != nil {
+		return nil
+	}
+	return msg
+}
+
+func (c *testConn) write(channel string, sequence int64, events interface{}) bool {
+	b, err := json.Marshal(events)
+	if err != nil {
+		c.t.Errorf("unable to serialize events: %v", err)
+		return false
+	}
+
+	return c.conn.WriteJSON(&Message{Channel: channel, SequenceNum: sequence, Events: b}) == nil
+}
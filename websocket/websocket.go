@@ -0,0 +1,622 @@
+/**
+ * Copyright 2024-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package websocket
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/rand"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/coinbase-samples/core-go"
+	"github.com/coinbase-samples/prime-sdk-go/client"
+	"github.com/coinbase-samples/prime-sdk-go/credentials"
+)
+
+const (
+	DefaultUrl = "wss://ws-feed.prime.coinbase.com"
+
+	ChannelL2Data        = "l2_data"
+	ChannelOrders        = "orders"
+	ChannelSubscriptions = "subscriptions"
+	ChannelHeartbeats    = "heartbeats"
+
+	messageTypeSubscribe   = "subscribe"
+	messageTypeUnsubscribe = "unsubscribe"
+	messageTypeError       = "error"
+
+	// Prime messages can be large, e.g., a deep order book snapshot
+	defaultReadLimit = 10 * 1024 * 1024
+
+	defaultReconnectBackoff    = time.Second
+	defaultMaxReconnectBackoff = 30 * time.Second
+)
+
+// ErrStale is returned by Listen when no message is received within the
+// configured stale period. Run treats it like any other connection failure
+// and reconnects.
+var ErrStale = errors.New("websocket connection stale")
+
+// Message is the envelope of every message received from Prime. Events are
+// decoded by the handler registered for the channel.
+type Message struct {
+	Channel     string          `json:"channel"`
+	Timestamp   time.Time       `json:"timestamp"`
+	SequenceNum int64           `json:"sequence_num"`
+	Events      json.RawMessage `json:"events"`
+
+	// Set on error messages
+	Type    string `json:"type"`
+	Message string `json:"message"`
+}
+
+type Subscription struct {
+	Channel     string   `json:"channel"`
+	PortfolioId string   `json:"portfolio_id"`
+	ProductIds  []string `json:"product_ids"`
+}
+
+func (s Subscription) key() string {
+	return fmt.Sprintf("%s|%s|%s", s.Channel, s.PortfolioId, strings.Join(s.ProductIds, ","))
+}
+
+type subscribeMessage struct {
+	Type        string   `json:"type"`
+	Channel     string   `json:"channel"`
+	AccessKey   string   `json:"access_key"`
+	ApiKeyId    string   `json:"api_key_id"`
+	Timestamp   string   `json:"timestamp"`
+	Passphrase  string   `json:"passphrase"`
+	Signature   string   `json:"signature"`
+	PortfolioId string   `json:"portfolio_id"`
+	ProductIds  []string `json:"product_ids"`
+}
+
+// ServerError is returned by Listen when Prime sends an error message.
+type ServerError struct {
+	Message string
+}
+
+func (e *ServerError) Error() string {
+	return fmt.Sprintf("websocket error message: %s", e.Message)
+}
+
+// MessageHandler is called on the listening goroutine for each message on a
+// channel. Handlers must not block.
+type MessageHandler func(msg *Message)
+
+// SequenceGapHandler is called when a message arrives with a sequence number
+// other than the next expected one. Prime sequences messages per connection,
+// across all channels.
+type SequenceGapHandler func(expected, received int64)
+
+// ReconnectHandler is called by Run after the connection is re-established
+// and subscriptions are restored, before messages are read. Use it to
+// recover state that may have changed while disconnected.
+type ReconnectHandler func(ctx context.Context)
+
+// StateHandler is called when the connection state changes. Handlers are
+// called synchronously and must not block.
+type StateHandler func(state ConnectionState)
+
+type Client interface {
+	SetUrl(u string) Client
+	Url() string
+	Credentials() *credentials.Credentials
+
+	// SetStaleAfter enables the watchdog: if no message, including a
+	// heartbeat, is received for the duration, the connection is declared
+	// stale and closed. Subscribe to the heartbeats channel so a healthy but
+	// quiet connection is not declared stale. Time spent in handlers, e.g., a
+	// resync, does not count. Zero, the default, disables it.
+	SetStaleAfter(d time.Duration) Client
+	StaleAfter() time.Duration
+
+	// SetReconnectBackoff sets the delay before the first reconnect attempt
+	// and the cap of the exponential backoff between failed attempts. Delays
+	// are jittered. Defaults to 1s and 30s.
+	SetReconnectBackoff(initial, max time.Duration) Client
+
+	Connect(ctx context.Context) error
+	Subscribe(ctx context.Context, sub *Subscription) error
+	Unsubscribe(ctx context.Context, sub *Subscription) error
+	Subscriptions() []*Subscription
+
+	Handle(channel string, h MessageHandler)
+	OnSequenceGap(h SequenceGapHandler)
+	OnReconnect(h ReconnectHandler)
+	OnStateChange(h StateHandler)
+
+	State() ConnectionState
+
+	// LastSequence returns the sequence number of the last message received
+	// on the current connection, or -1 if none was received.
+	LastSequence() int64
+
+	// LastHeartbeat returns the time the last heartbeats message was
+	// received, or the zero time if none was received.
+	LastHeartbeat() time.Time
+
+	// Listen reads messages and dispatches them to the channel handlers until
+	// the connection fails or the context is done. It blocks.
+	Listen(ctx context.Context) error
+
+	// Run connects, restores subscriptions and listens, reconnecting when
+	// the connection fails, until the context is done or Prime sends an
+	// error message. It blocks.
+	Run(ctx context.Context) error
+	Close() error
+}
+
+func NewClient(credentials *credentials.Credentials) Client {
+	return &clientImpl{
+		url:                 DefaultUrl,
+		credentials:         credentials,
+		reconnectBackoff:    defaultReconnectBackoff,
+		maxReconnectBackoff: defaultMaxReconnectBackoff,
+		handlers:            make(map[string]MessageHandler),
+		subscriptions:       make(map[string]*Subscription),
+		lastSequence:        -1,
+	}
+}
+
+type clientImpl struct {
+	url         string
+	credentials *credentials.Credentials
+
+	staleAfter          time.Duration
+	reconnectBackoff    time.Duration
+	maxReconnectBackoff time.Duration
+
+	conn    *core.WebSocketConnection
+	writeMu sync.Mutex
+
+	handlers          map[string]MessageHandler
+	gapHandlers       []SequenceGapHandler
+	reconnectHandlers []ReconnectHandler
+	stateHandlers     []StateHandler
+	subscriptions     map[string]*Subscription
+	lastSequence      int64
+	lastHeartbeat     time.Time
+	connectedBefore   bool
+	state             ConnectionState
+
+	mu sync.Mutex
+}
+
+func (c *clientImpl) SetUrl(u string) Client {
+	c.url = u
+	return c
+}
+
+func (c *clientImpl) Url() string {
+	return c.url
+}
+
+func (c *clientImpl) Credentials() *credentials.Credentials {
+	return c.credentials
+}
+
+func (c *clientImpl) SetStaleAfter(d time.Duration) Client {
+	c.staleAfter = d
+	return c
+}
+
+func (c *clientImpl) StaleAfter() time.Duration {
+	return c.staleAfter
+}
+
+func (c *clientImpl) SetReconnectBackoff(initial, max time.Duration) Client {
+	c.reconnectBackoff = initial
+	c.maxReconnectBackoff = max
+	return c
+}
+
+func (c *clientImpl) Handle(channel string, h MessageHandler) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.handlers[channel] = h
+}
+
+func (c *clientImpl) OnSequenceGap(h SequenceGapHandler) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.gapHandlers = append(c.gapHandlers, h)
+}
+
+func (c *clientImpl) OnReconnect(h ReconnectHandler) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.reconnectHandlers = append(c.reconnectHandlers, h)
+}
+
+func (c *clientImpl) OnStateChange(h StateHandler) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.stateHandlers = append(c.stateHandlers, h)
+}
+
+func (c *clientImpl) State() ConnectionState {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.state
+}
+
+func (c *clientImpl) LastSequence() int64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.lastSequence
+}
+
+func (c *clientImpl) LastHeartbeat() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.lastHeartbeat
+}
+
+func (c *clientImpl) setState(state ConnectionState) {
+	c.mu.Lock()
+	if c.state == state {
+		c.mu.Unlock()
+		return
+	}
+	c.state = state
+	handlers := c.stateHandlers
+	c.mu.Unlock()
+
+	for _, h := range handlers {
+		h(state)
+	}
+}
+
+func (c *clientImpl) Connect(ctx context.Context) error {
+
+	conn, err := core.DialWebSocket(ctx, core.DefaultDialerConfig(c.url))
+	if err != nil {
+		return fmt.Errorf("unable to dial websocket: %s - err: %w", c.url, err)
+	}
+
+	conn.SetReadLimit(defaultReadLimit)
+	c.extendDeadline(conn)
+
+	c.mu.Lock()
+	c.conn = conn
+	c.lastSequence = -1
+	c.mu.Unlock()
+
+	c.setState(ConnectionStateConnected)
+
+	return nil
+}
+
+// Run reconnects with exponential backoff and full jitter. The backoff is
+// reset once a connection is established.
+func (c *clientImpl) Run(ctx context.Context) error {
+
+	defer c.setState(ConnectionStateDisconnected)
+
+	attempt := 0
+
+	for {
+		if c.connection() == nil {
+			c.setState(ConnectionStateConnecting)
+		}
+
+		connected, err := c.runConnection(ctx)
+
+		c.dropConnection()
+
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		var serverErr *ServerError
+		if errors.As(err, &serverErr) {
+			return err
+		}
+
+		if errors.Is(err, ErrStale) {
+			c.setState(ConnectionStateStale)
+		}
+
+		if connected {
+			attempt = 0
+		}
+
+		c.setState(ConnectionStateReconnecting)
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(c.backoff(attempt)):
+		}
+
+		attempt++
+	}
+}
+
+// backoff returns a random delay between zero and the initial backoff
+// doubled for each failed attempt, capped at the max backoff.
+func (c *clientImpl) backoff(attempt int) time.Duration {
+
+	d := c.reconnectBackoff
+	for idx := 0; idx < attempt && d < c.maxReconnectBackoff; idx++ {
+		d *= 2
+	}
+
+	if d > c.maxReconnectBackoff {
+		d = c.maxReconnectBackoff
+	}
+
+	if d <= 0 {
+		return 0
+	}
+
+	return time.Duration(rand.Int63n(int64(d) + 1))
+}
+
+func (c *clientImpl) extendDeadline(conn *core.WebSocketConnection) {
+	if c.staleAfter > 0 {
+		conn.SetReadDeadline(time.Now().Add(c.staleAfter))
+	}
+}
+
+// runConnection connects, if needed, and listens until the connection
+// fails. On a new connection, the recorded subscriptions are restored and,
+// if a connection existed before, the reconnect handlers are called. The
+// returned bool reports whether a connection was established.
+func (c *clientImpl) runConnection(ctx context.Context) (bool, error) {
+
+	if c.connection() == nil {
+
+		if err := c.Connect(ctx); err != nil {
+			return false, err
+		}
+
+		for _, sub := range c.Subscriptions() {
+			if err := c.send(messageTypeSubscribe, sub); err != nil {
+				return true, fmt.Errorf("unable to restore subscription: %s - err: %w", sub.Channel, err)
+			}
+		}
+
+		c.mu.Lock()
+		reconnected := c.connectedBefore
+		handlers := c.reconnectHandlers
+		c.mu.Unlock()
+
+		if reconnected {
+			for _, h := range handlers {
+				h(ctx)
+			}
+		}
+	}
+
+	c.mu.Lock()
+	c.connectedBefore = true
+	c.mu.Unlock()
+
+	return true, c.Listen(ctx)
+}
+
+func (c *clientImpl) dropConnection() {
+	c.mu.Lock()
+	conn := c.conn
+	c.conn = nil
+	c.mu.Unlock()
+
+	if conn != nil {
+		conn.Close()
+	}
+}
+
+// Subscribe records the subscription, so it is restored by Run after a
+// reconnect, and sends it if the client is connected.
+func (c *clientImpl) Subscribe(ctx context.Context, sub *Subscription) error {
+
+	c.mu.Lock()
+	c.subscriptions[sub.key()] = sub
+	c.mu.Unlock()
+
+	if c.connection() == nil {
+		return nil
+	}
+
+	if err := c.send(messageTypeSubscribe, sub); err != nil {
+		return fmt.Errorf("unable to subscribe to channel: %s - err: %w", sub.Channel, err)
+	}
+
+	return nil
+}
+
+func (c *clientImpl) Unsubscribe(ctx context.Context, sub *Subscription) error {
+
+	c.mu.Lock()
+	delete(c.subscriptions, sub.key())
+	c.mu.Unlock()
+
+	if c.connection() == nil {
+		return nil
+	}
+
+	if err := c.send(messageTypeUnsubscribe, sub); err != nil {
+		return fmt.Errorf("unable to unsubscribe from channel: %s - err: %w", sub.Channel, err)
+	}
+
+	return nil
+}
+
+func (c *clientImpl) Subscriptions() []*Subscription {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	subs := make([]*Subscription, 0, len(c.subscriptions))
+	for _, s := range c.subscriptions {
+		subs = append(subs, s)
+	}
+	return subs
+}
+
+// send signs and writes a subscribe or unsubscribe message. The signature
+// covers the channel, key ids, timestamp, portfolio and products, using the
+// same HMAC scheme as REST requests.
+func (c *clientImpl) send(messageType string, sub *Subscription) error {
+
+	conn := c.connection()
+	if conn == nil {
+		return errors.New("not connected")
+	}
+
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+
+	signature := client.SignMessage(
+		c.credentials.SigningKey,
+		sub.Channel+c.credentials.AccessKey+c.credentials.SvcAccountId+timestamp+sub.PortfolioId+strings.Join(sub.ProductIds, ""),
+	)
+
+	b, err := json.Marshal(&subscribeMessage{
+		Type:        messageType,
+		Channel:     sub.Channel,
+		AccessKey:   c.credentials.AccessKey,
+		ApiKeyId:    c.credentials.SvcAccountId,
+		Timestamp:   timestamp,
+		Passphrase:  c.credentials.Passphrase,
+		Signature:   signature,
+		PortfolioId: sub.PortfolioId,
+		ProductIds:  sub.ProductIds,
+	})
+	if err != nil {
+		return err
+	}
+
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+
+	return conn.WriteMessage(core.WebSocketTextMessage, b)
+}
+
+func (c *clientImpl) Listen(ctx context.Context) error {
+
+	conn := c.connection()
+	if conn == nil {
+		return errors.New("not connected")
+	}
+
+	done := make(chan struct{})
+	defer close(done)
+
+	go func() {
+		select {
+		case <-ctx.Done():
+			conn.Close()
+		case <-done:
+		}
+	}()
+
+	var handlerErr error
+
+	// Handlers, e.g., an orders resync on a reconnect or a sequence gap, can
+	// take longer than the stale timeout, so the deadline is extended after
+	// they return, not only when a message is received
+	c.extendDeadline(conn)
+
+	err := core.ListenForWebSocketTextMessages(conn, func(b []byte) {
+		c.extendDeadline(conn)
+		if handlerErr == nil {
+			handlerErr = c.dispatch(b)
+		}
+		if handlerErr != nil {
+			conn.Close()
+			return
+		}
+		c.extendDeadline(conn)
+	})
+
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+
+	if handlerErr != nil {
+		return handlerErr
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return fmt.Errorf("no message received in %s - err: %w", c.staleAfter, ErrStale)
+	}
+
+	return err
+}
+
+func (c *clientImpl) dispatch(b []byte) error {
+
+	msg := &Message{}
+	if err := json.Unmarshal(b, msg); err != nil {
+		return fmt.Errorf("unable to deserialize message: %w", err)
+	}
+
+	if msg.Type == messageTypeError {
+		return &ServerError{Message: msg.Message}
+	}
+
+	c.mu.Lock()
+	expected := c.lastSequence + 1
+	gap := c.lastSequence >= 0 && msg.SequenceNum != expected
+	c.lastSequence = msg.SequenceNum
+	gapHandlers := c.gapHandlers
+	handler := c.handlers[msg.Channel]
+	if msg.Channel == ChannelHeartbeats {
+		c.lastHeartbeat = time.Now()
+	}
+	c.mu.Unlock()
+
+	if gap {
+		for _, h := range gapHandlers {
+			h(expected, msg.SequenceNum)
+		}
+	}
+
+	if handler != nil {
+		handler(msg)
+	}
+
+	return nil
+}
+
+func (c *clientImpl) connection() *core.WebSocketConnection {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.conn
+}
+
+func (c *clientImpl) Close() error {
+	conn := c.connection()
+	if conn == nil {
+		return nil
+	}
+
+	c.writeMu.Lock()
+	core.WriteWebSocketCloseMessge(conn)
+	c.writeMu.Unlock()
+
+	return conn.Close()
+}
@@ -0,0 +1,224 @@
+/**
+ * Copyright 2024-present Coinbase Global, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package websocket
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/coinbase-samples/prime-sdk-go/credentials"
+)
+
+type stateRecorder struct {
+	mu     sync.Mutex
+	states []ConnectionState
+	ch     chan ConnectionState
+}
+
+func newStateRecorder() *stateRecorder {
+	return &stateRecorder{ch: make(chan ConnectionState, 100)}
+}
+
+func (r *stateRecorder) handle(state ConnectionState) {
+	r.mu.Lock()
+	r.states = append(r.states, state)
+	r.mu.Unlock()
+	r.ch <- state
+}
+
+func (r *stateRecorder) waitFor(ctx context.Context, t *testing.T, state ConnectionState, count int) {
+	t.Helper()
+	seen := 0
+	for seen < count {
+		select {
+		case s := <-r.ch:
+			if s == state {
+				seen++
+			}
+		case <-ctx.Done():
+			t.Fatalf("timed out waiting for state: %s", state)
+		}
+	}
+}
+
+// The server drops the first connection and skips a sequence number on the
+// second. Run must reconnect, restore the subscription and report the gap.
+func TestRunReconnect(t *testing.T) {
+
+	server := newTestServer(t, func(c *testConn) {
+
+		sub := c.readSubscribe()
+		if sub == nil || sub.Channel != ChannelHeartbeats || sub.PortfolioId != "p1" {
+			t.Errorf("unexpected subscribe message: %+v", sub)
+			return
+		}
+
+		c.write(ChannelHeartbeats, 0, []*HeartbeatEvent{{HeartbeatCounter: 1}})
+		c.write(ChannelHeartbeats, 1, []*HeartbeatEvent{{HeartbeatCounter: 2}})
+		c.write(ChannelHeartbeats, 3, []*HeartbeatEvent{{HeartbeatCounter: 4}})
+
+		// Dropping the connection after the writes, without a close message
+	})
+
+	ws := NewClient(&credentials.Credentials{}).
+		SetUrl(server.url()).
+		SetReconnectBackoff(10*time.Millisecond, 50*time.Millisecond)
+
+	recorder := newStateRecorder()
+	ws.OnStateChange(recorder.handle)
+
+	gaps := make(chan [2]int64, 10)
+	ws.OnSequenceGap(func(expected, received int64) {
+		gaps <- [2]int64{expected, received}
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	if err := ws.Subscribe(ctx, HeartbeatSubscription("p1")); err != nil {
+		t.Fatal(err)
+	}
+
+	done := make(chan error)
+	go func() { done <- ws.Run(ctx) }()
+
+	recorder.waitFor(ctx, t, ConnectionStateConnected, 2)
+
+	if server.connectionCount() < 2 {
+		t.Errorf("expected at least 2 connections - received: %d", server.connectionCount())
+	}
+
+	select {
+	case gap := <-gaps:
+		if gap[0] != 2 || gap[1] != 3 {
+			t.Errorf("expected gap: [2 3] - received: %v", gap)
+		}
+	case <-ctx.Done():
+		t.Fatal("timed out waiting for the sequence gap")
+	}
+
+	if ws.LastHeartbeat().IsZero() {
+		t.Error("expected a heartbeat")
+	}
+
+	cancel()
+
+	<-done
+
+	if ws.State() != ConnectionStateDisconnected {
+		t.Errorf("expected state: %s - received: %s", ConnectionStateDisconnected, ws.State())
+	}
+
+	recorder.mu.Lock()
+	defer recorder.mu.Unlock()
+
+	expected := []ConnectionState{
+		ConnectionStateConnecting,
+		ConnectionStateConnected,
+		ConnectionStateReconnecting,
+		ConnectionStateConnecting,
+		ConnectionStateConnected,
+	}
+
+	for idx, state := range expected {
+		if recorder.states[idx] != state {
+			t.Fatalf("expected states: %v - received: %v", expected, recorder.states)
+		}
+	}
+}
+
+// The server goes silent after one message. The watchdog must declare the
+// connection stale and Run must reconnect.
+func TestRunStale(t *testing.T) {
+
+	server := newTestServer(t, func(c *testConn) {
+
+		if sub := c.readSubscribe(); sub == nil {
+			return
+		}
+
+		c.write(ChannelHeartbeats, 0, []*HeartbeatEvent{{HeartbeatCounter: 1}})
+
+		// Silent until the client closes the connection
+		for c.readSubscribe() != nil {
+		}
+	})
+
+	ws := NewClient(&credentials.Credentials{}).
+		SetUrl(server.url()).
+		SetStaleAfter(100*time.Millisecond).
+		SetReconnectBackoff(10*time.Millisecond, 50*time.Millisecond)
+
+	recorder := newStateRecorder()
+	ws.OnStateChange(recorder.handle)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	if err := ws.Subscribe(ctx, HeartbeatSubscription("p1")); err != nil {
+		t.Fatal(err)
+	}
+
+	go ws.Run(ctx)
+
+	recorder.waitFor(ctx, t, ConnectionStateStale, 1)
+	recorder.waitFor(ctx, t, ConnectionStateConnected, 1)
+
+	if server.connectionCount() < 2 {
+		t.Errorf("expected at least 2 connections - received: %d", server.connectionCount())
+	}
+}
+
+func TestBackoff(t *testing.T) {
+
+	c := NewClient(&credentials.Credentials{}).
+		SetReconnectBackoff(100*time.Millisecond, time.Second).(*clientImpl)
+
+	cases := []struct {
+		description string
+		attempt     int
+		max         time.Duration
+	}{
+		{
+			description: "TestBackoff0",
+			attempt:     0,
+			max:         100 * time.Millisecond,
+		},
+		{
+			description: "TestBackoff1",
+			attempt:     2,
+			max:         400 * time.Millisecond,
+		},
+		{
+			description: "TestBackoff2",
+			attempt:     10,
+			max:         time.Second,
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.description, func(t *testing.T) {
+			for idx := 0; idx < 100; idx++ {
+				if d := c.backoff(tt.attempt); d < 0 || d > tt.max {
+					t.Fatalf("test: %s - expected: <= %v - received: %v", tt.description, tt.max, d)
+				}
+			}
+		})
+	}
+}